@@ -4,8 +4,10 @@ go 1.26.0
 
 require (
 	github.com/fumiama/go-docx v0.0.0-20250506085032-0c30fd09304b
+	github.com/jung-kurt/gofpdf v1.16.2
 	golang.org/x/net v0.56.0
 	golang.org/x/text v0.38.0
+	golang.org/x/time v0.15.0
 )
 
 require (
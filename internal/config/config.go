@@ -4,8 +4,10 @@ package config
 
 import (
 	"log/slog"
+	"math"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,6 +16,47 @@ type Config struct {
 	// Parser configuration
 	BaseURL        string
 	RequestTimeout time.Duration
+	MaxPayloadSize int64 // maximum course data size in bytes; 0 disables the limit
+
+	// Retry configuration for transient fetch failures (5xx, 429, network errors)
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+
+	// Rate limiting shared across all fetches; a RateLimit of zero disables it
+	RateLimit float64 // requests per second
+	RateBurst int
+
+	// ShareHosts maps additional accepted share URL hosts to the API base URL
+	// serving them; an empty base URL uses BaseURL
+	ShareHosts map[string]string
+
+	// Authentication for private or password-protected share links
+	AuthToken  string   // sent as a bearer token
+	Headers    []string // extra request headers as "Name: value"
+	Cookies    string   // cookies as "name=value; name2=value2"
+	CookieFile string   // path to a Netscape cookie file
+
+	// Cache configuration; an empty CacheDir disables the on-disk course cache
+	CacheDir string
+	Offline  bool // serve courses strictly from the cache
+
+	// Strict fails loading when the course JSON has unknown fields or items
+	Strict bool
+
+	// Markdown export customization
+	MarkdownFlavor string // "gfm", "commonmark" or "plain"
+
+	// HTML export customization; empty paths use the built-in templates and stylesheet
+	TemplateDir string // directory of *.gohtml files overriding named templates
+	Stylesheet  string // CSS file replacing the built-in stylesheet
+	Theme       string // "default" or "course"
+	TrustHTML   bool   // embed course HTML without sanitizing it
+
+	// DOCX export customization
+	ReferenceDoc string // .docx or .dotx whose styles, headers, footers and page setup are used
+	TOC          bool   // insert a table of contents after the title
+	MediaDir     string // directory searched for images by file name before downloading them
 
 	// Logging configuration
 	LogLevel  slog.Level
@@ -24,6 +67,19 @@ type Config struct {
 const (
 	DefaultBaseURL        = "https://rise.articulate.com"
 	DefaultRequestTimeout = 30 * time.Second
+	DefaultMaxPayloadSize = 100 << 20 // 100 MiB
+	DefaultMaxRetries     = 3
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	DefaultRetryMaxDelay  = 30 * time.Second
+	DefaultRateLimit      = 0
+	DefaultRateBurst      = 1
+	DefaultCacheDir       = ""
+	DefaultOffline        = false
+	DefaultStrict         = false
+	DefaultMarkdownFlavor = "gfm"
+	DefaultTheme          = "default"
+	DefaultTrustHTML      = false
+	DefaultTOC            = false
 	DefaultLogLevel       = slog.LevelInfo
 	DefaultLogFormat      = "text"
 )
@@ -34,6 +90,28 @@ func Load() *Config {
 	return &Config{
 		BaseURL:        getEnv("ARTICULATE_BASE_URL", DefaultBaseURL),
 		RequestTimeout: getDurationEnv("ARTICULATE_REQUEST_TIMEOUT", DefaultRequestTimeout),
+		MaxPayloadSize: getSizeEnv("ARTICULATE_MAX_PAYLOAD_SIZE", DefaultMaxPayloadSize),
+		ShareHosts:     getHostMapEnv("ARTICULATE_SHARE_HOSTS"),
+		AuthToken:      getEnv("ARTICULATE_AUTH_TOKEN", ""),
+		Headers:        getLinesEnv("ARTICULATE_HEADERS"),
+		Cookies:        getEnv("ARTICULATE_COOKIES", ""),
+		CookieFile:     getEnv("ARTICULATE_COOKIE_FILE", ""),
+		MaxRetries:     getIntEnv("ARTICULATE_MAX_RETRIES", DefaultMaxRetries),
+		RetryBaseDelay: getDurationEnv("ARTICULATE_RETRY_BASE_DELAY", DefaultRetryBaseDelay),
+		RetryMaxDelay:  getDurationEnv("ARTICULATE_RETRY_MAX_DELAY", DefaultRetryMaxDelay),
+		RateLimit:      getFloatEnv("ARTICULATE_RATE_LIMIT", DefaultRateLimit),
+		RateBurst:      getIntEnv("ARTICULATE_RATE_BURST", DefaultRateBurst),
+		CacheDir:       getEnv("ARTICULATE_CACHE_DIR", DefaultCacheDir),
+		Offline:        getBoolEnv("ARTICULATE_OFFLINE", DefaultOffline),
+		Strict:         getBoolEnv("ARTICULATE_STRICT", DefaultStrict),
+		MarkdownFlavor: getEnv("ARTICULATE_MARKDOWN_FLAVOR", DefaultMarkdownFlavor),
+		TemplateDir:    getEnv("ARTICULATE_TEMPLATE_DIR", ""),
+		Stylesheet:     getEnv("ARTICULATE_STYLESHEET", ""),
+		Theme:          getEnv("ARTICULATE_THEME", DefaultTheme),
+		TrustHTML:      getBoolEnv("ARTICULATE_TRUST_HTML", DefaultTrustHTML),
+		ReferenceDoc:   getEnv("ARTICULATE_REFERENCE_DOC", ""),
+		TOC:            getBoolEnv("ARTICULATE_TOC", DefaultTOC),
+		MediaDir:       getEnv("ARTICULATE_MEDIA_DIR", ""),
 		LogLevel:       getLogLevelEnv("LOG_LEVEL", DefaultLogLevel),
 		LogFormat:      getEnv("LOG_FORMAT", DefaultLogFormat),
 	}
@@ -48,16 +126,112 @@ func getEnv(key, defaultValue string) string {
 }
 
 // getDurationEnv retrieves a duration from environment variable or returns default.
-// The environment variable should be in seconds (e.g., "30" for 30 seconds).
+// The environment variable should be in seconds (e.g., "30" for 30 seconds), or a
+// Go duration string for sub-second precision (e.g., "250ms").
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if seconds, err := strconv.Atoi(value); err == nil {
 			return time.Duration(seconds) * time.Second
 		}
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
 	}
 	return defaultValue
 }
 
+// getIntEnv retrieves an integer from environment variable or returns default.
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+// getSizeEnv retrieves a size in bytes from environment variable or returns default.
+// The value is a number of bytes, optionally followed by a K, M or G suffix for
+// KiB, MiB or GiB (e.g., "512K" or "200MB"). Negative or invalid values use the default.
+func getSizeEnv(key string, defaultValue int64) int64 {
+	value := strings.ToUpper(strings.TrimSpace(os.Getenv(key)))
+	if value == "" {
+		return defaultValue
+	}
+
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "B"), "I")
+	shift := 0
+	switch {
+	case strings.HasSuffix(value, "K"):
+		shift = 10
+	case strings.HasSuffix(value, "M"):
+		shift = 20
+	case strings.HasSuffix(value, "G"):
+		shift = 30
+	}
+	if shift > 0 {
+		value = value[:len(value)-1]
+	}
+
+	size, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || size < 0 || size > math.MaxInt64>>shift {
+		return defaultValue
+	}
+	return size << shift
+}
+
+// getFloatEnv retrieves a floating-point number from environment variable or returns default.
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
+
+// getBoolEnv retrieves a boolean from environment variable or returns default.
+// Accepts the values understood by strconv.ParseBool (e.g., "true", "1", "false", "0").
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+// getHostMapEnv retrieves a comma-separated list of hosts from environment variable.
+// Each entry is either a host (e.g., "rise.example.com") or a host mapped to an
+// API base URL (e.g., "localhost:8080=http://localhost:9000"). Returns nil if unset.
+func getHostMapEnv(key string) map[string]string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	hosts := make(map[string]string)
+	for entry := range strings.SplitSeq(value, ",") {
+		host, baseURL, _ := strings.Cut(strings.TrimSpace(entry), "=")
+		if host = strings.TrimSpace(host); host != "" {
+			hosts[strings.ToLower(host)] = strings.TrimSpace(baseURL)
+		}
+	}
+	return hosts
+}
+
+// getLinesEnv retrieves the non-empty lines of an environment variable.
+// Returns nil if the variable is unset.
+func getLinesEnv(key string) []string {
+	var lines []string
+	for line := range strings.Lines(os.Getenv(key)) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
 // getLogLevelEnv retrieves a log level from environment variable or returns default.
 // Accepts: "debug", "info", "warn", "error" (case-insensitive).
 func getLogLevelEnv(key string, defaultValue slog.Level) slog.Level {
@@ -21,6 +21,10 @@ func TestLoad(t *testing.T) {
 		t.Errorf("Expected timeout %v, got %v", DefaultRequestTimeout, cfg.RequestTimeout)
 	}
 
+	if cfg.MaxPayloadSize != DefaultMaxPayloadSize {
+		t.Errorf("Expected max payload size %d, got %d", DefaultMaxPayloadSize, cfg.MaxPayloadSize)
+	}
+
 	if cfg.LogLevel != DefaultLogLevel {
 		t.Errorf("Expected log level %v, got %v", DefaultLogLevel, cfg.LogLevel)
 	}
@@ -97,6 +101,7 @@ func TestGetDurationEnv(t *testing.T) {
 	}{
 		{"valid duration", "45", 45 * time.Second},
 		{"zero duration", "0", 0},
+		{"go duration string", "250ms", 250 * time.Millisecond},
 		{"invalid duration", "invalid", 30 * time.Second},
 		{"empty value", "", 30 * time.Second},
 	}
@@ -114,3 +119,241 @@ func TestGetDurationEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSizeEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected int64
+	}{
+		{"bytes", "1048576", 1 << 20},
+		{"zero disables", "0", 0},
+		{"kibibytes", "512K", 512 << 10},
+		{"megabytes with unit", "200MB", 200 << 20},
+		{"gibibytes lower case", "2gib", 2 << 30},
+		{"negative", "-1", 100},
+		{"overflow", "9999999999999G", 100},
+		{"invalid", "lots", 100},
+		{"empty value", "", 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_SIZE", tt.value)
+			if result := getSizeEnv("TEST_SIZE", 100); result != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestLoad_RetryAndRateLimit(t *testing.T) {
+	os.Clearenv()
+
+	cfg := Load()
+	if cfg.MaxRetries != DefaultMaxRetries {
+		t.Errorf("Expected max retries %d, got %d", DefaultMaxRetries, cfg.MaxRetries)
+	}
+	if cfg.RetryBaseDelay != DefaultRetryBaseDelay {
+		t.Errorf("Expected retry base delay %v, got %v", DefaultRetryBaseDelay, cfg.RetryBaseDelay)
+	}
+	if cfg.RateLimit != DefaultRateLimit {
+		t.Errorf("Expected rate limit %v, got %v", DefaultRateLimit, cfg.RateLimit)
+	}
+
+	t.Setenv("ARTICULATE_MAX_RETRIES", "5")
+	t.Setenv("ARTICULATE_RETRY_BASE_DELAY", "100ms")
+	t.Setenv("ARTICULATE_RETRY_MAX_DELAY", "10")
+	t.Setenv("ARTICULATE_RATE_LIMIT", "2.5")
+	t.Setenv("ARTICULATE_RATE_BURST", "4")
+
+	cfg = Load()
+	if cfg.MaxRetries != 5 {
+		t.Errorf("Expected max retries 5, got %d", cfg.MaxRetries)
+	}
+	if cfg.RetryBaseDelay != 100*time.Millisecond {
+		t.Errorf("Expected retry base delay 100ms, got %v", cfg.RetryBaseDelay)
+	}
+	if cfg.RetryMaxDelay != 10*time.Second {
+		t.Errorf("Expected retry max delay 10s, got %v", cfg.RetryMaxDelay)
+	}
+	if cfg.RateLimit != 2.5 {
+		t.Errorf("Expected rate limit 2.5, got %v", cfg.RateLimit)
+	}
+	if cfg.RateBurst != 4 {
+		t.Errorf("Expected rate burst 4, got %d", cfg.RateBurst)
+	}
+}
+
+func TestLoad_Cache(t *testing.T) {
+	os.Clearenv()
+
+	cfg := Load()
+	if cfg.CacheDir != DefaultCacheDir {
+		t.Errorf("Expected cache dir '%s', got '%s'", DefaultCacheDir, cfg.CacheDir)
+	}
+	if cfg.Offline {
+		t.Error("Expected offline mode to be disabled by default")
+	}
+
+	t.Setenv("ARTICULATE_CACHE_DIR", "/tmp/articulate-cache")
+	t.Setenv("ARTICULATE_OFFLINE", "true")
+
+	cfg = Load()
+	if cfg.CacheDir != "/tmp/articulate-cache" {
+		t.Errorf("Expected cache dir '/tmp/articulate-cache', got '%s'", cfg.CacheDir)
+	}
+	if !cfg.Offline {
+		t.Error("Expected offline mode to be enabled")
+	}
+
+	t.Setenv("ARTICULATE_OFFLINE", "maybe")
+	if cfg = Load(); cfg.Offline != DefaultOffline {
+		t.Errorf("Expected invalid offline value to fall back to %v, got %v", DefaultOffline, cfg.Offline)
+	}
+}
+
+func TestLoad_Strict(t *testing.T) {
+	os.Clearenv()
+
+	if cfg := Load(); cfg.Strict {
+		t.Error("Expected strict mode to be disabled by default")
+	}
+
+	t.Setenv("ARTICULATE_STRICT", "1")
+	if cfg := Load(); !cfg.Strict {
+		t.Error("Expected strict mode to be enabled")
+	}
+}
+
+func TestLoad_MarkdownFlavor(t *testing.T) {
+	os.Clearenv()
+
+	cfg := Load()
+	if cfg.MarkdownFlavor != DefaultMarkdownFlavor {
+		t.Errorf("Expected Markdown flavor '%s', got '%s'", DefaultMarkdownFlavor, cfg.MarkdownFlavor)
+	}
+
+	t.Setenv("ARTICULATE_MARKDOWN_FLAVOR", "commonmark")
+
+	cfg = Load()
+	if cfg.MarkdownFlavor != "commonmark" {
+		t.Errorf("Expected Markdown flavor 'commonmark', got '%s'", cfg.MarkdownFlavor)
+	}
+}
+
+func TestLoad_HTMLOptions(t *testing.T) {
+	os.Clearenv()
+
+	cfg := Load()
+	if cfg.TemplateDir != "" || cfg.Stylesheet != "" {
+		t.Errorf("Expected no template dir or stylesheet by default, got '%s' and '%s'", cfg.TemplateDir, cfg.Stylesheet)
+	}
+	if cfg.Theme != DefaultTheme {
+		t.Errorf("Expected theme '%s', got '%s'", DefaultTheme, cfg.Theme)
+	}
+	if cfg.TrustHTML {
+		t.Error("Expected course HTML to be sanitized by default")
+	}
+
+	t.Setenv("ARTICULATE_TEMPLATE_DIR", "/tmp/templates")
+	t.Setenv("ARTICULATE_STYLESHEET", "/tmp/brand.css")
+	t.Setenv("ARTICULATE_THEME", "course")
+	t.Setenv("ARTICULATE_TRUST_HTML", "true")
+
+	cfg = Load()
+	if cfg.TemplateDir != "/tmp/templates" {
+		t.Errorf("Expected template dir '/tmp/templates', got '%s'", cfg.TemplateDir)
+	}
+	if cfg.Stylesheet != "/tmp/brand.css" {
+		t.Errorf("Expected stylesheet '/tmp/brand.css', got '%s'", cfg.Stylesheet)
+	}
+	if cfg.Theme != "course" {
+		t.Errorf("Expected theme 'course', got '%s'", cfg.Theme)
+	}
+	if !cfg.TrustHTML {
+		t.Error("Expected course HTML to be trusted")
+	}
+}
+
+func TestLoad_DocxOptions(t *testing.T) {
+	os.Clearenv()
+
+	cfg := Load()
+	if cfg.ReferenceDoc != "" {
+		t.Errorf("Expected no reference document by default, got '%s'", cfg.ReferenceDoc)
+	}
+	if cfg.TOC {
+		t.Error("Expected the table of contents to be disabled by default")
+	}
+	if cfg.MediaDir != "" {
+		t.Errorf("Expected no media directory by default, got '%s'", cfg.MediaDir)
+	}
+
+	t.Setenv("ARTICULATE_REFERENCE_DOC", "/tmp/brand.dotx")
+	t.Setenv("ARTICULATE_TOC", "true")
+	t.Setenv("ARTICULATE_MEDIA_DIR", "/tmp/media")
+
+	cfg = Load()
+	if cfg.ReferenceDoc != "/tmp/brand.dotx" {
+		t.Errorf("Expected reference document '/tmp/brand.dotx', got '%s'", cfg.ReferenceDoc)
+	}
+	if !cfg.TOC {
+		t.Error("Expected the table of contents to be enabled")
+	}
+	if cfg.MediaDir != "/tmp/media" {
+		t.Errorf("Expected media directory '/tmp/media', got '%s'", cfg.MediaDir)
+	}
+}
+
+func TestGetHostMapEnv(t *testing.T) {
+	t.Setenv("TEST_HOSTS", "")
+	if hosts := getHostMapEnv("TEST_HOSTS"); hosts != nil {
+		t.Errorf("Expected nil for unset variable, got %v", hosts)
+	}
+
+	t.Setenv("TEST_HOSTS", " Rise.Example.com , localhost:8080=http://localhost:9000,, eu.example.com= https://eu-api.example.com ")
+	hosts := getHostMapEnv("TEST_HOSTS")
+
+	expected := map[string]string{
+		"rise.example.com": "",
+		"localhost:8080":   "http://localhost:9000",
+		"eu.example.com":   "https://eu-api.example.com",
+	}
+	if len(hosts) != len(expected) {
+		t.Fatalf("Expected %d hosts, got %v", len(expected), hosts)
+	}
+	for host, baseURL := range expected {
+		if got, ok := hosts[host]; !ok || got != baseURL {
+			t.Errorf("Expected host '%s' to map to '%s', got '%s' (present: %v)", host, baseURL, got, ok)
+		}
+	}
+}
+
+func TestLoad_Auth(t *testing.T) {
+	os.Clearenv()
+
+	cfg := Load()
+	if cfg.AuthToken != "" || cfg.Headers != nil || cfg.Cookies != "" || cfg.CookieFile != "" {
+		t.Errorf("Expected no credentials by default, got %+v", cfg)
+	}
+
+	t.Setenv("ARTICULATE_AUTH_TOKEN", "token-value")
+	t.Setenv("ARTICULATE_HEADERS", "X-Review-Session: abc\n\n  X-Tenant: acme  \n")
+	t.Setenv("ARTICULATE_COOKIES", "session=xyz")
+	t.Setenv("ARTICULATE_COOKIE_FILE", "/tmp/cookies.txt")
+
+	cfg = Load()
+	if cfg.AuthToken != "token-value" {
+		t.Errorf("Expected auth token 'token-value', got '%s'", cfg.AuthToken)
+	}
+	if len(cfg.Headers) != 2 || cfg.Headers[0] != "X-Review-Session: abc" || cfg.Headers[1] != "X-Tenant: acme" {
+		t.Errorf("Expected two headers, got %q", cfg.Headers)
+	}
+	if cfg.Cookies != "session=xyz" {
+		t.Errorf("Expected cookies 'session=xyz', got '%s'", cfg.Cookies)
+	}
+	if cfg.CookieFile != "/tmp/cookies.txt" {
+		t.Errorf("Expected cookie file '/tmp/cookies.txt', got '%s'", cfg.CookieFile)
+	}
+}
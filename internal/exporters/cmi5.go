@@ -0,0 +1,368 @@
+package exporters
+
+import (
+	"archive/zip"
+	"bytes"
+	_ "embed"
+	"fmt"
+	stdhtml "html"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/kjanat/articulate-parser/internal/interfaces"
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+//go:embed cmi5_structure.gotmpl
+var cmi5StructureTemplate string
+
+//go:embed cmi5_page.gohtml
+var cmi5PageTemplate string
+
+//go:embed cmi5_runtime.js
+var cmi5Runtime string
+
+// Files shared by all AUs of a cmi5 package.
+const (
+	cmi5Structure  = "cmi5.xml"
+	cmi5Stylesheet = "styles.css"
+	cmi5Script     = "cmi5.js"
+)
+
+// xAPI identifiers used in statements about knowledge checks.
+const (
+	xapiVerbAnswered      = "http://adlnet.gov/expapi/verbs/answered"
+	xapiObjectActivity    = "Activity"
+	xapiInteractionType   = "http://adlnet.gov/expapi/activities/cmi.interaction"
+	xapiLanguage          = "en-US"
+	xapiInteractionChoice = "choice"
+	xapiInteractionFillIn = "fill-in"
+	xapiInteractionMatch  = "matching"
+	xapiResponseDelimiter = "[,]"
+	xapiMatchDelimiter    = "[.]"
+	xapiCaseInsensitive   = "{case_matters=false}"
+)
+
+// CMI5Exporter implements the Exporter interface for cmi5 course packages.
+// It writes a zip with a cmi5.xml course structure and one assignable unit
+// (AU) per lesson. Lesson pages are rendered with the HTML exporter's
+// templates; knowledge checks become forms whose answers are sent to the LRS
+// as xAPI "answered" statements.
+type CMI5Exporter struct {
+	// htmlCleaner is passed to the HTML templates and cleans question text
+	htmlCleaner *services.HTMLCleaner
+	// page renders lesson pages; it extends the HTML exporter's templates
+	page *htmltemplate.Template
+	// structure renders cmi5.xml
+	structure *template.Template
+}
+
+// cmi5Node is a block or AU of the course structure. Sections become blocks
+// holding the AUs of their lessons as Children.
+type cmi5Node struct {
+	ID          string
+	Title       string
+	Description string
+	Href        string
+	Children    []cmi5Node
+}
+
+// cmi5StructureData is the data passed to the course structure template.
+type cmi5StructureData struct {
+	ID          string
+	Title       string
+	Description string
+	Nodes       []cmi5Node
+}
+
+// cmi5PageData is the data passed to the lesson page template.
+type cmi5PageData struct {
+	CourseTitle string
+	Lesson      templateSection
+	// Questions holds an answered statement template for every question of
+	// the lesson's knowledge checks, in document order
+	Questions []xapiStatement
+}
+
+// xapiStatement is the part of an xAPI statement the exporter knows in
+// advance. The runtime script adds the actor, result and launch context.
+type xapiStatement struct {
+	Verb   xapiVerb     `json:"verb"`
+	Object xapiActivity `json:"object"`
+}
+
+// xapiVerb is the verb of an xAPI statement.
+type xapiVerb struct {
+	ID      string            `json:"id"`
+	Display map[string]string `json:"display"`
+}
+
+// xapiActivity is an xAPI activity used as statement object.
+type xapiActivity struct {
+	ObjectType string                  `json:"objectType"`
+	ID         string                  `json:"id"`
+	Definition *xapiActivityDefinition `json:"definition,omitempty"`
+}
+
+// xapiActivityDefinition describes an interaction activity. The correct
+// response patterns carry the correct answers of the question.
+type xapiActivityDefinition struct {
+	Type                    string                     `json:"type"`
+	Name                    map[string]string          `json:"name"`
+	InteractionType         string                     `json:"interactionType"`
+	CorrectResponsesPattern []string                   `json:"correctResponsesPattern"`
+	Choices                 []xapiInteractionComponent `json:"choices,omitempty"`
+	Source                  []xapiInteractionComponent `json:"source,omitempty"`
+	Target                  []xapiInteractionComponent `json:"target,omitempty"`
+}
+
+// xapiInteractionComponent is a choice, matching source or matching target.
+type xapiInteractionComponent struct {
+	ID          string            `json:"id"`
+	Description map[string]string `json:"description"`
+}
+
+// NewCMI5Exporter creates a new CMI5Exporter instance.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//
+// Returns:
+//   - An implementation of the Exporter interface for cmi5 packages
+func NewCMI5Exporter(htmlCleaner *services.HTMLCleaner) interfaces.Exporter {
+	html := NewHTMLExporter(htmlCleaner).(*HTMLExporter)
+	page := htmltemplate.Must(htmltemplate.Must(html.tmpl.Clone()).Funcs(htmltemplate.FuncMap{
+		"inc": func(i int) int { return i + 1 },
+	}).Parse(cmi5PageTemplate))
+	structure := template.Must(template.New("cmi5").Funcs(template.FuncMap{
+		"esc": stdhtml.EscapeString,
+	}).Parse(cmi5StructureTemplate))
+
+	return &CMI5Exporter{
+		htmlCleaner: htmlCleaner,
+		page:        page,
+		structure:   structure,
+	}
+}
+
+// Export exports the course to a cmi5 package file.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - outputPath: The file path where the zip package will be written
+//
+// Returns:
+//   - An error if building or saving the package fails
+func (e *CMI5Exporter) Export(course *models.Course, outputPath string) error {
+	// #nosec G304 - Output path is provided by user via CLI argument, which is expected behavior
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	// Close errors are logged but not fatal, see DocxExporter.Export.
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to close output file: %v\n", err)
+		}
+	}()
+
+	return e.ExportTo(course, file)
+}
+
+// ExportTo exports the course as a cmi5 package written to w.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - w: The writer the zip package will be written to
+//
+// Returns:
+//   - An error if the course has no lessons or writing the package fails
+func (e *CMI5Exporter) ExportTo(course *models.Course, w io.Writer) error {
+	data := prepareTemplateData(course, e.htmlCleaner)
+	courseID := courseURN(course)
+	structure := cmi5StructureData{
+		ID:          courseID,
+		Title:       course.Course.Title,
+		Description: cmi5Description(e.htmlCleaner.CleanHTML(course.Course.Description), course.Course.Title),
+	}
+
+	files := map[string][]byte{
+		cmi5Stylesheet: []byte(data.CSS),
+		cmi5Script:     []byte(cmi5Runtime),
+	}
+	var pages []string
+
+	// One AU per lesson; lessons following a section are grouped in its block.
+	// data.Sections and the course lessons share their order.
+	block := -1 // index of the current section in structure.Nodes
+	sectionCounter := 0
+	for i, lesson := range data.Sections {
+		if lesson.Type == lessonTypeSection {
+			sectionCounter++
+			structure.Nodes = append(structure.Nodes, cmi5Node{
+				ID:          fmt.Sprintf("%s/sections/%d", courseID, sectionCounter),
+				Title:       lesson.Title,
+				Description: cmi5Description(e.htmlCleaner.CleanHTML(lesson.Description), lesson.Title),
+			})
+			block = len(structure.Nodes) - 1
+			continue
+		}
+
+		au := cmi5Node{
+			ID:          fmt.Sprintf("%s/lessons/%d", courseID, lesson.Number),
+			Title:       fmt.Sprintf("Lesson %d: %s", lesson.Number, lesson.Title),
+			Description: cmi5Description(e.htmlCleaner.CleanHTML(lesson.Description), lesson.Title),
+			Href:        fmt.Sprintf("lesson-%03d.html", lesson.Number),
+		}
+
+		page := cmi5PageData{
+			CourseTitle: course.Course.Title,
+			Lesson:      lesson,
+			Questions:   e.answeredStatements(au.ID, course.Course.Lessons[i].Items),
+		}
+		var buf bytes.Buffer
+		if err := e.page.ExecuteTemplate(&buf, "cmi5Page", page); err != nil {
+			return fmt.Errorf("failed to render %s: %w", au.Href, err)
+		}
+		files[au.Href] = buf.Bytes()
+		pages = append(pages, au.Href)
+
+		if block >= 0 {
+			structure.Nodes[block].Children = append(structure.Nodes[block].Children, au)
+		} else {
+			structure.Nodes = append(structure.Nodes, au)
+		}
+	}
+	if len(pages) == 0 {
+		return errNoLessons
+	}
+
+	// Blocks need at least one AU
+	nodes := structure.Nodes[:0]
+	for _, node := range structure.Nodes {
+		if node.Href != "" || len(node.Children) > 0 {
+			nodes = append(nodes, node)
+		}
+	}
+	structure.Nodes = nodes
+
+	var structureXML bytes.Buffer
+	if err := e.structure.ExecuteTemplate(&structureXML, "courseStructure", structure); err != nil {
+		return fmt.Errorf("failed to render %s: %w", cmi5Structure, err)
+	}
+	files[cmi5Structure] = structureXML.Bytes()
+
+	// The course structure goes first, followed by the shared files and the AUs in order
+	zw := zip.NewWriter(w)
+	names := append([]string{cmi5Structure, cmi5Stylesheet, cmi5Script}, pages...)
+	for _, name := range names {
+		fw, err := zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if _, err := fw.Write(files[name]); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write cmi5 package: %w", err)
+	}
+	return nil
+}
+
+// SupportedFormat returns the format name this exporter supports.
+//
+// Returns:
+//   - A string representing the supported format ("cmi5")
+func (e *CMI5Exporter) SupportedFormat() string {
+	return FormatCMI5
+}
+
+// answeredStatements builds the answered statement templates for the
+// knowledge check questions in items. Questions are numbered within the AU
+// and follow the order in which the page template renders their forms, so
+// the runtime script can pair each form with its statement.
+func (e *CMI5Exporter) answeredStatements(auID string, items []models.Item) []xapiStatement {
+	var statements []xapiStatement
+	for _, item := range items {
+		if strings.ToLower(item.Type) != itemTypeKnowledgeCheck {
+			continue
+		}
+
+		questionType := ""
+		if details := services.ItemDetails(&item); details.KnowledgeCheck != nil {
+			questionType = details.KnowledgeCheck.QuestionType
+		}
+		for _, subItem := range item.Items {
+			if len(subItem.Answers) == 0 {
+				continue
+			}
+			definition := xapiInteraction(questionType, subItem.Answers)
+			definition.Name = map[string]string{xapiLanguage: e.htmlCleaner.CleanHTML(subItem.Title)}
+			statements = append(statements, xapiStatement{
+				Verb: xapiVerb{ID: xapiVerbAnswered, Display: map[string]string{xapiLanguage: "answered"}},
+				Object: xapiActivity{
+					ObjectType: xapiObjectActivity,
+					ID:         fmt.Sprintf("%s/questions/%d", auID, len(statements)+1),
+					Definition: definition,
+				},
+			})
+		}
+	}
+	return statements
+}
+
+// xapiInteraction describes a question as an xAPI interaction. Component IDs
+// are numbered from 1 in answer order, matching the page template:
+// "choice-N" for choices, "source-N" and "target-N" for matching pairs.
+// The correct response patterns are derived from Answer.Correct for choice
+// questions, from the accepted answers for fill-in questions and from the
+// answer pairs for matching questions.
+func xapiInteraction(questionType string, answers []models.Answer) *xapiActivityDefinition {
+	definition := &xapiActivityDefinition{Type: xapiInteractionType}
+
+	switch questionType {
+	case models.QuestionFillIn:
+		definition.InteractionType = xapiInteractionFillIn
+		for _, answer := range answers {
+			definition.CorrectResponsesPattern = append(definition.CorrectResponsesPattern, xapiCaseInsensitive+answer.Title)
+		}
+	case models.QuestionMatching:
+		definition.InteractionType = xapiInteractionMatch
+		pairs := make([]string, 0, len(answers))
+		for i, answer := range answers {
+			source := fmt.Sprintf("source-%d", i+1)
+			target := fmt.Sprintf("target-%d", i+1)
+			definition.Source = append(definition.Source, xapiInteractionComponent{ID: source, Description: map[string]string{xapiLanguage: answer.Title}})
+			definition.Target = append(definition.Target, xapiInteractionComponent{ID: target, Description: map[string]string{xapiLanguage: answer.MatchTitle}})
+			pairs = append(pairs, source+xapiMatchDelimiter+target)
+		}
+		definition.CorrectResponsesPattern = []string{strings.Join(pairs, xapiResponseDelimiter)}
+	default:
+		definition.InteractionType = xapiInteractionChoice
+		var correct []string
+		for i, answer := range answers {
+			id := fmt.Sprintf("choice-%d", i+1)
+			definition.Choices = append(definition.Choices, xapiInteractionComponent{ID: id, Description: map[string]string{xapiLanguage: answer.Title}})
+			if answer.Correct {
+				correct = append(correct, id)
+			}
+		}
+		definition.CorrectResponsesPattern = []string{strings.Join(correct, xapiResponseDelimiter)}
+	}
+
+	return definition
+}
+
+// cmi5Description returns description, or fallback when it is empty, since
+// the course structure requires a description for every course, block and AU.
+func cmi5Description(description, fallback string) string {
+	if description != "" {
+		return description
+	}
+	return fallback
+}
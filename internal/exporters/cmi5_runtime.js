@@ -0,0 +1,298 @@
+// Runs a lesson page as a cmi5 assignable unit. The LMS launches the page
+// with the LRS endpoint, auth token URL, actor, registration and activity ID
+// in the query string. The lesson is completed once its end has been
+// scrolled into view, and every submitted knowledge check is reported as an
+// xAPI "answered" statement built from the page's statement templates.
+// Without launch parameters the page still checks answers, but reports
+// nothing.
+(function () {
+  "use strict";
+
+  var xapiVersion = "1.0.3";
+  var verbs = {
+    initialized: "http://adlnet.gov/expapi/verbs/initialized",
+    completed: "http://adlnet.gov/expapi/verbs/completed",
+    terminated: "http://adlnet.gov/expapi/verbs/terminated",
+  };
+  var categories = {
+    cmi5: "https://w3id.org/xapi/cmi5/context/categories/cmi5",
+    moveOn: "https://w3id.org/xapi/cmi5/context/categories/moveon",
+  };
+  var responseDelimiter = "[,]";
+  var matchDelimiter = "[.]";
+  var caseInsensitive = "{case_matters=false}";
+
+  var params = new URLSearchParams(window.location.search);
+  var launch = {
+    endpoint: params.get("endpoint"),
+    fetch: params.get("fetch"),
+    actor: params.get("actor"),
+    registration: params.get("registration"),
+    activityId: params.get("activityId"),
+  };
+  var connected =
+    !!launch.endpoint && !!launch.fetch && !!launch.actor && !!launch.registration && !!launch.activityId;
+  if (connected) {
+    launch.actor = JSON.parse(launch.actor);
+    if (launch.endpoint.charAt(launch.endpoint.length - 1) !== "/") {
+      launch.endpoint += "/";
+    }
+  }
+
+  var started = new Date();
+  var auth = null;
+  var contextTemplate = {};
+  var launchMode = "Normal";
+  var completed = false;
+  var terminated = false;
+  // queue keeps statements in order; the first entry sets up the session
+  var queue = connected ? startSession() : Promise.resolve();
+
+  function xapiHeaders() {
+    return {
+      Authorization: auth,
+      "Content-Type": "application/json",
+      "X-Experience-API-Version": xapiVersion,
+    };
+  }
+
+  // startSession fetches the auth token and the launch data, then sends the
+  // initialized statement.
+  function startSession() {
+    return fetch(launch.fetch, { method: "POST" })
+      .then(function (response) {
+        return response.json();
+      })
+      .then(function (token) {
+        auth = "Basic " + token["auth-token"];
+        var query = new URLSearchParams({
+          stateId: "LMS.LaunchData",
+          activityId: launch.activityId,
+          agent: JSON.stringify(launch.actor),
+          registration: launch.registration,
+        });
+        return fetch(launch.endpoint + "activities/state?" + query.toString(), { headers: xapiHeaders() });
+      })
+      .then(function (response) {
+        return response.json();
+      })
+      .then(function (launchData) {
+        contextTemplate = launchData.contextTemplate || {};
+        launchMode = launchData.launchMode || launchMode;
+        return post(cmi5Statement(verbs.initialized, "initialized"));
+      })
+      .catch(function (err) {
+        connected = false;
+        console.warn("cmi5: failed to start session", err);
+      });
+  }
+
+  function uuid() {
+    if (window.crypto && window.crypto.randomUUID) {
+      return window.crypto.randomUUID();
+    }
+    return "xxxxxxxx-xxxx-4xxx-yxxx-xxxxxxxxxxxx".replace(/[xy]/g, function (c) {
+      var r = (Math.random() * 16) | 0;
+      return (c === "x" ? r : (r & 0x3) | 0x8).toString(16);
+    });
+  }
+
+  function duration() {
+    return "PT" + Math.round((new Date() - started) / 10) / 100 + "S";
+  }
+
+  // statement completes a statement with the actor, the launch context and
+  // the given context activity categories.
+  function statement(base, categoryIds) {
+    var s = JSON.parse(JSON.stringify(base));
+    s.id = uuid();
+    s.timestamp = new Date().toISOString();
+    s.actor = launch.actor;
+    s.context = JSON.parse(JSON.stringify(contextTemplate));
+    s.context.registration = launch.registration;
+    var activities = (s.context.contextActivities = s.context.contextActivities || {});
+    if (categoryIds.length > 0) {
+      activities.category = (activities.category || []).concat(
+        categoryIds.map(function (id) {
+          return { objectType: "Activity", id: id };
+        }),
+      );
+    }
+    return s;
+  }
+
+  // cmi5Statement builds a cmi5 defined statement about the AU itself.
+  function cmi5Statement(verb, display, result, categoryIds) {
+    var s = statement(
+      {
+        verb: { id: verb, display: { "en-US": display } },
+        object: { objectType: "Activity", id: launch.activityId },
+      },
+      [categories.cmi5].concat(categoryIds || []),
+    );
+    if (result) {
+      s.result = result;
+    }
+    return s;
+  }
+
+  function post(s) {
+    return fetch(launch.endpoint + "statements", {
+      method: "POST",
+      headers: xapiHeaders(),
+      body: JSON.stringify(s),
+      keepalive: true,
+    }).then(function (response) {
+      if (!response.ok) {
+        throw new Error("LRS responded " + response.status);
+      }
+    });
+  }
+
+  // send queues the statement returned by build, which runs once the session
+  // is set up and may return null to send nothing.
+  function send(build) {
+    queue = queue.then(function () {
+      var s = connected && build();
+      if (!s) {
+        return;
+      }
+      return post(s).catch(function (err) {
+        console.warn("cmi5: failed to send statement", err);
+      });
+    });
+  }
+
+  function complete() {
+    if (completed || terminated) {
+      return;
+    }
+    completed = true;
+    send(function () {
+      if (launchMode !== "Normal") {
+        return null;
+      }
+      return cmi5Statement(verbs.completed, "completed", { completion: true, duration: duration() }, [
+        categories.moveOn,
+      ]);
+    });
+  }
+
+  function terminate() {
+    if (terminated) {
+      return;
+    }
+    terminated = true;
+    send(function () {
+      return cmi5Statement(verbs.terminated, "terminated", { duration: duration() });
+    });
+  }
+
+  // sameResponse compares two responses as unordered sets of components.
+  function sameResponse(a, b) {
+    var x = a.split(responseDelimiter).sort();
+    var y = b.split(responseDelimiter).sort();
+    return x.join(responseDelimiter) === y.join(responseDelimiter);
+  }
+
+  // matches reports whether response is one of the correct responses.
+  function matches(definition, response) {
+    return (definition.correctResponsesPattern || []).some(function (pattern) {
+      if (definition.interactionType === "fill-in") {
+        if (pattern.indexOf(caseInsensitive) === 0) {
+          return pattern.slice(caseInsensitive.length).toLowerCase() === response.toLowerCase();
+        }
+        return pattern === response;
+      }
+      return sameResponse(pattern, response);
+    });
+  }
+
+  // readResponse encodes the learner's answer in the xAPI response format.
+  function readResponse(form, definition) {
+    switch (definition.interactionType) {
+      case "fill-in":
+        return form.elements.response.value.trim();
+      case "matching":
+        return definition.source
+          .map(function (source) {
+            return source.id + matchDelimiter + form.elements[source.id].value;
+          })
+          .join(responseDelimiter);
+      default:
+        return Array.prototype.filter
+          .call(form.querySelectorAll('input[name="response"]'), function (input) {
+            return input.checked;
+          })
+          .map(function (input) {
+            return input.value;
+          })
+          .join(responseDelimiter);
+    }
+  }
+
+  // shuffle reorders the match options so their order gives nothing away.
+  function shuffle(select) {
+    var options = Array.prototype.slice.call(select.options, 1);
+    for (var i = options.length - 1; i > 0; i--) {
+      var j = Math.floor(Math.random() * (i + 1));
+      var tmp = options[i];
+      options[i] = options[j];
+      options[j] = tmp;
+    }
+    options.forEach(function (option) {
+      select.appendChild(option);
+    });
+  }
+
+  function setUpQuestion(form, template) {
+    var definition = template.object.definition;
+    Array.prototype.forEach.call(form.querySelectorAll("select"), shuffle);
+    form.addEventListener("submit", function (event) {
+      event.preventDefault();
+      var response = readResponse(form, definition);
+      var success = matches(definition, response);
+
+      form.querySelector(".cmi5-result").textContent = success ? "Correct" : "Incorrect";
+      var feedback = form.querySelector(".feedback");
+      if (feedback) {
+        feedback.hidden = false;
+      }
+
+      send(function () {
+        var s = statement(template, []);
+        s.context.contextActivities.parent = [{ objectType: "Activity", id: launch.activityId }];
+        s.result = { response: response, success: success };
+        return s;
+      });
+    });
+  }
+
+  document.addEventListener("DOMContentLoaded", function () {
+    var data = document.getElementById("cmi5-questions");
+    var templates = (data && JSON.parse(data.textContent)) || [];
+    var forms = document.querySelectorAll("form.cmi5-question");
+    for (var i = 0; i < forms.length && i < templates.length; i++) {
+      setUpQuestion(forms[i], templates[i]);
+    }
+  });
+
+  window.addEventListener("load", function () {
+    var end = document.getElementById("cmi5-end");
+    if (!end || !("IntersectionObserver" in window)) {
+      complete();
+      return;
+    }
+    var observer = new IntersectionObserver(function (entries) {
+      for (var i = 0; i < entries.length; i++) {
+        if (entries[i].isIntersecting) {
+          complete();
+          observer.disconnect();
+          return;
+        }
+      }
+    });
+    observer.observe(end);
+  });
+  window.addEventListener("pagehide", terminate);
+})();
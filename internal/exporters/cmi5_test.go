@@ -0,0 +1,589 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// cmi5StructureDoc is the subset of cmi5.xml checked by the tests.
+type cmi5StructureDoc struct {
+	XMLName xml.Name
+	Course  struct {
+		ID    string `xml:"id,attr"`
+		Title string `xml:"title>langstring"`
+	} `xml:"course"`
+	Blocks []struct {
+		ID          string       `xml:"id,attr"`
+		Title       string       `xml:"title>langstring"`
+		Description string       `xml:"description>langstring"`
+		AUs         []cmi5AUNode `xml:"au"`
+	} `xml:"block"`
+	AUs []cmi5AUNode `xml:"au"`
+}
+
+// cmi5AUNode is an assignable unit of the course structure.
+type cmi5AUNode struct {
+	ID          string `xml:"id,attr"`
+	MoveOn      string `xml:"moveOn,attr"`
+	Title       string `xml:"title>langstring"`
+	Description string `xml:"description>langstring"`
+	URL         string `xml:"url"`
+}
+
+// cmi5QuestionsPattern extracts the statement templates of a lesson page.
+var cmi5QuestionsPattern = regexp.MustCompile(`(?s)<script type="application/json" id="cmi5-questions">(.*?)</script>`)
+
+// createTestCourseForCMI5 returns a course with a lesson before the first
+// section and a section holding a lesson with one question of each type.
+func createTestCourseForCMI5() *models.Course {
+	return &models.Course{
+		Course: models.CourseInfo{
+			ID:          "rise-101",
+			Title:       "Geography",
+			Description: "<p>Capitals &amp; countries</p>",
+			Lessons: []models.Lesson{
+				{Title: "Welcome"},
+				{Title: "Europe", Type: "section"},
+				{
+					Title: "Quiz",
+					Items: []models.Item{
+						{Type: "knowledgeCheck", Variant: "multipleChoice", Items: []models.SubItem{{
+							Title:    "<p>Capital of France?</p>",
+							Answers:  []models.Answer{{Title: "Lyon"}, {Title: "Paris", Correct: true}},
+							Feedback: "<p>Paris it is.</p>",
+						}}},
+						{Type: "knowledgeCheck", Variant: "multipleResponse", Items: []models.SubItem{{
+							Title:   "<p>Which are in the EU?</p>",
+							Answers: []models.Answer{{Title: "Spain", Correct: true}, {Title: "Norway"}, {Title: "Italy", Correct: true}},
+						}}},
+						{Type: "knowledgeCheck", Variant: "fillIn", Items: []models.SubItem{{
+							Title:   "<p>Capital of Germany?</p>",
+							Answers: []models.Answer{{Title: "Berlin"}},
+						}}},
+						{Type: "knowledgeCheck", Variant: "matching", Items: []models.SubItem{{
+							Title:   "<p>Match the capitals</p>",
+							Answers: []models.Answer{{Title: "Italy", MatchTitle: "Rome"}, {Title: "Spain", MatchTitle: "Madrid"}},
+						}}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// exportCMI5 exports course and returns the package files by name.
+func exportCMI5(t *testing.T, course *models.Course) map[string]string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := NewCMI5Exporter(services.NewHTMLCleaner()).ExportTo(course, &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+	entries := readZipFiles(t, buf.Bytes())
+	if entries[0].name != "cmi5.xml" {
+		t.Errorf("Expected cmi5.xml as first entry, got %s", entries[0].name)
+	}
+	files := make(map[string]string)
+	for _, entry := range entries {
+		files[entry.name] = entry.data
+	}
+	return files
+}
+
+// pageStatements returns the answered statement templates of a lesson page.
+func pageStatements(t *testing.T, page string) []map[string]any {
+	t.Helper()
+	match := cmi5QuestionsPattern.FindStringSubmatch(page)
+	if match == nil {
+		t.Fatal("Expected the page to embed its statement templates")
+	}
+	var statements []map[string]any
+	if err := json.Unmarshal([]byte(match[1]), &statements); err != nil {
+		t.Fatalf("Statement templates are not valid JSON: %v", err)
+	}
+	return statements
+}
+
+// TestCMI5Exporter_SupportedFormat tests the SupportedFormat method.
+func TestCMI5Exporter_SupportedFormat(t *testing.T) {
+	exporter := NewCMI5Exporter(services.NewHTMLCleaner())
+	if got := exporter.SupportedFormat(); got != "cmi5" {
+		t.Errorf("Expected format 'cmi5', got '%s'", got)
+	}
+}
+
+// TestCMI5Exporter_CourseStructure tests cmi5.xml and the files it refers to.
+func TestCMI5Exporter_CourseStructure(t *testing.T) {
+	files := exportCMI5(t, createTestCourseForCMI5())
+
+	var structure cmi5StructureDoc
+	if err := xml.Unmarshal([]byte(files["cmi5.xml"]), &structure); err != nil {
+		t.Fatalf("Course structure is not well-formed: %v", err)
+	}
+	if structure.XMLName.Space != "https://w3id.org/xapi/profiles/cmi5/v1/CourseStructure.xsd" {
+		t.Errorf("Unexpected namespace %s", structure.XMLName.Space)
+	}
+	if structure.Course.ID != "urn:articulate-rise:course:rise-101" || structure.Course.Title != "Geography" {
+		t.Errorf("Unexpected course: %+v", structure.Course)
+	}
+
+	if len(structure.AUs) != 1 || len(structure.Blocks) != 1 {
+		t.Fatalf("Expected 1 top-level AU and 1 block, got %+v", structure)
+	}
+	welcome := structure.AUs[0]
+	if welcome.ID != "urn:articulate-rise:course:rise-101/lessons/1" || welcome.MoveOn != "Completed" ||
+		welcome.Title != "Lesson 1: Welcome" || welcome.Description != "Welcome" || welcome.URL != "lesson-001.html" {
+		t.Errorf("Unexpected AU: %+v", welcome)
+	}
+	block := structure.Blocks[0]
+	if block.Title != "Europe" || len(block.AUs) != 1 || block.AUs[0].URL != "lesson-002.html" {
+		t.Errorf("Unexpected block: %+v", block)
+	}
+
+	for _, au := range append(structure.AUs, block.AUs...) {
+		if _, ok := files[au.URL]; !ok {
+			t.Errorf("AU %s launches missing file %s", au.ID, au.URL)
+		}
+	}
+	for _, name := range []string{"styles.css", "cmi5.js"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("Expected %s in the package", name)
+		}
+	}
+	if !strings.Contains(files["cmi5.xml"], "<description><langstring lang=\"en-US\">Capitals &amp; countries</langstring></description>") {
+		t.Error("Expected the cleaned course description")
+	}
+}
+
+// TestCMI5Exporter_LessonPage tests that knowledge checks become forms that
+// match the embedded answered statement templates.
+func TestCMI5Exporter_LessonPage(t *testing.T) {
+	files := exportCMI5(t, createTestCourseForCMI5())
+	page := files["lesson-002.html"]
+
+	for _, expected := range []string{
+		`<script src="cmi5.js"></script>`,
+		`<form class="cmi5-question">`,
+		`<input type="radio" name="response" value="choice-2"> Paris`,
+		`<input type="checkbox" name="response" value="choice-3"> Italy`,
+		`<input type="text" name="response"`,
+		`<select name="source-2">`,
+		`<option value="target-1">Rome</option>`,
+		`<div class="feedback" hidden>`,
+		`id="cmi5-end"`,
+	} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("Expected lesson page to contain '%s'", expected)
+		}
+	}
+	if strings.Contains(page, "correct-answer") {
+		t.Error("Expected the page not to reveal correct answers in the markup")
+	}
+	if forms := strings.Count(page, `<form class="cmi5-question">`); forms != len(pageStatements(t, page)) {
+		t.Errorf("Expected one statement template per form, got %d forms", forms)
+	}
+	if got := pageStatements(t, files["lesson-001.html"]); len(got) != 0 {
+		t.Errorf("Expected no statement templates without knowledge checks, got %v", got)
+	}
+}
+
+// TestXAPIInteraction tests that correct response patterns are derived from
+// the answers of each question type.
+func TestXAPIInteraction(t *testing.T) {
+	tests := []struct {
+		questionType    string
+		answers         []models.Answer
+		interactionType string
+		pattern         []string
+	}{
+		{
+			questionType:    models.QuestionSingleChoice,
+			answers:         []models.Answer{{Title: "A"}, {Title: "B", Correct: true}},
+			interactionType: "choice",
+			pattern:         []string{"choice-2"},
+		},
+		{
+			questionType:    "",
+			answers:         []models.Answer{{Title: "A", Correct: true}, {Title: "B"}},
+			interactionType: "choice",
+			pattern:         []string{"choice-1"},
+		},
+		{
+			questionType:    models.QuestionMultipleChoice,
+			answers:         []models.Answer{{Title: "A", Correct: true}, {Title: "B"}, {Title: "C", Correct: true}},
+			interactionType: "choice",
+			pattern:         []string{"choice-1[,]choice-3"},
+		},
+		{
+			questionType:    models.QuestionFillIn,
+			answers:         []models.Answer{{Title: "Paris"}, {Title: "Parijs"}},
+			interactionType: "fill-in",
+			pattern:         []string{"{case_matters=false}Paris", "{case_matters=false}Parijs"},
+		},
+		{
+			questionType:    models.QuestionMatching,
+			answers:         []models.Answer{{Title: "France", MatchTitle: "Paris"}, {Title: "Peru", MatchTitle: "Lima"}},
+			interactionType: "matching",
+			pattern:         []string{"source-1[.]target-1[,]source-2[.]target-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.interactionType+"/"+tt.questionType, func(t *testing.T) {
+			definition := xapiInteraction(tt.questionType, tt.answers)
+			if definition.Type != "http://adlnet.gov/expapi/activities/cmi.interaction" {
+				t.Errorf("Unexpected activity type %s", definition.Type)
+			}
+			if definition.InteractionType != tt.interactionType {
+				t.Errorf("Expected interaction type %s, got %s", tt.interactionType, definition.InteractionType)
+			}
+			if !slices.Equal(definition.CorrectResponsesPattern, tt.pattern) {
+				t.Errorf("Expected pattern %v, got %v", tt.pattern, definition.CorrectResponsesPattern)
+			}
+		})
+	}
+}
+
+// stubLRS is a minimal LRS for the tests. It issues an auth token, serves the
+// LMS.LaunchData state document and records the statements it receives.
+type stubLRS struct {
+	t          *testing.T
+	mu         sync.Mutex
+	statements []map[string]any
+}
+
+// Launch values used with the stub LRS.
+const (
+	stubAuthToken    = "dGVzdDpzZWNyZXQ="
+	stubRegistration = "0c2d4a1e-7a3f-4d6b-9b1f-2f5e8c9d0a11"
+	stubSessionID    = "session-42"
+	stubActivityID   = "https://lms.example.com/activities/au-quiz"
+)
+
+// ServeHTTP implements the fetch URL and the parts of the xAPI the runtime uses.
+func (l *stubLRS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/fetch" {
+		_ = json.NewEncoder(w).Encode(map[string]string{"auth-token": stubAuthToken})
+		return
+	}
+
+	if got := r.Header.Get("Authorization"); got != "Basic "+stubAuthToken {
+		l.t.Errorf("Expected the fetched auth token, got '%s'", got)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if got := r.Header.Get("X-Experience-API-Version"); !strings.HasPrefix(got, "1.0") {
+		l.t.Errorf("Expected an xAPI 1.0 version header, got '%s'", got)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/xapi/activities/state":
+		if r.URL.Query().Get("stateId") != "LMS.LaunchData" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"launchMode": "Normal",
+			"moveOn":     "Completed",
+			"contextTemplate": map[string]any{
+				"extensions": map[string]string{"https://w3id.org/xapi/cmi5/context/extensions/sessionid": stubSessionID},
+			},
+		})
+	case r.Method == http.MethodPost && r.URL.Path == "/xapi/statements":
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			l.t.Errorf("Expected JSON statements, got '%s'", ct)
+		}
+		var statement map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&statement); err != nil {
+			l.t.Errorf("Failed to decode statement: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		l.mu.Lock()
+		l.statements = append(l.statements, statement)
+		l.mu.Unlock()
+		_ = json.NewEncoder(w).Encode([]any{statement["id"]})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// learnerSession plays the part of cmi5.js: it completes statement templates
+// with the launch data and the learner's response and posts them to the LRS.
+type learnerSession struct {
+	t        *testing.T
+	endpoint string
+	auth     string
+	context  map[string]any
+}
+
+// launchSession fetches the auth token and launch data, as cmi5.js does on load.
+func launchSession(t *testing.T, server *httptest.Server) *learnerSession {
+	t.Helper()
+	s := &learnerSession{t: t, endpoint: server.URL + "/xapi/"}
+
+	resp, err := http.Post(server.URL+"/fetch", "", nil)
+	if err != nil {
+		t.Fatalf("Failed to fetch auth token: %v", err)
+	}
+	var token map[string]string
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("Failed to decode auth token: %v", err)
+	}
+	s.auth = "Basic " + token["auth-token"]
+
+	var launchData struct {
+		ContextTemplate map[string]any `json:"contextTemplate"`
+	}
+	s.do(http.MethodGet, "activities/state?stateId=LMS.LaunchData", nil, &launchData)
+	s.context = launchData.ContextTemplate
+	return s
+}
+
+// do sends an xAPI request and decodes the response into out, if given.
+func (s *learnerSession) do(method, resource string, body, out any) {
+	s.t.Helper()
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			s.t.Fatalf("Failed to encode request: %v", err)
+		}
+	}
+	req, err := http.NewRequest(method, s.endpoint+resource, &payload)
+	if err != nil {
+		s.t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", s.auth)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Experience-API-Version", "1.0.3")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		s.t.Fatalf("Request to %s failed: %v", resource, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		s.t.Fatalf("Expected status 200 from %s, got %d", resource, resp.StatusCode)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			s.t.Fatalf("Failed to decode response from %s: %v", resource, err)
+		}
+	}
+}
+
+// answer posts template completed with response and its success, computed
+// from the correct response patterns like cmi5.js does.
+func (s *learnerSession) answer(template map[string]any, response string) {
+	s.t.Helper()
+	definition := template["object"].(map[string]any)["definition"].(map[string]any)
+	success := false
+	for _, p := range definition["correctResponsesPattern"].([]any) {
+		pattern := p.(string)
+		switch {
+		case definition["interactionType"] == "fill-in" && strings.HasPrefix(pattern, "{case_matters=false}"):
+			success = success || strings.EqualFold(strings.TrimPrefix(pattern, "{case_matters=false}"), response)
+		case definition["interactionType"] == "fill-in":
+			success = success || pattern == response
+		default:
+			a, b := strings.Split(pattern, "[,]"), strings.Split(response, "[,]")
+			slices.Sort(a)
+			slices.Sort(b)
+			success = success || slices.Equal(a, b)
+		}
+	}
+
+	statement := make(map[string]any, len(template)+4)
+	for k, v := range template {
+		statement[k] = v
+	}
+	statement["actor"] = map[string]any{"objectType": "Agent", "account": map[string]string{"homePage": "https://lms.example.com", "name": "learner-1"}}
+	statement["context"] = map[string]any{
+		"registration":      stubRegistration,
+		"extensions":        s.context["extensions"],
+		"contextActivities": map[string]any{"parent": []any{map[string]string{"objectType": "Activity", "id": stubActivityID}}},
+	}
+	statement["result"] = map[string]any{"response": response, "success": success}
+	s.do(http.MethodPost, "statements", statement, nil)
+}
+
+// TestCMI5Exporter_AnsweredStatements answers the exported questions against a
+// stub LRS and checks the payloads of the answered statements it receives.
+func TestCMI5Exporter_AnsweredStatements(t *testing.T) {
+	files := exportCMI5(t, createTestCourseForCMI5())
+	templates := pageStatements(t, files["lesson-002.html"])
+	if len(templates) != 4 {
+		t.Fatalf("Expected 4 statement templates, got %d", len(templates))
+	}
+
+	lrs := &stubLRS{t: t}
+	server := httptest.NewServer(lrs)
+	defer server.Close()
+
+	session := launchSession(t, server)
+	responses := []struct {
+		interactionType string
+		response        string
+		success         bool
+	}{
+		{"choice", "choice-2", true},                                    // Paris
+		{"choice", "choice-3[,]choice-2", false},                        // Italy and Norway
+		{"fill-in", "berlin", true},                                     // case-insensitive
+		{"matching", "source-1[.]target-1[,]source-2[.]target-2", true}, // Italy-Rome, Spain-Madrid
+	}
+	for i, r := range responses {
+		session.answer(templates[i], r.response)
+	}
+
+	if len(lrs.statements) != len(responses) {
+		t.Fatalf("Expected %d statements at the LRS, got %d", len(responses), len(lrs.statements))
+	}
+
+	type received struct {
+		Verb struct {
+			ID string `json:"id"`
+		} `json:"verb"`
+		Object struct {
+			ObjectType string                 `json:"objectType"`
+			ID         string                 `json:"id"`
+			Definition xapiActivityDefinition `json:"definition"`
+		} `json:"object"`
+		Context struct {
+			Registration      string            `json:"registration"`
+			Extensions        map[string]string `json:"extensions"`
+			ContextActivities struct {
+				Parent []struct {
+					ID string `json:"id"`
+				} `json:"parent"`
+			} `json:"contextActivities"`
+		} `json:"context"`
+		Result struct {
+			Response string `json:"response"`
+			Success  bool   `json:"success"`
+		} `json:"result"`
+	}
+	var statements []received
+	for _, raw := range lrs.statements {
+		data, _ := json.Marshal(raw)
+		var s received
+		if err := json.Unmarshal(data, &s); err != nil {
+			t.Fatalf("Failed to decode received statement: %v", err)
+		}
+		statements = append(statements, s)
+	}
+
+	for i, s := range statements {
+		if s.Verb.ID != "http://adlnet.gov/expapi/verbs/answered" {
+			t.Errorf("Statement %d: unexpected verb %s", i, s.Verb.ID)
+		}
+		if expected := fmt.Sprintf("urn:articulate-rise:course:rise-101/lessons/2/questions/%d", i+1); s.Object.ID != expected {
+			t.Errorf("Statement %d: expected object %s, got %s", i, expected, s.Object.ID)
+		}
+		if s.Object.ObjectType != "Activity" || s.Object.Definition.Type != "http://adlnet.gov/expapi/activities/cmi.interaction" {
+			t.Errorf("Statement %d: unexpected object %+v", i, s.Object)
+		}
+		if s.Object.Definition.InteractionType != responses[i].interactionType {
+			t.Errorf("Statement %d: expected interaction type %s, got %s", i, responses[i].interactionType, s.Object.Definition.InteractionType)
+		}
+		if s.Context.Registration != stubRegistration ||
+			s.Context.Extensions["https://w3id.org/xapi/cmi5/context/extensions/sessionid"] != stubSessionID ||
+			len(s.Context.ContextActivities.Parent) != 1 || s.Context.ContextActivities.Parent[0].ID != stubActivityID {
+			t.Errorf("Statement %d: unexpected context %+v", i, s.Context)
+		}
+		if s.Result.Response != responses[i].response || s.Result.Success != responses[i].success {
+			t.Errorf("Statement %d: expected response %s with success %v, got %+v", i, responses[i].response, responses[i].success, s.Result)
+		}
+	}
+
+	// The Answer.Correct data travels with each statement
+	single := statements[0].Object.Definition
+	if single.InteractionType != "choice" || !slices.Equal(single.CorrectResponsesPattern, []string{"choice-2"}) ||
+		len(single.Choices) != 2 || single.Choices[1].Description["en-US"] != "Paris" {
+		t.Errorf("Unexpected single choice definition: %+v", single)
+	}
+	if single.Name["en-US"] != "Capital of France?" {
+		t.Errorf("Expected the cleaned question as name, got %v", single.Name)
+	}
+	multiple := statements[1].Object.Definition
+	if !slices.Equal(multiple.CorrectResponsesPattern, []string{"choice-1[,]choice-3"}) {
+		t.Errorf("Expected Spain and Italy as correct responses, got %v", multiple.CorrectResponsesPattern)
+	}
+	if fillIn := statements[2].Object.Definition; !slices.Equal(fillIn.CorrectResponsesPattern, []string{"{case_matters=false}Berlin"}) {
+		t.Errorf("Unexpected fill-in pattern %v", fillIn.CorrectResponsesPattern)
+	}
+	matching := statements[3].Object.Definition
+	if len(matching.Source) != 2 || len(matching.Target) != 2 || matching.Target[1].Description["en-US"] != "Madrid" ||
+		!slices.Equal(matching.CorrectResponsesPattern, []string{"source-1[.]target-1[,]source-2[.]target-2"}) {
+		t.Errorf("Unexpected matching definition: %+v", matching)
+	}
+}
+
+// TestCMI5Exporter_Runtime tests that the runtime script follows the cmi5
+// launch sequence and sends the statements the AU is expected to.
+func TestCMI5Exporter_Runtime(t *testing.T) {
+	script := exportCMI5(t, createTestCourseForCMI5())["cmi5.js"]
+	for _, expected := range []string{
+		`params.get("endpoint")`,
+		`params.get("fetch")`,
+		`token["auth-token"]`,
+		`"LMS.LaunchData"`,
+		"http://adlnet.gov/expapi/verbs/initialized",
+		"http://adlnet.gov/expapi/verbs/completed",
+		"http://adlnet.gov/expapi/verbs/terminated",
+		"https://w3id.org/xapi/cmi5/context/categories/moveon",
+		`"X-Experience-API-Version"`,
+		`"cmi5-questions"`,
+	} {
+		if !strings.Contains(script, expected) {
+			t.Errorf("Expected runtime script to contain '%s'", expected)
+		}
+	}
+}
+
+// TestCMI5Exporter_NoLessons tests that a course without lessons is rejected.
+func TestCMI5Exporter_NoLessons(t *testing.T) {
+	exporter := NewCMI5Exporter(services.NewHTMLCleaner())
+	course := &models.Course{Course: models.CourseInfo{
+		Title:   "Empty",
+		Lessons: []models.Lesson{{Title: "Only a section", Type: "section"}},
+	}}
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); !errors.Is(err, errNoLessons) {
+		t.Errorf("Expected errNoLessons, got %v", err)
+	}
+}
+
+// TestCMI5Exporter_Export tests that Export writes the package to a file.
+func TestCMI5Exporter_Export(t *testing.T) {
+	exporter := NewCMI5Exporter(services.NewHTMLCleaner())
+	outputPath := filepath.Join(t.TempDir(), "course.zip")
+
+	if err := exporter.Export(createTestCourseForCMI5(), outputPath); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte("PK")) {
+		t.Error("Expected a zip archive")
+	}
+}
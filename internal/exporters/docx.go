@@ -3,9 +3,14 @@
 package exporters
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fumiama/go-docx"
 	"golang.org/x/text/cases"
@@ -16,19 +21,93 @@ import (
 	"github.com/kjanat/articulate-parser/internal/services"
 )
 
-// Font sizes for DOCX document headings (in half-points, so "32" = 16pt).
+// Paragraph styles of DOCX documents. They are Word's built-in styles, so
+// reference documents, the navigation pane and tables of contents recognize
+// them; docx_styles.xml defines them for documents that lack them.
 const (
-	docxTitleSize  = "32" // Course title (16pt)
-	docxLessonSize = "28" // Lesson heading (14pt)
-	docxItemSize   = "24" // Item heading (12pt)
+	docxStyleTitle      = "Title"
+	docxStyleHeading1   = "Heading1"
+	docxStyleHeading2   = "Heading2"
+	docxStyleHeading3   = "Heading3"
+	docxStyleListBullet = "ListBullet"
+	docxStyleListNumber = "ListNumber"
+	docxStyleCaption    = "Caption"
 )
 
+// docxMaxListLevel is the deepest list nesting level Word supports.
+const docxMaxListLevel = 8
+
+// docxLinkColor is the colour of hyperlinks, as in Word's Hyperlink style.
+const docxLinkColor = "0563C1"
+
+// docxTOCMarker is the text of the placeholder paragraph that is replaced
+// by the table of contents once the document is written.
+const docxTOCMarker = "{{articulate-parser:toc}}"
+
 // DocxExporter implements the Exporter interface for DOCX format.
 // It converts Articulate Rise course data into a Microsoft Word document
-// using the go-docx package.
+// using the go-docx package. Headings and lists use Word's built-in styles,
+// so the document can take the styles of a reference document.
 type DocxExporter struct {
 	// htmlCleaner is used to convert HTML content to plain text
 	htmlCleaner *services.HTMLCleaner
+	// reference provides the styles, headers, footers and page setup; nil uses the built-in styles
+	reference *docxPackage
+	// toc adds a table of contents after the course title
+	toc bool
+	// mediaDir is a local directory searched for media before downloading it; empty disables it
+	mediaDir string
+	// client downloads media that is neither bundled with the course nor in mediaDir
+	client *http.Client
+}
+
+// DocxOptions customizes the DOCX exporter. The zero value uses the built-in
+// styles and no table of contents.
+type DocxOptions struct {
+	// ReferenceDoc is a .docx or .dotx file whose styles, headers, footers and
+	// page setup are used. Styles the export needs but the reference lacks are
+	// added from the built-in ones.
+	ReferenceDoc string
+	// TOC adds a table of contents field after the course title, which Word
+	// fills in when the document is opened
+	TOC bool
+	// MediaDir is a directory of downloaded course media. Images are looked
+	// up there by the file name of their key or URL before they are downloaded.
+	MediaDir string
+}
+
+// docxDocument is a Word document being built, together with the lists that
+// need numbering definitions once it is written.
+type docxDocument struct {
+	*docx.Docx
+	// lists holds each list of the document; list i uses numbering ID i+1
+	lists []docxList
+	// pkg is the source package of the course, which may bundle its media
+	pkg *models.SourcePackage
+	// images caches the loaded images by their keys; nil marks images that could not be loaded
+	images map[string][]byte
+}
+
+// docxList is a list of a Word document.
+type docxList struct {
+	// style is models.ListStyleBulleted, ListStyleNumbered or ListStyleCheckboxes
+	style string
+	// level is the nesting level of the list's paragraphs, from 0
+	level int
+}
+
+// addList starts a new list and returns its numbering ID. Each list has its
+// own ID, so numbered lists restart at 1, including lists nested in others.
+//
+// Parameters:
+//   - style: The list style (models.ListStyleBulleted, ListStyleNumbered or ListStyleCheckboxes)
+//   - level: The nesting level of the list, from 0 to docxMaxListLevel
+//
+// Returns:
+//   - The numbering ID for the paragraphs of the list
+func (d *docxDocument) addList(style string, level int) string {
+	d.lists = append(d.lists, docxList{style: style, level: level})
+	return strconv.Itoa(len(d.lists))
 }
 
 // NewDocxExporter creates a new DocxExporter instance.
@@ -42,7 +121,42 @@ type DocxExporter struct {
 func NewDocxExporter(htmlCleaner *services.HTMLCleaner) interfaces.Exporter {
 	return &DocxExporter{
 		htmlCleaner: htmlCleaner,
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// NewDocxExporterWithOptions creates a new DocxExporter instance that uses a
+// reference document, adds a table of contents or reads media from a local
+// directory.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//   - opts: The reference document, table of contents and media options
+//
+// Returns:
+//   - An implementation of the Exporter interface for DOCX format
+//   - An error if the reference document cannot be read or is not a Word document
+func NewDocxExporterWithOptions(htmlCleaner *services.HTMLCleaner, opts DocxOptions) (interfaces.Exporter, error) {
+	exporter := &DocxExporter{
+		htmlCleaner: htmlCleaner,
+		toc:         opts.TOC,
+		mediaDir:    opts.MediaDir,
+		client:      &http.Client{Timeout: 30 * time.Second},
 	}
+
+	if opts.ReferenceDoc != "" {
+		// #nosec G304 - The reference document is chosen by the user via CLI flag or environment
+		data, err := os.ReadFile(opts.ReferenceDoc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read reference document: %w", err)
+		}
+		reference, err := readDocxPackage(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference document %s: %w", opts.ReferenceDoc, err)
+		}
+		exporter.reference = reference
+	}
+	return exporter, nil
 }
 
 // Export exports the course to a DOCX file.
@@ -56,22 +170,9 @@ func NewDocxExporter(htmlCleaner *services.HTMLCleaner) interfaces.Exporter {
 // Returns:
 //   - An error if creating or saving the document fails
 func (e *DocxExporter) Export(course *models.Course, outputPath string) error {
-	doc := docx.New()
-
-	// Add title
-	titlePara := doc.AddParagraph()
-	titlePara.AddText(course.Course.Title).Size(docxTitleSize).Bold()
-
-	// Add description if available
-	if course.Course.Description != "" {
-		descPara := doc.AddParagraph()
-		cleanDesc := e.htmlCleaner.CleanHTML(course.Course.Description)
-		descPara.AddText(cleanDesc)
-	}
-
-	// Add each lesson
-	for _, lesson := range course.Course.Lessons {
-		e.exportLesson(doc, &lesson)
+	data, err := e.render(course)
+	if err != nil {
+		return err
 	}
 
 	// Ensure output directory exists and add .docx extension
@@ -85,7 +186,7 @@ func (e *DocxExporter) Export(course *models.Course, outputPath string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create output file: %w", err)
 	}
-	// Ensure file is closed even if WriteTo fails. Close errors are logged but not
+	// Ensure file is closed even if Write fails. Close errors are logged but not
 	// fatal since the document content has already been written to disk. A close
 	// error typically indicates a filesystem synchronization issue that doesn't
 	// affect the validity of the exported file.
@@ -96,30 +197,126 @@ func (e *DocxExporter) Export(course *models.Course, outputPath string) error {
 	}()
 
 	// Save the document
-	_, err = doc.WriteTo(file)
-	if err != nil {
+	if _, err := file.Write(data); err != nil {
 		return fmt.Errorf("failed to save document: %w", err)
 	}
 
 	return nil
 }
 
+// ExportTo exports the course as a DOCX document written to w.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - w: The writer the DOCX content will be written to
+//
+// Returns:
+//   - An error if writing the document fails
+func (e *DocxExporter) ExportTo(course *models.Course, w io.Writer) error {
+	data, err := e.render(course)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to save document: %w", err)
+	}
+	return nil
+}
+
+// render builds the Word document for a course and completes the package
+// written by go-docx: it applies the reference document, adds the styles
+// and list numbering the document uses and inserts the table of contents.
+//
+// Parameters:
+//   - course: The course data model to export
+//
+// Returns:
+//   - The DOCX file content
+//   - An error if the document cannot be written or completed
+func (e *DocxExporter) render(course *models.Course) ([]byte, error) {
+	doc := e.buildDocument(course)
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("failed to save document: %w", err)
+	}
+	pkg, err := readDocxPackage(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to save document: %w", err)
+	}
+
+	if e.reference != nil {
+		if pkg, err = pkg.withReference(e.reference); err != nil {
+			return nil, fmt.Errorf("failed to apply reference document: %w", err)
+		}
+	} else {
+		// The built-in styles replace those of the library's default theme
+		pkg.set(docxStylesPart, []byte(docxStyles))
+	}
+	if err := pkg.addStyles(); err != nil {
+		return nil, fmt.Errorf("failed to add styles: %w", err)
+	}
+	if err := pkg.addNumbering(doc.lists); err != nil {
+		return nil, fmt.Errorf("failed to add list numbering: %w", err)
+	}
+	if e.toc {
+		if err := pkg.insertTOC(); err != nil {
+			return nil, fmt.Errorf("failed to add table of contents: %w", err)
+		}
+	}
+	return pkg.bytes()
+}
+
+// buildDocument creates the Word document for a course in memory.
+//
+// Parameters:
+//   - course: The course data model to export
+//
+// Returns:
+//   - The populated Word document
+func (e *DocxExporter) buildDocument(course *models.Course) *docxDocument {
+	doc := &docxDocument{Docx: docx.New().WithDefaultTheme(), pkg: course.Package, images: map[string][]byte{}}
+
+	// Add title
+	doc.AddParagraph().Style(docxStyleTitle).AddText(course.Course.Title)
+
+	// Add the cover image below the title
+	if cover := course.Course.CoverImage; cover != nil && cover.Image != nil {
+		e.addImage(doc.AddParagraph(), doc, cover.Image.Width, cover.Image.Height, cover.Image.Key, cover.Image.OriginalURL)
+	}
+
+	// Add the table of contents placeholder, replaced once the document is written
+	if e.toc {
+		doc.AddParagraph().AddText(docxTOCMarker)
+	}
+
+	// Add description if available
+	if course.Course.Description != "" {
+		addHTML(doc, course.Course.Description)
+	}
+
+	// Add each lesson
+	for _, lesson := range course.Course.Lessons {
+		e.exportLesson(doc, &lesson)
+	}
+
+	return doc
+}
+
 // exportLesson adds a lesson to the document with appropriate formatting.
 // It creates a lesson heading, adds the description, and processes all items in the lesson.
 //
 // Parameters:
 //   - doc: The Word document being created
 //   - lesson: The lesson data model to export
-func (e *DocxExporter) exportLesson(doc *docx.Docx, lesson *models.Lesson) {
+func (e *DocxExporter) exportLesson(doc *docxDocument, lesson *models.Lesson) {
 	// Add lesson title
-	lessonPara := doc.AddParagraph()
-	lessonPara.AddText(fmt.Sprintf("Lesson: %s", lesson.Title)).Size(docxLessonSize).Bold()
+	doc.AddParagraph().Style(docxStyleHeading1).AddText(fmt.Sprintf("Lesson: %s", lesson.Title))
 
 	// Add lesson description if available
 	if lesson.Description != "" {
-		descPara := doc.AddParagraph()
-		cleanDesc := e.htmlCleaner.CleanHTML(lesson.Description)
-		descPara.AddText(cleanDesc)
+		addHTML(doc, lesson.Description)
 	}
 
 	// Add each item in the lesson
@@ -134,69 +331,229 @@ func (e *DocxExporter) exportLesson(doc *docx.Docx, lesson *models.Lesson) {
 // Parameters:
 //   - doc: The Word document being created
 //   - item: The item data model to export
-func (e *DocxExporter) exportItem(doc *docx.Docx, item *models.Item) {
+func (e *DocxExporter) exportItem(doc *docxDocument, item *models.Item) {
 	// Add item type as heading
 	if item.Type != "" {
-		itemPara := doc.AddParagraph()
 		caser := cases.Title(language.English)
-		itemPara.AddText(caser.String(item.Type)).Size(docxItemSize).Bold()
+		doc.AddParagraph().Style(docxStyleHeading2).AddText(caser.String(item.Type))
+	}
+
+	details := services.ItemDetails(item)
+	if details.List != nil {
+		e.exportListEntries(doc, item, details.List.Style)
+		return
+	}
+
+	questionType := ""
+	if details.KnowledgeCheck != nil {
+		questionType = details.KnowledgeCheck.QuestionType
 	}
 
 	// Add sub-items
 	for _, subItem := range item.Items {
-		e.exportSubItem(doc, &subItem)
+		e.exportSubItem(doc, &subItem, questionType)
+	}
+}
+
+// exportListEntries adds the entries of a list item as a Word list matching
+// the list style: numbers, bullets or empty checkboxes.
+//
+// Parameters:
+//   - doc: The Word document being created
+//   - item: The list item to export
+//   - style: The decoded list style
+func (e *DocxExporter) exportListEntries(doc *docxDocument, item *models.Item, style string) {
+	numID := ""
+	for _, subItem := range item.Items {
+		if subItem.Paragraph == "" {
+			continue
+		}
+		if numID == "" {
+			numID = doc.addList(style, 0)
+		}
+		addInlineHTML(addListParagraph(doc, style, numID, 0), subItem.Paragraph, docxRunFormat{})
+	}
+}
+
+// addListParagraph adds a paragraph to a list started with addList.
+//
+// Parameters:
+//   - doc: The Word document being created
+//   - style: The list style the list was started with
+//   - numID: The numbering ID of the list
+//   - level: The nesting level the list was started with
+//
+// Returns:
+//   - The new paragraph
+func addListParagraph(doc *docxDocument, style, numID string, level int) *docx.Paragraph {
+	paraStyle := docxStyleListBullet
+	if style == models.ListStyleNumbered {
+		paraStyle = docxStyleListNumber
+	}
+	return doc.AddParagraph().Style(paraStyle).NumPr(numID, strconv.Itoa(level))
+}
+
+// addText adds a run of text to a paragraph. Unlike Paragraph.AddText it
+// keeps leading and trailing spaces, which Word drops otherwise.
+//
+// Parameters:
+//   - para: The paragraph the text is added to
+//   - text: The text to add
+//
+// Returns:
+//   - The new run
+func addText(para *docx.Paragraph, text string) *docx.Run {
+	run := para.AddText(text)
+	preserveSpaces(run)
+	return run
+}
+
+// addLink adds a hyperlink to a paragraph. Paragraph.AddLink stores the text
+// as a field instruction, which Word does not show, and refers to a
+// character style that documents may lack, so the run is rebuilt as visible
+// text formatted like Word's Hyperlink style.
+//
+// Parameters:
+//   - para: The paragraph the link is added to
+//   - text: The text of the link
+//   - link: The target URL
+//
+// Returns:
+//   - The run of the link, for further formatting
+func addLink(para *docx.Paragraph, text, link string) *docx.Run {
+	run := &para.AddLink(text, link).Run
+	run.InstrText = ""
+	run.RunProperties = &docx.RunProperties{}
+	run.Children = []interface{}{&docx.Text{Text: text}}
+	preserveSpaces(run)
+	return run.Color(docxLinkColor).Underline("single")
+}
+
+// preserveSpaces marks the text of a run whose leading or trailing spaces
+// must be kept.
+func preserveSpaces(run *docx.Run) {
+	for _, child := range run.Children {
+		if t, ok := child.(*docx.Text); ok && strings.TrimSpace(t.Text) != t.Text {
+			t.XMLSpace = "preserve"
+		}
 	}
 }
 
 // exportSubItem adds a sub-item to the document.
 // It handles different components of a sub-item like title, heading,
-// paragraph content, answers, and feedback.
+// paragraph content, answers, and feedback. Answers are presented according
+// to the question type of the enclosing knowledge check, if any.
 //
 // Parameters:
 //   - doc: The Word document being created
 //   - subItem: The sub-item data model to export
-func (e *DocxExporter) exportSubItem(doc *docx.Docx, subItem *models.SubItem) {
+//   - questionType: The decoded question type, or "" outside knowledge checks
+func (e *DocxExporter) exportSubItem(doc *docxDocument, subItem *models.SubItem, questionType string) {
 	// Add title if available
 	if subItem.Title != "" {
-		subItemPara := doc.AddParagraph()
-		subItemPara.AddText("  " + subItem.Title).Bold() // Indented
+		addInlineHTML(doc.AddParagraph(), subItem.Title, docxRunFormat{bold: true})
 	}
 
 	// Add heading if available
 	if subItem.Heading != "" {
-		headingPara := doc.AddParagraph()
-		cleanHeading := e.htmlCleaner.CleanHTML(subItem.Heading)
-		headingPara.AddText("  " + cleanHeading).Bold() // Indented
+		addInlineHTML(doc.AddParagraph().Style(docxStyleHeading3), subItem.Heading, docxRunFormat{})
 	}
 
-	// Add paragraph content if available
+	// Add paragraph content with its formatting, links, lists and tables
 	if subItem.Paragraph != "" {
-		contentPara := doc.AddParagraph()
-		cleanContent := e.htmlCleaner.CleanHTML(subItem.Paragraph)
-		contentPara.AddText("  " + cleanContent) // Indented
+		addHTML(doc, subItem.Paragraph)
+	}
+
+	// Add media with its caption if available
+	if subItem.Media != nil {
+		e.exportMedia(doc, subItem.Media)
+	}
+	if subItem.Caption != "" {
+		addInlineHTML(doc.AddParagraph().Style(docxStyleCaption), subItem.Caption, docxRunFormat{})
+	}
+
+	// Add the sides if this is a flashcard
+	if isFlashcard(subItem) {
+		e.exportCard(doc, subItem)
 	}
 
 	// Add answers if this is a question
 	if len(subItem.Answers) > 0 {
-		answersPara := doc.AddParagraph()
-		answersPara.AddText("  Answers:").Bold()
+		e.exportAnswers(doc, subItem.Answers, questionType)
+	}
+
+	// Add feedback if available
+	if subItem.Feedback != "" {
+		feedbackPara := doc.AddParagraph()
+		addText(feedbackPara, "Feedback: ").Italic()
+		addInlineHTML(feedbackPara, subItem.Feedback, docxRunFormat{italic: true})
+	}
+}
+
+// exportAnswers adds the answers of a question to the document. Fill-in-the-blank
+// questions list their accepted answers and matching questions their pairs;
+// other questions list numbered choices with the correct ones marked.
+//
+// Parameters:
+//   - doc: The Word document being created
+//   - answers: The answers of the question
+//   - questionType: The decoded question type
+func (e *DocxExporter) exportAnswers(doc *docxDocument, answers []models.Answer, questionType string) {
+	label := "Answers:"
+	style := models.ListStyleNumbered
+	switch questionType {
+	case models.QuestionFillIn:
+		label = "Accepted answers:"
+		style = models.ListStyleBulleted
+	case models.QuestionMatching:
+		label = "Matches:"
+	case models.QuestionMultipleChoice:
+		label = "Answers (select all that apply):"
+	}
+	answersPara := doc.AddParagraph()
+	answersPara.AddText(label).Bold()
+
+	numID := doc.addList(style, 0)
+	for _, answer := range answers {
+		answerPara := addListParagraph(doc, style, numID, 0)
+		cleanAnswer := e.htmlCleaner.CleanHTML(answer.Title)
 
-		for i, answer := range subItem.Answers {
-			answerPara := doc.AddParagraph()
-			prefix := fmt.Sprintf("    %d. ", i+1)
+		switch questionType {
+		case models.QuestionFillIn:
+			answerPara.AddText(cleanAnswer)
+		case models.QuestionMatching:
+			answerPara.AddText(fmt.Sprintf("%s → %s", cleanAnswer, e.htmlCleaner.CleanHTML(answer.MatchTitle)))
+		default:
+			prefix := ""
 			if answer.Correct {
-				prefix += "✓ "
+				prefix = "✓ "
 			}
-			cleanAnswer := e.htmlCleaner.CleanHTML(answer.Title)
 			answerPara.AddText(prefix + cleanAnswer)
 		}
 	}
+}
 
-	// Add feedback if available
-	if subItem.Feedback != "" {
-		feedbackPara := doc.AddParagraph()
-		cleanFeedback := e.htmlCleaner.CleanHTML(subItem.Feedback)
-		feedbackPara.AddText("  Feedback: " + cleanFeedback).Italic()
+// exportCard adds the front and back of a flashcard to the document, each
+// with a bold label and the media shown on that side.
+//
+// Parameters:
+//   - doc: The Word document being created
+//   - subItem: The flashcard sub-item to export
+func (e *DocxExporter) exportCard(doc *docxDocument, subItem *models.SubItem) {
+	for _, side := range []struct {
+		label string
+		side  *models.CardSide
+	}{{"Front", subItem.Front}, {"Back", subItem.Back}} {
+		if side.side == nil {
+			continue
+		}
+		sidePara := doc.AddParagraph()
+		addText(sidePara, side.label+": ").Bold()
+		addInlineHTML(sidePara, side.side.Description, docxRunFormat{})
+
+		if side.side.Media != nil {
+			e.exportMedia(doc, side.side.Media)
+		}
 	}
 }
 
@@ -0,0 +1,373 @@
+package exporters
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fumiama/go-docx"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// docxBlockElements are the HTML elements that start a new paragraph, or a
+// new line inside list items, table cells and inline content.
+var docxBlockElements = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true,
+	atom.Dd: true, atom.Div: true, atom.Dl: true, atom.Dt: true,
+	atom.Figcaption: true, atom.Figure: true, atom.Footer: true, atom.H1: true,
+	atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Header: true, atom.Li: true, atom.Main: true, atom.Nav: true,
+	atom.P: true, atom.Pre: true, atom.Section: true, atom.Tr: true,
+}
+
+// docxRunFormat is the character formatting of the runs of an HTML element.
+type docxRunFormat struct {
+	bold, italic, underline bool
+	// link is the target of the enclosing link, or "" outside links
+	link string
+}
+
+// apply sets the formatting of a run.
+func (f docxRunFormat) apply(run *docx.Run) {
+	if f.bold {
+		run.Bold()
+	}
+	if f.italic {
+		run.Italic()
+	}
+	if f.underline {
+		run.Underline("single")
+	}
+}
+
+// docxHTMLWriter adds HTML from course data to a Word document, mapping
+// bold, italic and underlined text to formatted runs, links to hyperlinks,
+// lists to Word lists and tables to Word tables.
+type docxHTMLWriter struct {
+	doc *docxDocument
+	// para receives inline content; nil starts a new paragraph
+	para *docx.Paragraph
+	// inline keeps all content in para, putting blocks on new lines
+	inline bool
+	// inItem is set inside list items, whose blocks continue the item's paragraph
+	inItem bool
+	// lineStarted is set once the current line of para has text
+	lineStarted bool
+	// space is set when collapsed whitespace is pending before the next text
+	space bool
+	// spaced is set when the last run ends with a space
+	spaced bool
+	// breakPending is set when the next text starts on a new line
+	breakPending bool
+}
+
+// addHTML adds HTML from course data to the document as paragraphs, lists
+// and tables.
+//
+// Parameters:
+//   - doc: The Word document being created
+//   - htmlStr: The HTML to add
+func addHTML(doc *docxDocument, htmlStr string) {
+	w := &docxHTMLWriter{doc: doc}
+	for _, n := range parseDocxHTML(htmlStr) {
+		w.node(n, docxRunFormat{}, 0)
+	}
+}
+
+// addInlineHTML adds HTML from course data to a paragraph as formatted runs.
+// Blocks start new lines; lists and tables are reduced to their lines.
+//
+// Parameters:
+//   - para: The paragraph the runs are added to
+//   - htmlStr: The HTML to add
+//   - format: The formatting applied to all runs
+func addInlineHTML(para *docx.Paragraph, htmlStr string, format docxRunFormat) {
+	w := &docxHTMLWriter{para: para, inline: true}
+	for _, n := range parseDocxHTML(htmlStr) {
+		w.node(n, format, 0)
+	}
+}
+
+// parseDocxHTML parses an HTML fragment from course data. Text that cannot be
+// parsed is returned as a single text node.
+func parseDocxHTML(fragment string) []*html.Node {
+	context := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), context)
+	if err != nil {
+		return []*html.Node{{Type: html.TextNode, Data: fragment}}
+	}
+	return nodes
+}
+
+// node adds an HTML node with the formatting of its ancestors.
+//
+// Parameters:
+//   - n: The node to add
+//   - format: The formatting of the enclosing elements
+//   - level: The nesting level of lists started by n
+func (w *docxHTMLWriter) node(n *html.Node, format docxRunFormat, level int) {
+	switch n.Type {
+	case html.TextNode:
+		w.text(n.Data, format)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Img, atom.Input, atom.Hr:
+		return
+	case atom.Br:
+		w.lineBreak()
+		return
+	case atom.Strong, atom.B, atom.Th:
+		format.bold = true
+	case atom.Em, atom.I:
+		format.italic = true
+	case atom.U:
+		format.underline = true
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		// Headings in course text stay out of the document outline
+		format.bold = true
+	case atom.A:
+		if href := strings.TrimSpace(attrValue(n, "href")); href != "" && !strings.HasPrefix(strings.ToLower(href), "javascript:") {
+			format.link = href
+		}
+	case atom.Ul, atom.Ol:
+		if !w.inline {
+			w.list(n, format, level)
+			return
+		}
+	case atom.Table:
+		if !w.inline {
+			w.table(n, format)
+			return
+		}
+	case atom.Pre:
+		w.startBlock()
+		w.preformatted(n, format)
+		w.endBlock()
+		return
+	}
+
+	if docxBlockElements[n.DataAtom] {
+		w.startBlock()
+		w.children(n, format, level)
+		w.endBlock()
+		return
+	}
+	w.children(n, format, level)
+}
+
+// children adds the child nodes of n.
+func (w *docxHTMLWriter) children(n *html.Node, format docxRunFormat, level int) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.node(c, format, level)
+	}
+}
+
+// startBlock prepares for a block: a new paragraph, or a new line where the
+// content stays in the current paragraph.
+func (w *docxHTMLWriter) startBlock() {
+	if (w.inline || w.inItem) && w.para != nil {
+		w.breakPending = w.breakPending || w.lineStarted
+		return
+	}
+	w.para = nil
+}
+
+// endBlock ends a block, so that following text starts a new paragraph or line.
+func (w *docxHTMLWriter) endBlock() {
+	w.startBlock()
+}
+
+// paragraph returns the paragraph for inline content, starting one if needed.
+func (w *docxHTMLWriter) paragraph() *docx.Paragraph {
+	if w.para == nil {
+		w.para = w.doc.AddParagraph()
+		w.lineStarted, w.space, w.spaced, w.breakPending = false, false, false, false
+	}
+	if w.breakPending {
+		w.addBreak()
+	}
+	return w.para
+}
+
+// text adds text as a run, collapsing whitespace as browsers do.
+func (w *docxHTMLWriter) text(s string, format docxRunFormat) {
+	text := strings.Join(strings.Fields(s), " ")
+	if text == "" {
+		w.space = w.space || s != ""
+		return
+	}
+
+	para := w.paragraph()
+	if (w.space || startsWithSpace(s)) && w.lineStarted && !w.spaced {
+		text = " " + text
+	}
+	// Trailing spaces stay in their run, so they keep its formatting
+	w.spaced = endsWithSpace(s)
+	if w.spaced {
+		text += " "
+	}
+	w.run(para, text, format)
+	w.lineStarted, w.space = true, false
+}
+
+// startsWithSpace reports whether s starts with whitespace.
+func startsWithSpace(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsSpace(r)
+}
+
+// endsWithSpace reports whether s ends with whitespace.
+func endsWithSpace(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return unicode.IsSpace(r)
+}
+
+// run adds a run of text to a paragraph, as a hyperlink inside links.
+func (w *docxHTMLWriter) run(para *docx.Paragraph, text string, format docxRunFormat) {
+	if format.link != "" {
+		format.apply(addLink(para, text, format.link))
+		return
+	}
+	format.apply(addText(para, text))
+}
+
+// lineBreak adds a line break for <br>. Breaks outside paragraphs are
+// dropped, as they would only add empty lines.
+func (w *docxHTMLWriter) lineBreak() {
+	if w.para == nil {
+		return
+	}
+	if w.breakPending {
+		w.addBreak()
+	}
+	w.addBreak()
+}
+
+// addBreak adds a line break to the current paragraph.
+func (w *docxHTMLWriter) addBreak() {
+	run := w.para.AddText("")
+	run.Children = []interface{}{&docx.BarterRabbet{Type: "textWrapping"}}
+	w.lineStarted, w.space, w.spaced, w.breakPending = false, false, false, false
+}
+
+// preformatted adds preformatted text, keeping its spaces and line breaks.
+func (w *docxHTMLWriter) preformatted(n *html.Node, format docxRunFormat) {
+	text := strings.TrimRight(nodeText(n), "\n")
+	for i, line := range strings.Split(text, "\n") {
+		para := w.paragraph()
+		if i > 0 {
+			w.addBreak()
+		}
+		if line != "" {
+			w.run(para, line, format)
+			w.lineStarted = true
+		}
+	}
+}
+
+// list adds a bulleted or numbered list as a Word list. Lists inside its items
+// become nested lists one level deeper.
+func (w *docxHTMLWriter) list(n *html.Node, format docxRunFormat, level int) {
+	style := models.ListStyleBulleted
+	if n.DataAtom == atom.Ol {
+		style = models.ListStyleNumbered
+	}
+	level = min(level, docxMaxListLevel)
+	numID := w.doc.addList(style, level)
+
+	inItem := w.inItem
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if c.DataAtom != atom.Li {
+			// Lists misplaced directly inside the list are nested in it
+			w.para, w.inItem = nil, false
+			w.node(c, format, level+1)
+			continue
+		}
+		w.para = addListParagraph(w.doc, style, numID, level)
+		w.lineStarted, w.space, w.spaced, w.breakPending, w.inItem = false, false, false, false, true
+		w.children(c, format, level+1)
+	}
+	w.para, w.inItem = nil, inItem
+}
+
+// table adds a table as a Word table. Header cells are bold.
+func (w *docxHTMLWriter) table(n *html.Node, format docxRunFormat) {
+	var rows [][]*html.Node
+	columns := 0
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if c.DataAtom != atom.Tr {
+				collect(c)
+				continue
+			}
+			var row []*html.Node
+			for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.DataAtom == atom.Td || cell.DataAtom == atom.Th {
+					row = append(row, cell)
+				}
+			}
+			rows = append(rows, row)
+			columns = max(columns, len(row))
+		}
+	}
+	collect(n)
+	if columns == 0 {
+		return
+	}
+
+	table := w.doc.AddTable(len(rows), columns, 0, nil)
+	for i, row := range rows {
+		for j, cell := range table.TableRows[i].TableCells {
+			// Every cell needs a paragraph, even where the row is short
+			cw := &docxHTMLWriter{doc: w.doc, para: cell.AddParagraph(), inline: true}
+			if j < len(row) {
+				cw.node(row[j], format, 0)
+			}
+		}
+	}
+	w.para = nil
+}
+
+// nodeText returns the text below n; <br> becomes a line break.
+func nodeText(n *html.Node) string {
+	var buf strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			buf.WriteString(n.Data)
+		case n.Type == html.ElementNode && n.DataAtom == atom.Br:
+			buf.WriteString("\n")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return buf.String()
+}
+
+// attrValue returns the value of an attribute of n, or "" if it is not set.
+func attrValue(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
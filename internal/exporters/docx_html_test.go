@@ -0,0 +1,103 @@
+package exporters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// createTestCourseForDocxRichText returns a course whose text uses
+// formatting, links, line breaks, nested lists and a table.
+func createTestCourseForDocxRichText() *models.Course {
+	return &models.Course{
+		Course: models.CourseInfo{
+			Title:       "Rich Text",
+			Description: "<p>Intro with <u>underlined</u> text.</p><p>Second paragraph</p>",
+			Lessons: []models.Lesson{{
+				Title: "Formatting",
+				Items: []models.Item{
+					{Type: "text", Items: []models.SubItem{{
+						Heading: "<h2>Rich <em>heading</em></h2>",
+						Paragraph: `<p>Some <strong>bold</strong> and <em>italic</em> text, ` +
+							`<a href="https://example.com/docs">a <b>link</b></a> and <a href="javascript:alert(1)">no link</a>.<br>Next line</p>` +
+							"<ul><li>One<ol><li>Nested</li><li>Nested two</li></ol></li><li>Two</li></ul>" +
+							"<table><thead><tr><th>Term</th><th>Meaning</th></tr></thead><tbody><tr><td>Leaf</td><td>Green</td></tr><tr><td>Root</td></tr></tbody></table>",
+					}}},
+					{Type: "knowledgeCheck", Items: []models.SubItem{{
+						Title:    "<p>Which <em>leaf</em> colour?</p>",
+						Answers:  []models.Answer{{Title: "Yes", Correct: true}},
+						Feedback: "<p>Read the <b>docs</b></p>",
+					}}},
+				},
+			}},
+		},
+	}
+}
+
+// TestDocxExporter_RichText tests that formatted HTML becomes formatted
+// runs, hyperlinks, Word lists and tables.
+func TestDocxExporter_RichText(t *testing.T) {
+	parts := exportDocxParts(t, NewDocxExporter(services.NewHTMLCleaner()), createTestCourseForDocxRichText())
+	document := parts["word/document.xml"]
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"bold", `<w:b></w:b></w:rPr><w:t>bold</w:t>`},
+		{"italic", `<w:i></w:i></w:rPr><w:t>italic</w:t>`},
+		{"underline", `<w:u w:val="single"></w:u></w:rPr><w:t>underlined</w:t>`},
+		{"spaces kept", `<w:t xml:space="preserve"> and </w:t>`},
+		{"italic heading text", `<w:b></w:b><w:i></w:i></w:rPr><w:t>heading</w:t>`},
+		{"hyperlink", `<w:hyperlink r:id=`},
+		{"link text", `<w:color w:val="0563C1"></w:color><w:u w:val="single"></w:u></w:rPr><w:t xml:space="preserve">a </w:t>`},
+		{"bold link text", `<w:b></w:b><w:color w:val="0563C1"></w:color><w:u w:val="single"></w:u></w:rPr><w:t>link</w:t>`},
+		{"script link as text", `<w:rPr></w:rPr><w:t>no link</w:t>`},
+		{"line break", `<w:br w:type="textWrapping">`},
+		{"text after line break", `<w:br w:type="textWrapping"></w:br></w:r><w:r><w:rPr></w:rPr><w:t>Next line</w:t>`},
+		{"second paragraph", `<w:p><w:r><w:rPr></w:rPr><w:t>Second paragraph</w:t>`},
+		{"bulleted list", `<w:pStyle w:val="ListBullet">`},
+		{"nested list level", `<w:ilvl w:val="1">`},
+		{"table", "<w:tbl>"},
+		{"header cell", `<w:b></w:b></w:rPr><w:t>Term</w:t>`},
+		{"table cell", `</w:tcPr><w:p><w:r><w:rPr></w:rPr><w:t>Green</w:t>`},
+		{"question title", `<w:p><w:r><w:rPr><w:b></w:b></w:rPr><w:t xml:space="preserve">Which </w:t>`},
+		{"question title formatting", `<w:b></w:b><w:i></w:i></w:rPr><w:t>leaf</w:t>`},
+		{"feedback formatting", `<w:b></w:b><w:i></w:i></w:rPr><w:t>docs</w:t>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(document, tt.expected) {
+				t.Errorf("Expected document to contain '%s'", tt.expected)
+			}
+		})
+	}
+
+	if strings.Contains(document, "<strong>") || strings.Contains(document, "&lt;b&gt;") || strings.Contains(document, "&lt;p&gt;") {
+		t.Error("Expected no HTML tags in the document")
+	}
+	if strings.Contains(document, "<w:instrText>") {
+		t.Error("Expected link text to be visible, not a field instruction")
+	}
+	if strings.Count(document, "<w:tr>") != 3 || strings.Count(document, "<w:tc>") != 6 {
+		t.Error("Expected a table of 3 rows with 2 cells each")
+	}
+
+	rels := parts["word/_rels/document.xml.rels"]
+	if !strings.Contains(rels, `Target="https://example.com/docs" TargetMode="External"`) {
+		t.Error("Expected an external hyperlink relationship")
+	}
+	if strings.Contains(rels, "javascript:") {
+		t.Error("Expected script links to be dropped")
+	}
+
+	// The nested numbered list restarts at its own level
+	if !strings.Contains(parts["word/numbering.xml"], `<w:lvlOverride w:ilvl="1">`) {
+		t.Error("Expected the nested list to restart at level 1")
+	}
+	if !strings.Contains(parts["word/numbering.xml"], `<w:numFmt w:val="lowerLetter"/>`) {
+		t.Error("Expected nested levels to use their own number format")
+	}
+}
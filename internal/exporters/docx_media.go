@@ -0,0 +1,237 @@
+package exporters
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"  // Register GIF for reading image sizes
+	_ "image/jpeg" // Register JPEG for reading image sizes
+	_ "image/png"  // Register PNG for reading image sizes
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/fumiama/go-docx"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// Image sizes in DOCX documents, in EMU (English Metric Units).
+const (
+	// docxMaxImageWidth is the text width of a Letter or A4 page with one
+	// inch margins, rounded down to 6 inches; wider images are scaled to it
+	docxMaxImageWidth = 6 * 914400
+	// docxEMUPerPixel converts image pixels to EMU at 96 DPI
+	docxEMUPerPixel = 9525
+)
+
+// docxMaxMediaSize limits the size of an image embedded in a document.
+const docxMaxMediaSize = 20 << 20 // 20 MiB
+
+// docxImageTypes are the image formats Word can display.
+var docxImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+}
+
+// exportMedia adds the image or video of a block or flashcard side. Images
+// are embedded; if an image cannot be loaded its URL is shown instead. Videos
+// are shown by their poster image with a link to the video.
+//
+// Parameters:
+//   - doc: The Word document being created
+//   - media: The media to add
+func (e *DocxExporter) exportMedia(doc *docxDocument, media *models.Media) {
+	if img := media.Image; img != nil {
+		para := doc.AddParagraph()
+		if !e.addImage(para, doc, img.Width, img.Height, img.Key, img.OriginalURL) && img.OriginalURL != "" {
+			addText(para, "Image: "+img.OriginalURL)
+		}
+	}
+
+	if video := media.Video; video != nil {
+		if video.Poster != "" {
+			e.addImage(doc.AddParagraph(), doc, 0, 0, video.Poster)
+		}
+
+		link := video.OriginalURL
+		if link == "" {
+			link = video.URL
+		}
+		if link != "" {
+			para := doc.AddParagraph()
+			addText(para, "Video: ")
+			addLink(para, link, link)
+		}
+	}
+}
+
+// addImage embeds an image centred in a paragraph, scaled down to the page
+// width if it is wider.
+//
+// Parameters:
+//   - para: The paragraph the image is added to
+//   - doc: The Word document being created
+//   - width: The pixel width of the image, or 0 if unknown
+//   - height: The pixel height of the image, or 0 if unknown
+//   - keys: The keys and URLs the image may be found by, in order of preference
+//
+// Returns:
+//   - true if the image was added, false if it could not be loaded
+func (e *DocxExporter) addImage(para *docx.Paragraph, doc *docxDocument, width, height int, keys ...string) bool {
+	data := e.loadImage(doc, keys)
+	if data == nil {
+		return false
+	}
+	run, err := para.AddInlineDrawing(data)
+	if err != nil {
+		return false
+	}
+	para.Justification("center")
+
+	// Prefer the size recorded in the course over the pixel size of the image.
+	// The library sizes images to fill the page, so its extent is not used.
+	if width <= 0 || height <= 0 {
+		if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+			width, height = config.Width, config.Height
+		}
+	}
+	for _, child := range run.Children {
+		if drawing, ok := child.(*docx.Drawing); ok && drawing.Inline != nil {
+			cx, cy := drawing.Inline.Extent.CX, drawing.Inline.Extent.CY
+			if width > 0 && height > 0 {
+				cx, cy = int64(width)*docxEMUPerPixel, int64(height)*docxEMUPerPixel
+			}
+			if cx > docxMaxImageWidth {
+				cx, cy = docxMaxImageWidth, cy*docxMaxImageWidth/cx
+			}
+			drawing.Inline.Size(cx, cy)
+		}
+	}
+	return true
+}
+
+// loadImage returns an image from the course's source package, the media
+// directory or the web, in that order. Results are cached, so an image used
+// several times is only loaded once.
+//
+// Parameters:
+//   - doc: The Word document being created
+//   - keys: The keys and URLs the image may be found by, in order of preference
+//
+// Returns:
+//   - The image data, or nil if no source has it in a format Word can display
+func (e *DocxExporter) loadImage(doc *docxDocument, keys []string) []byte {
+	cacheKey := strings.Join(keys, "\n")
+	if data, ok := doc.images[cacheKey]; ok {
+		return data
+	}
+
+	var data []byte
+	for _, source := range []func(key string) io.ReadCloser{
+		func(key string) io.ReadCloser {
+			rc, err := services.OpenPackageMedia(doc.pkg, key)
+			if err != nil {
+				return nil
+			}
+			return rc
+		},
+		e.openMediaFile,
+		e.downloadMedia,
+	} {
+		for _, key := range keys {
+			if key == "" {
+				continue
+			}
+			if data = readDocxImage(source(key)); data != nil {
+				doc.images[cacheKey] = data
+				return data
+			}
+		}
+	}
+
+	doc.images[cacheKey] = nil
+	return nil
+}
+
+// openMediaFile opens a media file from the media directory. Only the file
+// name of the key or URL is used, so keys cannot point outside the directory.
+//
+// Parameters:
+//   - key: The media key or URL
+//
+// Returns:
+//   - The file, or nil if there is no media directory or it lacks the file
+func (e *DocxExporter) openMediaFile(key string) io.ReadCloser {
+	if e.mediaDir == "" {
+		return nil
+	}
+	name := key
+	if u, err := url.Parse(key); err == nil && u.Scheme != "" {
+		name = u.Path
+	}
+	name = path.Base(name)
+	if name == "." || name == "/" {
+		return nil
+	}
+
+	// #nosec G304 - The directory is chosen by the user and the name has no directory part
+	file, err := os.Open(filepath.Join(e.mediaDir, name))
+	if err != nil {
+		return nil
+	}
+	return file
+}
+
+// downloadMedia downloads a media file.
+//
+// Parameters:
+//   - key: The media key or URL; keys that are not HTTP URLs are skipped
+//
+// Returns:
+//   - The response body, or nil if the download failed
+func (e *DocxExporter) downloadMedia(key string) io.ReadCloser {
+	if !strings.HasPrefix(key, "http://") && !strings.HasPrefix(key, "https://") {
+		return nil
+	}
+	resp, err := e.client.Get(key) // #nosec G107 - URL comes from the course data
+	if err != nil {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close() // The body is not read; close errors are irrelevant
+		return nil
+	}
+	return resp.Body
+}
+
+// readDocxImage reads and closes an image, keeping it only if it is in a
+// format Word can display and within docxMaxMediaSize.
+//
+// Parameters:
+//   - rc: The image to read, or nil
+//
+// Returns:
+//   - The image data, or nil
+func readDocxImage(rc io.ReadCloser) []byte {
+	if rc == nil {
+		return nil
+	}
+	defer func() {
+		_ = rc.Close() // Read-only source; close errors cannot affect the data read
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(rc, docxMaxMediaSize+1))
+	if err != nil || len(data) > docxMaxMediaSize {
+		return nil
+	}
+	if !docxImageTypes[http.DetectContentType(data)] {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,238 @@
+package exporters
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// createTestPNG returns a PNG image of the given size.
+func createTestPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// newTestMediaServer starts a server that serves a PNG image below /media/
+// and counts the requests it receives.
+func newTestMediaServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	data := createTestPNG(t, 4, 2)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !strings.HasPrefix(r.URL.Path, "/media/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// createTestCourseForDocxMedia returns a course with an image, a video, a
+// flashcard image and a cover image served by the given server.
+func createTestCourseForDocxMedia(baseURL string) *models.Course {
+	return &models.Course{
+		Course: models.CourseInfo{
+			Title:      "Media",
+			CoverImage: &models.Media{Image: &models.ImageMedia{OriginalURL: baseURL + "/media/cover.png"}},
+			Lessons: []models.Lesson{{
+				Title: "Lesson",
+				Items: []models.Item{
+					{
+						Type: "image",
+						Items: []models.SubItem{{
+							Caption: "<p>A <b>wide</b> diagram</p>",
+							Media: &models.Media{Image: &models.ImageMedia{
+								Key: "assets/diagram.png", OriginalURL: baseURL + "/media/diagram.png", Width: 1600, Height: 800,
+							}},
+						}},
+					},
+					{
+						Type: "multimedia",
+						Items: []models.SubItem{{
+							Media: &models.Media{Video: &models.VideoMedia{
+								Poster: baseURL + "/media/poster.png", OriginalURL: "https://example.com/intro.mp4",
+							}},
+						}},
+					},
+					{
+						Type: "flashcard",
+						Items: []models.SubItem{{
+							Front: &models.CardSide{
+								Description: "<p>Leaf</p>",
+								Media:       &models.Media{Image: &models.ImageMedia{OriginalURL: baseURL + "/media/leaf.png", Width: 200, Height: 100}},
+							},
+							Back: &models.CardSide{Description: "<p>Green</p>"},
+						}},
+					},
+				},
+			}},
+		},
+	}
+}
+
+// countDocxMedia returns the number of media parts in a document.
+func countDocxMedia(parts map[string]string) int {
+	count := 0
+	for name := range parts {
+		if strings.HasPrefix(name, "word/media/") {
+			count++
+		}
+	}
+	return count
+}
+
+// TestDocxExporter_Media tests that images and video posters are downloaded,
+// embedded and scaled, with captions and video links.
+func TestDocxExporter_Media(t *testing.T) {
+	server, _ := newTestMediaServer(t)
+	parts := exportDocxParts(t, NewDocxExporter(services.NewHTMLCleaner()), createTestCourseForDocxMedia(server.URL))
+	document := parts["word/document.xml"]
+
+	if count := countDocxMedia(parts); count != 4 {
+		t.Errorf("Expected 4 embedded images, got %d", count)
+	}
+	for name, data := range parts {
+		if strings.HasPrefix(name, "word/media/") && data != string(createTestPNG(t, 4, 2)) {
+			t.Errorf("Expected %s to be the served image", name)
+		}
+	}
+	if got := strings.Count(document, `<wp:extent cx="38100" cy="19050">`); got != 2 {
+		t.Errorf("Expected the cover image and video poster at their pixel size, got %d", got)
+	}
+	if strings.Contains(document, "<w:instrText>") {
+		t.Error("Expected the video link text to be visible, not a field instruction")
+	}
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"wide image scaled to page width", `<wp:extent cx="5486400" cy="2743200">`},
+		{"small image keeps its size", `<wp:extent cx="1905000" cy="952500">`},
+		{"unsized image uses its pixel size", `<wp:extent cx="38100" cy="19050">`},
+		{"image centred", `<w:jc w:val="center">`},
+		{"caption style", `<w:pStyle w:val="Caption">`},
+		{"caption text", `<w:t xml:space="preserve"> diagram</w:t>`},
+		{"caption formatting", `<w:b></w:b></w:rPr><w:t>wide</w:t>`},
+		{"video label", `<w:t xml:space="preserve">Video: </w:t>`},
+		{"video link text", `<w:u w:val="single"></w:u></w:rPr><w:t>https://example.com/intro.mp4</w:t>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(document, tt.expected) {
+				t.Errorf("Expected document to contain '%s'", tt.expected)
+			}
+		})
+	}
+
+	if strings.Contains(document, "Image: ") {
+		t.Error("Expected embedded images not to be shown as links")
+	}
+	if !strings.Contains(parts["word/_rels/document.xml.rels"], `Target="https://example.com/intro.mp4" TargetMode="External"`) {
+		t.Error("Expected a hyperlink to the original video")
+	}
+}
+
+// TestDocxExporter_MediaFallback tests that images which cannot be
+// downloaded are shown by their URL.
+func TestDocxExporter_MediaFallback(t *testing.T) {
+	server, _ := newTestMediaServer(t)
+	notImage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("<html>Not an image</html>"))
+	}))
+	t.Cleanup(notImage.Close)
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"not found", server.URL + "/missing.png"},
+		{"not an image", notImage.URL + "/media/page.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			course := &models.Course{Course: models.CourseInfo{
+				Title: "Fallback",
+				Lessons: []models.Lesson{{Items: []models.Item{{
+					Type:  "image",
+					Items: []models.SubItem{{Media: &models.Media{Image: &models.ImageMedia{OriginalURL: tt.url}}}},
+				}}}},
+			}}
+
+			parts := exportDocxParts(t, NewDocxExporter(services.NewHTMLCleaner()), course)
+			if count := countDocxMedia(parts); count != 0 {
+				t.Errorf("Expected no embedded images, got %d", count)
+			}
+			if !strings.Contains(parts["word/document.xml"], "Image: "+tt.url) {
+				t.Errorf("Expected document to contain 'Image: %s'", tt.url)
+			}
+		})
+	}
+}
+
+// TestDocxExporter_MediaDir tests that images are read from the media
+// directory before they are downloaded.
+func TestDocxExporter_MediaDir(t *testing.T) {
+	server, requests := newTestMediaServer(t)
+	dir := t.TempDir()
+	for _, name := range []string{"cover.png", "diagram.png", "poster.png", "leaf.png"} {
+		writeTestFile(t, dir, name, string(createTestPNG(t, 2, 2)))
+	}
+
+	exporter, err := NewDocxExporterWithOptions(services.NewHTMLCleaner(), DocxOptions{MediaDir: dir})
+	if err != nil {
+		t.Fatalf("NewDocxExporterWithOptions failed: %v", err)
+	}
+	parts := exportDocxParts(t, exporter, createTestCourseForDocxMedia(server.URL))
+
+	if count := countDocxMedia(parts); count != 4 {
+		t.Errorf("Expected 4 embedded images, got %d", count)
+	}
+	for name, data := range parts {
+		if strings.HasPrefix(name, "word/media/") && data != string(createTestPNG(t, 2, 2)) {
+			t.Errorf("Expected %s to be read from the media directory", name)
+		}
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("Expected no downloads, got %d", n)
+	}
+}
+
+// TestDocxExporter_MediaCache tests that an image used several times is
+// downloaded once.
+func TestDocxExporter_MediaCache(t *testing.T) {
+	server, requests := newTestMediaServer(t)
+	logo := &models.Media{Image: &models.ImageMedia{OriginalURL: server.URL + "/media/logo.png"}}
+	course := &models.Course{Course: models.CourseInfo{
+		Title:      "Cache",
+		CoverImage: logo,
+		Lessons: []models.Lesson{{Items: []models.Item{{
+			Type:  "image",
+			Items: []models.SubItem{{Media: logo}, {Media: logo}},
+		}}}},
+	}}
+
+	parts := exportDocxParts(t, NewDocxExporter(services.NewHTMLCleaner()), course)
+	if count := countDocxMedia(parts); count != 3 {
+		t.Errorf("Expected 3 embedded images, got %d", count)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected 1 download, got %d", n)
+	}
+}
@@ -0,0 +1,657 @@
+package exporters
+
+import (
+	"archive/zip"
+	"bytes"
+	_ "embed"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+//go:embed docx_styles.xml
+var docxStyles string
+
+//go:embed docx_parts.gotmpl
+var docxPartsTemplate string
+
+// docxParts renders the numbering definitions and the table of contents.
+var docxParts = template.Must(template.New("docx").Parse(docxPartsTemplate))
+
+// Part names of a Word document package.
+const (
+	docxContentTypesPart = "[Content_Types].xml"
+	docxDocumentPart     = "word/document.xml"
+	docxDocumentRelsPart = "word/_rels/document.xml.rels"
+	docxStylesPart       = "word/styles.xml"
+	docxNumberingPart    = "word/numbering.xml"
+)
+
+// Content and relationship types of the parts the exporter adds or changes.
+const (
+	docxDocumentType     = "application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"
+	docxStylesType       = "application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"
+	docxNumberingType    = "application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"
+	docxStylesRelType    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"
+	docxNumberingRelType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering"
+	docxExternalTarget   = "External"
+)
+
+// docxDefaultParagraphStyle marks the style of paragraphs without a style.
+const docxDefaultParagraphStyle = `w:type="paragraph" w:default="1"`
+
+var (
+	// docxRelIDAttr matches the relationship ID attributes of the document body.
+	docxRelIDAttr = regexp.MustCompile(`(\sr:(?:id|embed|link)=")([^"]*)(")`)
+	// docxNumIDAttr matches the numbering IDs of list paragraphs.
+	docxNumIDAttr = regexp.MustCompile(`(<w:numId w:val=")(\d+)(")`)
+	// docxStyleElement matches a style definition and captures its ID.
+	docxStyleElement = regexp.MustCompile(`(?s)<w:style\s[^>]*w:styleId="([^"]+)".*?</w:style>`)
+	// docxAbstractNumIDAttr and docxNumIDDef match the IDs of existing numbering definitions.
+	docxAbstractNumIDAttr = regexp.MustCompile(`<w:abstractNum\s[^>]*w:abstractNumId="(\d+)"`)
+	docxNumIDDef          = regexp.MustCompile(`<w:num\s[^>]*w:numId="(\d+)"`)
+	// docxFirstNum matches the first numbering instance, which follows the abstract definitions.
+	docxFirstNum = regexp.MustCompile(`<w:num[\s>]|<w:numIdMacAtCleanup[\s>/]|</w:numbering>`)
+)
+
+// docxDefaultContentTypes are the content types of a package that has no
+// [Content_Types].xml yet.
+var docxDefaultContentTypes = []docxDefaultType{
+	{Extension: "rels", ContentType: "application/vnd.openxmlformats-package.relationships+xml"},
+	{Extension: "xml", ContentType: "application/xml"},
+}
+
+// docxPackage is a Word document as the parts of its zip archive, in
+// archive order.
+type docxPackage struct {
+	names []string
+	parts map[string][]byte
+}
+
+// docxRelationships is a relationships part such as word/_rels/document.xml.rels.
+type docxRelationships struct {
+	XMLName       xml.Name           `xml:"http://schemas.openxmlformats.org/package/2006/relationships Relationships"`
+	Relationships []docxRelationship `xml:"Relationship"`
+}
+
+// docxRelationship links a part to another part or an external target.
+type docxRelationship struct {
+	ID         string `xml:"Id,attr"`
+	Type       string `xml:"Type,attr"`
+	Target     string `xml:"Target,attr"`
+	TargetMode string `xml:"TargetMode,attr,omitempty"`
+}
+
+// docxContentTypes is the [Content_Types].xml part.
+type docxContentTypes struct {
+	XMLName   xml.Name              `xml:"http://schemas.openxmlformats.org/package/2006/content-types Types"`
+	Defaults  []docxDefaultType     `xml:"Default"`
+	Overrides []docxOverrideContent `xml:"Override"`
+}
+
+// docxDefaultType is the content type of the parts with an extension.
+type docxDefaultType struct {
+	Extension   string `xml:"Extension,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+// docxOverrideContent is the content type of a single part.
+type docxOverrideContent struct {
+	PartName    string `xml:"PartName,attr"`
+	ContentType string `xml:"ContentType,attr"`
+}
+
+// docxNumberingData is the data of the numbering templates.
+type docxNumberingData struct {
+	// Bullet, Number and Checkbox are the abstract numbering IDs of the list styles
+	Bullet, Number, Checkbox int
+	Levels                   []docxNumberingLevel
+	Lists                    []docxNumberingList
+}
+
+// docxNumberingLevel is a nesting level of the list styles.
+type docxNumberingLevel struct {
+	Level  int
+	Indent int // twips
+	// Bullet is the marker of bulleted lists
+	Bullet string
+	// NumberFormat and NumberText are the numbering of numbered lists
+	NumberFormat, NumberText string
+}
+
+// docxNumberingList is the numbering instance of a single list.
+type docxNumberingList struct {
+	ID       int
+	Abstract int
+	// Level is the nesting level restarted by Restart
+	Level   int
+	Restart bool
+}
+
+// docxNumberingLevels are the nine nesting levels Word supports. Markers and
+// number formats cycle every three levels, as in Word's default lists.
+var docxNumberingLevels = func() []docxNumberingLevel {
+	bullets := []string{"•", "◦", "▪"}
+	formats := []string{"decimal", "lowerLetter", "lowerRoman"}
+	levels := make([]docxNumberingLevel, docxMaxListLevel+1)
+	for i := range levels {
+		levels[i] = docxNumberingLevel{
+			Level:        i,
+			Indent:       720 * (i + 1),
+			Bullet:       bullets[i%3],
+			NumberFormat: formats[i%3],
+			NumberText:   "%" + strconv.Itoa(i+1) + ".",
+		}
+	}
+	return levels
+}()
+
+// readDocxPackage reads the parts of a Word document.
+//
+// Parameters:
+//   - data: The content of a .docx or .dotx file
+//
+// Returns:
+//   - The parts of the document
+//   - An error if data is not a zip archive or has no main document part
+func readDocxPackage(data []byte) (*docxPackage, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+
+	pkg := &docxPackage{parts: make(map[string][]byte, len(zr.File))}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+		}
+		part, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		pkg.set(f.Name, part)
+	}
+
+	if pkg.parts[docxDocumentPart] == nil {
+		return nil, fmt.Errorf("missing %s", docxDocumentPart)
+	}
+	return pkg, nil
+}
+
+// set stores a part, adding it to the end of the archive if it is new.
+func (p *docxPackage) set(name string, data []byte) {
+	if _, ok := p.parts[name]; !ok {
+		p.names = append(p.names, name)
+	}
+	p.parts[name] = data
+}
+
+// clone returns a copy of the package that can be changed independently.
+// The part contents are shared, since parts are replaced rather than modified.
+func (p *docxPackage) clone() *docxPackage {
+	c := &docxPackage{names: append([]string(nil), p.names...), parts: make(map[string][]byte, len(p.parts))}
+	for name, data := range p.parts {
+		c.parts[name] = data
+	}
+	return c
+}
+
+// bytes writes the package as a zip archive.
+func (p *docxPackage) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range p.names {
+		fw, err := zw.Create(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if _, err := fw.Write(p.parts[name]); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write document: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// readXML decodes an XML part into v.
+func (p *docxPackage) readXML(name string, v any) error {
+	if err := xml.Unmarshal(p.parts[name], v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeXML encodes v into an XML part.
+func (p *docxPackage) writeXML(name string, v any) error {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	p.set(name, append([]byte(xml.Header), data...))
+	return nil
+}
+
+// addPart adds a part that the main document refers to, along with its
+// content type and relationship. If the main document already has a
+// relationship of that type, the part is stored at its target instead of
+// adding a second relationship.
+//
+// Parameters:
+//   - name: The part name, such as "word/numbering.xml"
+//   - contentType: The content type of the part
+//   - relType: The type of the relationship from the main document
+//   - data: The content of the part
+//
+// Returns:
+//   - An error if the content types or relationships cannot be updated
+func (p *docxPackage) addPart(name, contentType, relType string, data []byte) error {
+	var rels docxRelationships
+	if p.parts[docxDocumentRelsPart] != nil {
+		if err := p.readXML(docxDocumentRelsPart, &rels); err != nil {
+			return err
+		}
+	}
+	related := false
+	for _, rel := range rels.Relationships {
+		if rel.Type == relType && rel.TargetMode != docxExternalTarget {
+			name, related = docxPartName(rel.Target), true
+			break
+		}
+	}
+	if !related {
+		rels.Relationships = append(rels.Relationships, docxRelationship{
+			ID:     unusedRelID(rels.Relationships, "rIdExport"),
+			Type:   relType,
+			Target: strings.TrimPrefix(name, "word/"),
+		})
+		if err := p.writeXML(docxDocumentRelsPart, &rels); err != nil {
+			return err
+		}
+	}
+
+	types := docxContentTypes{Defaults: docxDefaultContentTypes}
+	if p.parts[docxContentTypesPart] != nil {
+		types = docxContentTypes{}
+		if err := p.readXML(docxContentTypesPart, &types); err != nil {
+			return err
+		}
+	} else {
+		setOverride(&types, "/"+docxDocumentPart, docxDocumentType)
+	}
+	setOverride(&types, "/"+name, contentType)
+	if err := p.writeXML(docxContentTypesPart, &types); err != nil {
+		return err
+	}
+
+	p.set(name, data)
+	return nil
+}
+
+// withReference returns a package with the body of p and everything else of
+// the reference document: styles, numbering, headers, footers, settings and
+// the page setup of the final section. The hyperlinks and images of the body
+// are carried over under new relationship IDs.
+//
+// Parameters:
+//   - ref: The reference document
+//
+// Returns:
+//   - The combined package
+//   - An error if a part of either package cannot be parsed
+func (p *docxPackage) withReference(ref *docxPackage) (*docxPackage, error) {
+	out := ref.clone()
+
+	var bodyRels, refRels docxRelationships
+	if err := p.readXML(docxDocumentRelsPart, &bodyRels); err != nil {
+		return nil, err
+	}
+	if ref.parts[docxDocumentRelsPart] != nil {
+		if err := ref.readXML(docxDocumentRelsPart, &refRels); err != nil {
+			return nil, err
+		}
+	}
+	var bodyTypes, types docxContentTypes
+	if err := p.readXML(docxContentTypesPart, &bodyTypes); err != nil {
+		return nil, err
+	}
+	if err := ref.readXML(docxContentTypesPart, &types); err != nil {
+		return nil, err
+	}
+
+	// Carry over the relationships the body uses, renaming parts that
+	// collide with the reference's
+	document := p.parts[docxDocumentPart]
+	renamed := map[string]string{}
+	for _, match := range docxRelIDAttr.FindAllSubmatch(document, -1) {
+		id := string(match[2])
+		if _, ok := renamed[id]; ok {
+			continue
+		}
+		for _, rel := range bodyRels.Relationships {
+			if rel.ID != id {
+				continue
+			}
+			if rel.TargetMode != docxExternalTarget {
+				source := docxPartName(rel.Target)
+				target := unusedPartName(out, source)
+				out.set(target, p.parts[source])
+				addContentType(&types, &bodyTypes, source, target)
+				rel.Target = strings.TrimPrefix(target, "word/")
+			}
+			rel.ID = unusedRelID(refRels.Relationships, "rIdExport")
+			refRels.Relationships = append(refRels.Relationships, rel)
+			renamed[id] = rel.ID
+		}
+	}
+	document = docxRelIDAttr.ReplaceAllFunc(document, func(attr []byte) []byte {
+		match := docxRelIDAttr.FindSubmatch(attr)
+		if id, ok := renamed[string(match[2])]; ok {
+			return []byte(string(match[1]) + id + string(match[3]))
+		}
+		return attr
+	})
+
+	// The final section properties hold the page setup and the header and
+	// footer references
+	start, end, err := docxBodySection(document)
+	if err != nil {
+		return nil, err
+	}
+	refStart, refEnd, err := docxBodySection(ref.parts[docxDocumentPart])
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference document: %w", err)
+	}
+	section := ref.parts[docxDocumentPart][refStart:refEnd]
+	document = append(append(append([]byte{}, document[:start]...), section...), document[end:]...)
+	out.set(docxDocumentPart, document)
+
+	// A template's main part must become a document's
+	setOverride(&types, "/"+docxDocumentPart, docxDocumentType)
+	if err := out.writeXML(docxContentTypesPart, &types); err != nil {
+		return nil, err
+	}
+	if err := out.writeXML(docxDocumentRelsPart, &refRels); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// addStyles adds the built-in styles from docx_styles.xml that the styles
+// part does not define yet, so the headings and lists render as intended
+// with any reference document.
+func (p *docxPackage) addStyles() error {
+	styles := p.parts[docxStylesPart]
+	if styles == nil {
+		return p.addPart(docxStylesPart, docxStylesType, docxStylesRelType, []byte(docxStyles))
+	}
+
+	// A package can only have one default paragraph style
+	hasDefault := bytes.Contains(styles, []byte(docxDefaultParagraphStyle))
+	var missing []byte
+	for _, match := range docxStyleElement.FindAllStringSubmatch(docxStyles, -1) {
+		if !bytes.Contains(styles, []byte(`w:styleId="`+match[1]+`"`)) {
+			style := match[0]
+			if hasDefault {
+				style = strings.Replace(style, docxDefaultParagraphStyle, `w:type="paragraph"`, 1)
+			}
+			missing = append(missing, style...)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	end := bytes.LastIndex(styles, []byte("</w:styles>"))
+	if end < 0 {
+		return fmt.Errorf("invalid %s", docxStylesPart)
+	}
+	p.set(docxStylesPart, append(append(append([]byte{}, styles[:end]...), missing...), styles[end:]...))
+	return nil
+}
+
+// addNumbering adds the numbering definitions of the lists and points the
+// list paragraphs at them. List i of the document uses numbering ID i+1,
+// which is shifted past the IDs the numbering part already defines.
+//
+// Parameters:
+//   - lists: The style and nesting level of each list of the document
+//
+// Returns:
+//   - An error if the numbering part cannot be updated
+func (p *docxPackage) addNumbering(lists []docxList) error {
+	if len(lists) == 0 {
+		return nil
+	}
+
+	numbering := p.parts[docxNumberingPart]
+	abstractBase := maxSubmatchInt(docxAbstractNumIDAttr, numbering) + 1
+	numBase := maxSubmatchInt(docxNumIDDef, numbering)
+
+	data := docxNumberingData{Bullet: abstractBase, Number: abstractBase + 1, Checkbox: abstractBase + 2, Levels: docxNumberingLevels}
+	for i, l := range lists {
+		list := docxNumberingList{ID: numBase + i + 1, Abstract: data.Bullet, Level: l.level}
+		switch l.style {
+		case models.ListStyleNumbered:
+			list.Abstract, list.Restart = data.Number, true
+		case models.ListStyleCheckboxes:
+			list.Abstract = data.Checkbox
+		}
+		data.Lists = append(data.Lists, list)
+	}
+
+	if numBase > 0 {
+		document := docxNumIDAttr.ReplaceAllFunc(p.parts[docxDocumentPart], func(attr []byte) []byte {
+			match := docxNumIDAttr.FindSubmatch(attr)
+			id, _ := strconv.Atoi(string(match[2]))
+			return []byte(string(match[1]) + strconv.Itoa(numBase+id) + string(match[3]))
+		})
+		p.set(docxDocumentPart, document)
+	}
+
+	if numbering == nil {
+		var buf bytes.Buffer
+		if err := docxParts.ExecuteTemplate(&buf, "numbering", data); err != nil {
+			return fmt.Errorf("failed to render numbering: %w", err)
+		}
+		return p.addPart(docxNumberingPart, docxNumberingType, docxNumberingRelType, buf.Bytes())
+	}
+
+	// Abstract definitions must precede all numbering instances
+	var abstract, nums bytes.Buffer
+	if err := docxParts.ExecuteTemplate(&abstract, "abstractNums", data); err != nil {
+		return fmt.Errorf("failed to render numbering: %w", err)
+	}
+	if err := docxParts.ExecuteTemplate(&nums, "nums", data); err != nil {
+		return fmt.Errorf("failed to render numbering: %w", err)
+	}
+	first := docxFirstNum.FindIndex(numbering)
+	last := bytes.Index(numbering, []byte("<w:numIdMacAtCleanup"))
+	if last < 0 {
+		last = bytes.LastIndex(numbering, []byte("</w:numbering>"))
+	}
+	if first == nil || last < 0 {
+		return fmt.Errorf("invalid %s", docxNumberingPart)
+	}
+
+	var out bytes.Buffer
+	out.Write(numbering[:first[0]])
+	out.Write(abstract.Bytes())
+	out.Write(numbering[first[0]:last])
+	out.Write(nums.Bytes())
+	out.Write(numbering[last:])
+	p.set(docxNumberingPart, out.Bytes())
+	return nil
+}
+
+// insertTOC replaces the paragraph holding docxTOCMarker with a table of
+// contents field covering headings 1 to 3. The field is marked dirty, so Word
+// fills it in when the document is opened.
+func (p *docxPackage) insertTOC() error {
+	document := p.parts[docxDocumentPart]
+	at := bytes.Index(document, []byte(docxTOCMarker))
+	if at < 0 {
+		return errors.New("missing table of contents placeholder")
+	}
+	start := max(bytes.LastIndex(document[:at], []byte("<w:p>")), bytes.LastIndex(document[:at], []byte("<w:p ")))
+	end := bytes.Index(document[at:], []byte("</w:p>"))
+	if start < 0 || end < 0 {
+		return errors.New("missing table of contents placeholder")
+	}
+	end += at + len("</w:p>")
+
+	var toc bytes.Buffer
+	toc.Write(document[:start])
+	if err := docxParts.ExecuteTemplate(&toc, "toc", nil); err != nil {
+		return fmt.Errorf("failed to render table of contents: %w", err)
+	}
+	toc.Write(document[end:])
+	p.set(docxDocumentPart, toc.Bytes())
+	return nil
+}
+
+// docxBodySection finds the section properties at the end of the document
+// body. If there are none, start and end are both the position of the
+// closing body tag, where they would go.
+//
+// Parameters:
+//   - document: The main document part
+//
+// Returns:
+//   - The start and end offsets of the section properties
+//   - An error if the document cannot be parsed or has no body
+func docxBodySection(document []byte) (start, end int, err error) {
+	d := xml.NewDecoder(bytes.NewReader(document))
+	depth, bodyDepth := 0, 0
+	for {
+		offset := int(d.InputOffset())
+		tok, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				return 0, 0, fmt.Errorf("missing body in %s", docxDocumentPart)
+			}
+			return 0, 0, fmt.Errorf("failed to parse %s: %w", docxDocumentPart, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case bodyDepth == 0 && t.Name.Local == "body":
+				bodyDepth = depth
+			case bodyDepth > 0 && depth == bodyDepth+1 && t.Name.Local == "sectPr":
+				if err := d.Skip(); err != nil {
+					return 0, 0, fmt.Errorf("failed to parse %s: %w", docxDocumentPart, err)
+				}
+				return offset, int(d.InputOffset()), nil
+			}
+		case xml.EndElement:
+			if depth == bodyDepth {
+				return offset, offset, nil
+			}
+			depth--
+		}
+	}
+}
+
+// docxPartName returns the part name of a relationship target of the main
+// document, which is relative to the word directory unless it is absolute.
+func docxPartName(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join("word", target)
+}
+
+// unusedPartName returns name, or a variant of it with a numbered suffix if
+// the package already has a part with that name.
+func unusedPartName(p *docxPackage, name string) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 1; ; i++ {
+		if _, ok := p.parts[name]; !ok {
+			return name
+		}
+		name = fmt.Sprintf("%s-export%d%s", base, i, ext)
+	}
+}
+
+// unusedRelID returns the first ID of the form prefix1, prefix2, ... that
+// none of the relationships uses.
+func unusedRelID(rels []docxRelationship, prefix string) string {
+	for i := 1; ; i++ {
+		id := prefix + strconv.Itoa(i)
+		used := false
+		for _, rel := range rels {
+			used = used || rel.ID == id
+		}
+		if !used {
+			return id
+		}
+	}
+}
+
+// addContentType gives the copied part target the content type that source
+// has in from, unless types already covers it.
+func addContentType(types, from *docxContentTypes, source, target string) {
+	ext := strings.TrimPrefix(path.Ext(target), ".")
+	for _, o := range types.Overrides {
+		if strings.EqualFold(o.PartName, "/"+target) {
+			return
+		}
+	}
+	for _, d := range types.Defaults {
+		if strings.EqualFold(d.Extension, ext) {
+			return
+		}
+	}
+
+	for _, o := range from.Overrides {
+		if strings.EqualFold(o.PartName, "/"+source) {
+			types.Overrides = append(types.Overrides, docxOverrideContent{PartName: "/" + target, ContentType: o.ContentType})
+			return
+		}
+	}
+	for _, d := range from.Defaults {
+		if strings.EqualFold(d.Extension, ext) {
+			types.Defaults = append(types.Defaults, d)
+			return
+		}
+	}
+}
+
+// setOverride sets the content type of a single part.
+func setOverride(types *docxContentTypes, partName, contentType string) {
+	for i := range types.Overrides {
+		if strings.EqualFold(types.Overrides[i].PartName, partName) {
+			types.Overrides[i].ContentType = contentType
+			return
+		}
+	}
+	types.Overrides = append(types.Overrides, docxOverrideContent{PartName: partName, ContentType: contentType})
+}
+
+// maxSubmatchInt returns the largest integer captured by re in data, or 0.
+func maxSubmatchInt(re *regexp.Regexp, data []byte) int {
+	maxValue := 0
+	for _, match := range re.FindAllSubmatch(data, -1) {
+		if v, err := strconv.Atoi(string(match[1])); err == nil && v > maxValue {
+			maxValue = v
+		}
+	}
+	return maxValue
+}
@@ -0,0 +1,237 @@
+package exporters
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// writeTestZip returns a zip archive with the given files, in order.
+func writeTestZip(t *testing.T, files [][2]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range files {
+		fw, err := zw.Create(file[0])
+		if err != nil {
+			t.Fatalf("Failed to create %s: %v", file[0], err)
+		}
+		if _, err := fw.Write([]byte(file[1])); err != nil {
+			t.Fatalf("Failed to write %s: %v", file[0], err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to close archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// createTestReferenceDocx returns a Word template with a header, a logo, a
+// landscape page, its own Heading1 style and existing numbering definitions.
+func createTestReferenceDocx(t *testing.T) []byte {
+	t.Helper()
+	const w = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+	return writeTestZip(t, [][2]string{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Default Extension="png" ContentType="image/png"/>` +
+			`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.template.main+xml"/>` +
+			`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+			`<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>` +
+			`<Override PartName="/word/header1.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/></Relationships>`},
+		{"word/document.xml", `<?xml version="1.0" encoding="UTF-8"?><w:document ` + w + `><w:body>` +
+			`<w:p><w:pPr><w:sectPr><w:pgSz w:w="11906" w:h="16838"/></w:sectPr></w:pPr><w:r><w:t>Template body</w:t></w:r></w:p>` +
+			`<w:sectPr><w:headerReference w:type="default" r:id="rId2"/><w:pgSz w:w="16838" w:h="11906" w:orient="landscape"/></w:sectPr>` +
+			`</w:body></w:document>`},
+		{"word/_rels/document.xml.rels", `<?xml version="1.0" encoding="UTF-8"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/header" Target="header1.xml"/>` +
+			`<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering" Target="numbering.xml"/>` +
+			`</Relationships>`},
+		{"word/styles.xml", `<?xml version="1.0" encoding="UTF-8"?><w:styles ` + w + `>` +
+			`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+			`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:aliases w:val="Brand heading"/></w:style>` +
+			`</w:styles>`},
+		{"word/numbering.xml", `<?xml version="1.0" encoding="UTF-8"?><w:numbering ` + w + `>` +
+			`<w:abstractNum w:abstractNumId="0"><w:lvl w:ilvl="0"><w:numFmt w:val="upperRoman"/></w:lvl></w:abstractNum>` +
+			`<w:abstractNum w:abstractNumId="2"><w:lvl w:ilvl="0"><w:numFmt w:val="decimal"/></w:lvl></w:abstractNum>` +
+			`<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>` +
+			`<w:num w:numId="8"><w:abstractNumId w:val="2"/></w:num>` +
+			`</w:numbering>`},
+		{"word/header1.xml", `<?xml version="1.0" encoding="UTF-8"?><w:hdr ` + w + `><w:p><w:r><w:t>ACME Training</w:t></w:r></w:p></w:hdr>`},
+		{"word/media/image1.png", "reference logo"},
+	})
+}
+
+// TestDocxPackage_WithReference tests that the hyperlinks and images of the
+// body are carried over to the reference under new IDs and part names.
+func TestDocxPackage_WithReference(t *testing.T) {
+	ref, err := readDocxPackage(createTestReferenceDocx(t))
+	if err != nil {
+		t.Fatalf("Failed to read reference: %v", err)
+	}
+	body, err := readDocxPackage(writeTestZip(t, [][2]string{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="png" ContentType="image/png"/><Default Extension="jpeg" ContentType="image/jpeg"/></Types>`},
+		{"word/document.xml", `<w:document xmlns:w="w" xmlns:r="r"><w:body>` +
+			`<w:p><w:hyperlink r:id="rId5"><w:r><w:t>Link</w:t></w:r></w:hyperlink></w:p>` +
+			`<w:p><w:r><w:drawing><a:blip xmlns:a="a" r:embed="rId6"/></w:drawing></w:r></w:p>` +
+			`<w:p><w:r><w:drawing><a:blip xmlns:a="a" r:embed="rId7"/></w:drawing></w:r></w:p>` +
+			`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/></w:sectPr></w:body></w:document>`},
+		{"word/_rels/document.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+			`<Relationship Id="rId5" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.com/" TargetMode="External"/>` +
+			`<Relationship Id="rId6" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image1.png"/>` +
+			`<Relationship Id="rId7" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image2.jpeg"/>` +
+			`</Relationships>`},
+		{"word/styles.xml", `<w:styles xmlns:w="w"/>`},
+		{"word/media/image1.png", "course image"},
+		{"word/media/image2.jpeg", "course photo"},
+	}))
+	if err != nil {
+		t.Fatalf("Failed to read body: %v", err)
+	}
+
+	out, err := body.withReference(ref)
+	if err != nil {
+		t.Fatalf("withReference failed: %v", err)
+	}
+
+	document := string(out.parts[docxDocumentPart])
+	for _, s := range []string{`r:id="rIdExport1"`, `r:embed="rIdExport2"`, `r:embed="rIdExport3"`, `<w:headerReference w:type="default" r:id="rId2"/>`} {
+		if !strings.Contains(document, s) {
+			t.Errorf("Expected document to contain '%s', got %s", s, document)
+		}
+	}
+	if strings.Contains(document, "11906\" w:h=\"16838\"/></w:sectPr></w:body>") {
+		t.Error("Expected the section properties of the body to be replaced")
+	}
+
+	rels := string(out.parts[docxDocumentRelsPart])
+	for _, s := range []string{
+		`Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/header" Target="header1.xml"`,
+		`Id="rIdExport1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.com/" TargetMode="External"`,
+		`Id="rIdExport2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image1-export1.png"`,
+		`Id="rIdExport3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image2.jpeg"`,
+	} {
+		if !strings.Contains(rels, s) {
+			t.Errorf("Expected relationships to contain '%s', got %s", s, rels)
+		}
+	}
+	if strings.Count(rels, "relationships/styles") != 1 {
+		t.Error("Expected the body's own styles relationship to be dropped")
+	}
+
+	if string(out.parts["word/media/image1.png"]) != "reference logo" || string(out.parts["word/media/image1-export1.png"]) != "course image" {
+		t.Error("Expected colliding images to be renamed instead of replaced")
+	}
+	types := string(out.parts[docxContentTypesPart])
+	if !strings.Contains(types, `<Default Extension="jpeg" ContentType="image/jpeg">`) || strings.Count(types, `Extension="png"`) != 1 {
+		t.Errorf("Expected content types for the carried over images, got %s", types)
+	}
+}
+
+// TestDocxBodySection tests locating the final section properties of a body.
+func TestDocxBodySection(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		expected string
+	}{
+		{
+			name:     "section properties",
+			document: `<w:document xmlns:w="w"><w:body><w:p/><w:sectPr><w:pgSz/></w:sectPr></w:body></w:document>`,
+			expected: `<w:sectPr><w:pgSz/></w:sectPr>`,
+		},
+		{
+			name:     "paragraph sections are skipped",
+			document: `<w:document xmlns:w="w"><w:body><w:p><w:pPr><w:sectPr/></w:pPr></w:p><w:sectPr w:rsidR="1"/></w:body></w:document>`,
+			expected: `<w:sectPr w:rsidR="1"/>`,
+		},
+		{
+			name:     "no section properties",
+			document: `<w:document xmlns:w="w"><w:body><w:p/></w:body></w:document>`,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := docxBodySection([]byte(tt.document))
+			if err != nil {
+				t.Fatalf("docxBodySection failed: %v", err)
+			}
+			if got := tt.document[start:end]; got != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+			}
+			if tt.expected == "" && !strings.HasPrefix(tt.document[start:], "</w:body>") {
+				t.Errorf("Expected the position of the closing body tag, got %d", start)
+			}
+		})
+	}
+
+	if _, _, err := docxBodySection([]byte(`<w:document xmlns:w="w"/>`)); err == nil {
+		t.Error("Expected an error for a document without a body")
+	}
+}
+
+// TestDocxPackage_AddPart tests adding a part to a package without
+// [Content_Types].xml.
+func TestDocxPackage_AddPart(t *testing.T) {
+	pkg, err := readDocxPackage(writeTestZip(t, [][2]string{
+		{"word/document.xml", `<w:document xmlns:w="w"><w:body/></w:document>`},
+	}))
+	if err != nil {
+		t.Fatalf("readDocxPackage failed: %v", err)
+	}
+
+	if err := pkg.addStyles(); err != nil {
+		t.Fatalf("addStyles failed: %v", err)
+	}
+	types := string(pkg.parts[docxContentTypesPart])
+	for _, expected := range []string{
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml">`,
+		`<Default Extension="xml" ContentType="application/xml">`,
+		`<Override PartName="/word/document.xml" ContentType="` + docxDocumentType + `">`,
+		`<Override PartName="/word/styles.xml" ContentType="` + docxStylesType + `">`,
+	} {
+		if !strings.Contains(types, expected) {
+			t.Errorf("Expected content types to contain '%s', got %s", expected, types)
+		}
+	}
+	if !strings.Contains(string(pkg.parts[docxDocumentRelsPart]), `Target="styles.xml"`) {
+		t.Error("Expected a relationship to the styles part")
+	}
+}
+
+// TestDocxPackage_AddPartExistingRelationship tests that a missing part is
+// added at the target of an existing relationship instead of a second one.
+func TestDocxPackage_AddPartExistingRelationship(t *testing.T) {
+	pkg, err := readDocxPackage(writeTestZip(t, [][2]string{
+		{"word/document.xml", `<w:document xmlns:w="w"><w:body/></w:document>`},
+		{"word/_rels/document.xml.rels", `<?xml version="1.0" encoding="UTF-8"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="` + docxStylesRelType + `" Target="styles.xml"/></Relationships>`},
+	}))
+	if err != nil {
+		t.Fatalf("readDocxPackage failed: %v", err)
+	}
+
+	if err := pkg.addStyles(); err != nil {
+		t.Fatalf("addStyles failed: %v", err)
+	}
+	rels := string(pkg.parts[docxDocumentRelsPart])
+	if got := strings.Count(rels, docxStylesRelType); got != 1 {
+		t.Errorf("Expected 1 styles relationship, got %d in %s", got, rels)
+	}
+	if !strings.Contains(rels, `Id="rId1"`) || pkg.parts[docxStylesPart] == nil {
+		t.Error("Expected the styles part to be added at the existing relationship's target")
+	}
+	if got := strings.Count(string(pkg.parts[docxContentTypesPart]), `PartName="/word/styles.xml"`); got != 1 {
+		t.Errorf("Expected 1 content type for the styles part, got %d", got)
+	}
+}
@@ -1,10 +1,15 @@
 package exporters
 
 import (
+	"archive/zip"
+	"bytes"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/kjanat/articulate-parser/internal/interfaces"
 	"github.com/kjanat/articulate-parser/internal/models"
 	"github.com/kjanat/articulate-parser/internal/services"
 )
@@ -115,6 +120,31 @@ func TestDocxExporter_Export_InvalidPath(t *testing.T) {
 	}
 }
 
+// TestDocxExporter_ExportTo tests writing a DOCX document to an io.Writer.
+func TestDocxExporter_ExportTo(t *testing.T) {
+	exporter := NewDocxExporter(services.NewHTMLCleaner())
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(createTestCourseForDocx(), &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	// A DOCX file is a zip archive containing the main document part
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ExportTo output is not a valid zip archive: %v", err)
+	}
+	found := false
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("ExportTo output should contain word/document.xml")
+	}
+}
+
 // TestDocxExporter_ExportLesson tests the exportLesson method indirectly through Export.
 func TestDocxExporter_ExportLesson(t *testing.T) {
 	htmlCleaner := services.NewHTMLCleaner()
@@ -669,3 +699,293 @@ func BenchmarkDocxExporter_ComplexCourse(b *testing.B) {
 		_ = os.Remove(outputPath)
 	}
 }
+
+// TestDocxExporter_ItemVariants tests that list styles and question types shape the document.
+func TestDocxExporter_ItemVariants(t *testing.T) {
+	exporter := NewDocxExporter(services.NewHTMLCleaner())
+	course := &models.Course{
+		Course: models.CourseInfo{
+			Title: "Variants",
+			Lessons: []models.Lesson{{
+				Title: "Lesson",
+				Items: []models.Item{
+					{
+						Type:    "list",
+						Variant: "numbered",
+						Items:   []models.SubItem{{Paragraph: "<p>Step one</p>"}},
+					},
+					{
+						Type:    "knowledgeCheck",
+						Variant: "matching",
+						Items: []models.SubItem{{
+							Title:   "<p>Match the capitals</p>",
+							Answers: []models.Answer{{Title: "France", MatchTitle: "Paris"}},
+						}},
+					},
+				},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ExportTo output is not a valid zip archive: %v", err)
+	}
+	var document string
+	for _, f := range zr.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open document.xml: %v", err)
+		}
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		document = string(data)
+	}
+
+	for _, expected := range []string{`<w:pStyle w:val="ListNumber">`, "Step one", "Matches:", "France → Paris"} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("Expected document to contain '%s'", expected)
+		}
+	}
+}
+
+// TestDocxExporter_Flashcards tests that both sides of a flashcard and their
+// media end up in the document.
+func TestDocxExporter_Flashcards(t *testing.T) {
+	exporter := NewDocxExporter(services.NewHTMLCleaner())
+	course := &models.Course{
+		Course: models.CourseInfo{
+			Title: "Cards",
+			Lessons: []models.Lesson{{
+				Title: "Lesson",
+				Items: []models.Item{{
+					Type: "flashcard",
+					Items: []models.SubItem{{
+						Front: &models.CardSide{Description: "<p>Photosynthesis</p>"},
+						Back: &models.CardSide{
+							Description: "<p>Turning light into energy</p>",
+							Media:       &models.Media{Video: &models.VideoMedia{OriginalURL: "https://example.com/leaf.mp4"}},
+						},
+					}},
+				}},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ExportTo output is not a valid zip archive: %v", err)
+	}
+	var document string
+	for _, f := range zr.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open document.xml: %v", err)
+		}
+		data, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		document = string(data)
+	}
+
+	for _, expected := range []string{
+		`<w:t xml:space="preserve">Front: </w:t>`, "Photosynthesis", `<w:t xml:space="preserve">Back: </w:t>`,
+		"Turning light into energy", "Video: ", "https://example.com/leaf.mp4",
+	} {
+		if !strings.Contains(document, expected) {
+			t.Errorf("Expected document to contain '%s'", expected)
+		}
+	}
+}
+
+// exportDocxParts exports a course with the given exporter and returns the
+// parts of the document by name.
+func exportDocxParts(t *testing.T, exporter interfaces.Exporter, course *models.Course) map[string]string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	parts := map[string]string{}
+	for _, entry := range readZipFiles(t, buf.Bytes()) {
+		parts[entry.name] = entry.data
+	}
+	return parts
+}
+
+// createTestCourseForDocxStyles returns a course with headings and two
+// numbered lists.
+func createTestCourseForDocxStyles() *models.Course {
+	return &models.Course{
+		Course: models.CourseInfo{
+			Title: "Styled Course",
+			Lessons: []models.Lesson{{
+				Title: "Basics",
+				Items: []models.Item{
+					{Type: "text", Items: []models.SubItem{{Heading: "<h2>Overview</h2>", Paragraph: "<p>Body text</p>"}}},
+					{Type: "list", Variant: "numbered", Items: []models.SubItem{{Paragraph: "<p>First</p>"}, {Paragraph: "<p>Second</p>"}}},
+					{Type: "list", Variant: "checkboxes", Items: []models.SubItem{{Paragraph: "<p>Done</p>"}}},
+					{Type: "list", Variant: "numbered", Items: []models.SubItem{{Paragraph: "<p>Again</p>"}}},
+				},
+			}},
+		},
+	}
+}
+
+// TestDocxExporter_Styles tests that headings and lists use Word's styles
+// and numbering instead of direct formatting.
+func TestDocxExporter_Styles(t *testing.T) {
+	parts := exportDocxParts(t, NewDocxExporter(services.NewHTMLCleaner()), createTestCourseForDocxStyles())
+	document := parts["word/document.xml"]
+
+	for _, style := range []string{"Title", "Heading1", "Heading2", "Heading3", "ListNumber", "ListBullet"} {
+		if !strings.Contains(document, `<w:pStyle w:val="`+style+`">`) {
+			t.Errorf("Expected document to use style %s", style)
+		}
+		if !strings.Contains(parts["word/styles.xml"], `w:styleId="`+style+`"`) {
+			t.Errorf("Expected styles.xml to define style %s", style)
+		}
+	}
+	if strings.Contains(document, "<w:sz ") {
+		t.Error("Expected headings to take their size from the styles")
+	}
+	if strings.Contains(document, `">  `) {
+		t.Error("Expected no indentation with leading spaces")
+	}
+
+	// Each list has its own numbering, so the second numbered list restarts
+	numbering := parts["word/numbering.xml"]
+	if numbering == "" {
+		t.Fatal("Expected numbering.xml to be added")
+	}
+	if got := strings.Count(numbering, "<w:num w:numId="); got != 3 {
+		t.Errorf("Expected 3 numbering instances, got %d", got)
+	}
+	if got := strings.Count(numbering, "<w:startOverride "); got != 2 {
+		t.Errorf("Expected both numbered lists to restart, got %d restarts", got)
+	}
+	if !strings.Contains(numbering, `<w:lvlText w:val="☐"/>`) {
+		t.Error("Expected checkbox lists to use a checkbox marker")
+	}
+	if !strings.Contains(parts["word/_rels/document.xml.rels"], "relationships/numbering") ||
+		!strings.Contains(parts["[Content_Types].xml"], "/word/numbering.xml") {
+		t.Error("Expected numbering.xml to be registered in the package")
+	}
+}
+
+// TestDocxExporter_TOC tests the table of contents option.
+func TestDocxExporter_TOC(t *testing.T) {
+	exporter, err := NewDocxExporterWithOptions(services.NewHTMLCleaner(), DocxOptions{TOC: true})
+	if err != nil {
+		t.Fatalf("NewDocxExporterWithOptions failed: %v", err)
+	}
+	document := exportDocxParts(t, exporter, createTestCourseForDocxStyles())["word/document.xml"]
+
+	if !strings.Contains(document, `TOC \o "1-3" \h \z \u`) || !strings.Contains(document, `w:dirty="true"`) {
+		t.Error("Expected a table of contents field that Word updates on opening")
+	}
+	if strings.Contains(document, docxTOCMarker) {
+		t.Error("Expected the placeholder to be replaced")
+	}
+	if strings.Index(document, "Styled Course") > strings.Index(document, "TOC \\o") ||
+		strings.Index(document, "TOC \\o") > strings.Index(document, "Lesson: Basics") {
+		t.Error("Expected the table of contents between the title and the first lesson")
+	}
+
+	plain := exportDocxParts(t, NewDocxExporter(services.NewHTMLCleaner()), createTestCourseForDocxStyles())
+	if strings.Contains(plain["word/document.xml"], "TOC \\o") {
+		t.Error("Expected no table of contents by default")
+	}
+}
+
+// TestDocxExporter_ReferenceDoc tests that a reference template provides the
+// styles, header and page setup of the document.
+func TestDocxExporter_ReferenceDoc(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "brand.dotx")
+	if err := os.WriteFile(path, createTestReferenceDocx(t), 0o600); err != nil {
+		t.Fatalf("Failed to write reference document: %v", err)
+	}
+	exporter, err := NewDocxExporterWithOptions(services.NewHTMLCleaner(), DocxOptions{ReferenceDoc: path})
+	if err != nil {
+		t.Fatalf("NewDocxExporterWithOptions failed: %v", err)
+	}
+	parts := exportDocxParts(t, exporter, createTestCourseForDocxStyles())
+
+	document := parts["word/document.xml"]
+	if !strings.Contains(document, "Lesson: Basics") || strings.Contains(document, "Template body") {
+		t.Error("Expected the course content to replace the body of the reference")
+	}
+	if !strings.Contains(document, `<w:headerReference w:type="default" r:id="rId2"`) || !strings.Contains(document, `w:orient="landscape"`) {
+		t.Error("Expected the section properties of the reference")
+	}
+	if parts["word/header1.xml"] == "" {
+		t.Error("Expected the header of the reference")
+	}
+
+	styles := parts["word/styles.xml"]
+	if !strings.Contains(styles, "Brand heading") {
+		t.Error("Expected the styles of the reference")
+	}
+	if strings.Count(styles, `w:styleId="Heading1"`) != 1 || !strings.Contains(styles, `w:styleId="Heading2"`) {
+		t.Error("Expected missing styles to be added without replacing the reference's")
+	}
+
+	if !strings.Contains(parts["[Content_Types].xml"], docxDocumentType) || strings.Contains(parts["[Content_Types].xml"], "template.main+xml") {
+		t.Error("Expected the template content type to become a document's")
+	}
+
+	// The lists are numbered after the reference's own numbering definitions
+	numbering := parts["word/numbering.xml"]
+	if !strings.Contains(numbering, `<w:abstractNum w:abstractNumId="0">`) || !strings.Contains(numbering, `<w:num w:numId="8">`) {
+		t.Error("Expected the reference's numbering to be kept")
+	}
+	if !strings.Contains(numbering, `<w:num w:numId="9">`) || !strings.Contains(document, `<w:numId w:val="9">`) {
+		t.Error("Expected the lists to use numbering IDs after the reference's")
+	}
+	if strings.Index(numbering, `w:abstractNumId="3"`) > strings.Index(numbering, `<w:num w:numId="8">`) {
+		t.Error("Expected all abstract numbering definitions before the numbering instances")
+	}
+}
+
+// TestNewDocxExporterWithOptions_InvalidReference tests the errors for
+// unusable reference documents.
+func TestNewDocxExporterWithOptions_InvalidReference(t *testing.T) {
+	dir := t.TempDir()
+	notZip := filepath.Join(dir, "notes.docx")
+	if err := os.WriteFile(notZip, []byte("not a document"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		path          string
+		expectedError string
+	}{
+		{"missing file", filepath.Join(dir, "missing.docx"), "failed to read reference document"},
+		{"not a document", notZip, "invalid reference document"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDocxExporterWithOptions(services.NewHTMLCleaner(), DocxOptions{ReferenceDoc: tt.path})
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing '%s', got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
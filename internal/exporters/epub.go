@@ -0,0 +1,549 @@
+package exporters
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	stdhtml "html"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/kjanat/articulate-parser/internal/interfaces"
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+//go:embed epub_templates.gotmpl
+var epubTemplates string
+
+//go:embed epub_styles.css
+var epubCSS string
+
+// Fixed locations and values inside the EPUB container.
+const (
+	epubMimetype   = "application/epub+zip"
+	epubContentDir = "OEBPS"
+	epubPackage    = "content.opf"
+	epubStylesheet = "styles.css"
+	epubNav        = "nav.xhtml"
+	epubTitlePage  = "title.xhtml"
+	epubCoverPage  = "cover.xhtml"
+
+	// epubLanguage is the publication language. Rise courses do not record
+	// their language, so English is assumed.
+	epubLanguage = "en"
+
+	// epubMaxCoverSize limits the size of a downloaded cover image.
+	epubMaxCoverSize = 20 << 20
+)
+
+// epubCoverTypes maps the image media types EPUB reading systems must support
+// to the file extension used for the cover image.
+var epubCoverTypes = map[string]string{
+	"image/jpeg":    ".jpg",
+	"image/png":     ".png",
+	"image/gif":     ".gif",
+	"image/svg+xml": ".svg",
+	"image/webp":    ".webp",
+}
+
+// epubDroppedElements are elements removed from course HTML because they
+// would reference remote resources or run scripts, which EPUB does not allow
+// without declaring them in the package.
+var epubDroppedElements = map[atom.Atom]bool{
+	atom.Script: true,
+	atom.Style:  true,
+	atom.Iframe: true,
+	atom.Object: true,
+	atom.Embed:  true,
+	atom.Img:    true,
+	atom.Video:  true,
+	atom.Audio:  true,
+	atom.Form:   true,
+}
+
+// EPUBExporter implements the Exporter interface for EPUB 3 format.
+// It writes each lesson as an XHTML chapter, using the HTML exporter's item
+// templates, and turns sections into parts of the navigation document.
+type EPUBExporter struct {
+	// htmlCleaner is used to convert HTML content to plain text for metadata
+	htmlCleaner *services.HTMLCleaner
+	// html renders lesson items with the templates of the HTML exporter
+	html *HTMLExporter
+	// tmpl holds the parsed EPUB package and page templates
+	tmpl *template.Template
+	// client downloads the cover image when it is not bundled with the course
+	client *http.Client
+	// now returns the modification time recorded in the package metadata
+	now func() time.Time
+}
+
+// epubPage is the data passed to the XHTML page templates.
+type epubPage struct {
+	Title       string
+	Language    string
+	Stylesheet  string
+	Author      string
+	Image       string
+	Description string
+	Body        string
+	Entries     []epubNavEntry
+	Landmarks   []epubLandmark
+}
+
+// epubNavEntry is an entry of the navigation document. Parts have the
+// lessons that follow them as children.
+type epubNavEntry struct {
+	Title    string
+	Href     string
+	Children []epubNavEntry
+}
+
+// epubLandmark is an entry of the landmarks navigation.
+type epubLandmark struct {
+	Type  string
+	Title string
+	Href  string
+}
+
+// epubManifestItem is a resource listed in the package manifest.
+type epubManifestItem struct {
+	ID         string
+	Href       string
+	MediaType  string
+	Properties string
+}
+
+// epubPackageData is the data passed to the package document template.
+type epubPackageData struct {
+	Identifier  string
+	Title       string
+	Language    string
+	Creator     string
+	Description string
+	Modified    string
+	CoverID     string
+	Manifest    []epubManifestItem
+	Spine       []string
+}
+
+// epubFile is a file written to the EPUB container.
+type epubFile struct {
+	Name string
+	Data []byte
+}
+
+// NewEPUBExporter creates a new EPUBExporter instance.
+// It takes an HTMLCleaner to handle HTML content conversion.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//
+// Returns:
+//   - An implementation of the Exporter interface for EPUB format
+func NewEPUBExporter(htmlCleaner *services.HTMLCleaner) interfaces.Exporter {
+	funcMap := template.FuncMap{
+		"esc": stdhtml.EscapeString,
+	}
+
+	return &EPUBExporter{
+		htmlCleaner: htmlCleaner,
+		html:        NewHTMLExporter(htmlCleaner).(*HTMLExporter),
+		tmpl:        template.Must(template.New("epub").Funcs(funcMap).Parse(epubTemplates)),
+		client:      &http.Client{Timeout: 30 * time.Second},
+		now:         time.Now,
+	}
+}
+
+// Export exports the course to an EPUB file.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - outputPath: The file path where the EPUB content will be written
+//
+// Returns:
+//   - An error if building or saving the publication fails
+func (e *EPUBExporter) Export(course *models.Course, outputPath string) error {
+	// #nosec G304 - Output path is provided by user via CLI argument, which is expected behavior
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	// Close errors are logged but not fatal, see DocxExporter.Export.
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to close output file: %v\n", err)
+		}
+	}()
+
+	return e.ExportTo(course, file)
+}
+
+// ExportTo exports the course as an EPUB publication written to w.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - w: The writer the EPUB content will be written to
+//
+// Returns:
+//   - An error if building or writing the publication fails
+func (e *EPUBExporter) ExportTo(course *models.Course, w io.Writer) error {
+	files, err := e.buildPublication(course)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+
+	// The mimetype file must come first and be stored uncompressed so that
+	// the container can be identified by its leading bytes
+	mt, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return fmt.Errorf("failed to write EPUB container: %w", err)
+	}
+	if _, err := io.WriteString(mt, epubMimetype); err != nil {
+		return fmt.Errorf("failed to write EPUB container: %w", err)
+	}
+
+	for _, f := range files {
+		fw, err := zw.Create(f.Name)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Name, err)
+		}
+		if _, err := fw.Write(f.Data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write EPUB container: %w", err)
+	}
+	return nil
+}
+
+// buildPublication renders all files of the publication except the mimetype.
+//
+// Parameters:
+//   - course: The course data model to export
+//
+// Returns:
+//   - The files of the publication in container order
+//   - An error if rendering a page fails
+func (e *EPUBExporter) buildPublication(course *models.Course) ([]epubFile, error) {
+	info := course.Course
+	pkg := epubPackageData{
+		Identifier:  courseURN(course),
+		Title:       info.Title,
+		Language:    epubLanguage,
+		Creator:     course.Author,
+		Description: e.htmlCleaner.CleanHTML(info.Description),
+		Modified:    e.now().UTC().Format(time.RFC3339),
+	}
+	if pkg.Title == "" {
+		pkg.Title = "Untitled Course"
+	}
+
+	var files []epubFile
+	render := func(name, tmpl string, data any) error {
+		var buf bytes.Buffer
+		if err := e.tmpl.ExecuteTemplate(&buf, tmpl, data); err != nil {
+			return fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		files = append(files, epubFile{Name: path.Join(epubContentDir, name), Data: buf.Bytes()})
+		return nil
+	}
+	page := func(title string) epubPage {
+		return epubPage{Title: title, Language: epubLanguage, Stylesheet: epubStylesheet}
+	}
+	addPage := func(id, href, properties string) {
+		pkg.Manifest = append(pkg.Manifest, epubManifestItem{
+			ID: id, Href: href, MediaType: "application/xhtml+xml", Properties: properties,
+		})
+		pkg.Spine = append(pkg.Spine, id)
+	}
+
+	var container bytes.Buffer
+	if err := e.tmpl.ExecuteTemplate(&container, "container", path.Join(epubContentDir, epubPackage)); err != nil {
+		return nil, fmt.Errorf("failed to render container.xml: %w", err)
+	}
+	files = append(files, epubFile{Name: "META-INF/container.xml", Data: container.Bytes()})
+	files = append(files, epubFile{Name: path.Join(epubContentDir, epubStylesheet), Data: []byte(epubCSS)})
+	pkg.Manifest = append(pkg.Manifest, epubManifestItem{ID: "css", Href: epubStylesheet, MediaType: "text/css"})
+
+	landmarks := []epubLandmark{{Type: "toc", Title: "Contents", Href: epubNav + "#toc"}}
+
+	// Cover
+	if image, mediaType, ok := e.coverImage(course); ok {
+		imageHref := "images/cover" + epubCoverTypes[mediaType]
+		files = append(files, epubFile{Name: path.Join(epubContentDir, imageHref), Data: image})
+		pkg.Manifest = append(pkg.Manifest, epubManifestItem{
+			ID: "cover-image", Href: imageHref, MediaType: mediaType, Properties: "cover-image",
+		})
+		pkg.CoverID = "cover-image"
+
+		cover := page(pkg.Title)
+		cover.Image = imageHref
+		if err := render(epubCoverPage, "cover", cover); err != nil {
+			return nil, err
+		}
+		addPage("cover", epubCoverPage, "")
+		landmarks = append(landmarks, epubLandmark{Type: "cover", Title: "Cover", Href: epubCoverPage})
+	}
+
+	// Title page
+	titlePage := page(pkg.Title)
+	titlePage.Author = course.Author
+	titlePage.Body = xhtmlFragment(info.Description)
+	if err := render(epubTitlePage, "title", titlePage); err != nil {
+		return nil, err
+	}
+	addPage("title-page", epubTitlePage, "")
+	addPage("nav", epubNav, "nav")
+	entries := []epubNavEntry{{Title: pkg.Title, Href: epubTitlePage}}
+
+	// Parts and lessons
+	lessonCounter := 0
+	partCounter := 0
+	part := -1 // index of the current part in entries
+	for _, lesson := range info.Lessons {
+		var entry epubNavEntry
+
+		if lesson.Type == lessonTypeSection {
+			partCounter++
+			id := fmt.Sprintf("part-%03d", partCounter)
+			entry = epubNavEntry{Title: lesson.Title, Href: id + ".xhtml"}
+			if err := render(entry.Href, "part", page(lesson.Title)); err != nil {
+				return nil, err
+			}
+			addPage(id, entry.Href, "")
+		} else {
+			lessonCounter++
+			id := fmt.Sprintf("lesson-%03d", lessonCounter)
+			title := fmt.Sprintf("Lesson %d: %s", lessonCounter, lesson.Title)
+			entry = epubNavEntry{Title: title, Href: id + ".xhtml"}
+
+			chapter, err := e.lessonPage(&lesson, page(title))
+			if err != nil {
+				return nil, err
+			}
+			if err := render(entry.Href, "lesson", chapter); err != nil {
+				return nil, err
+			}
+			addPage(id, entry.Href, "")
+		}
+
+		if lessonCounter+partCounter == 1 {
+			landmarks = append(landmarks, epubLandmark{Type: "bodymatter", Title: "Start of Content", Href: entry.Href})
+		}
+
+		switch {
+		case lesson.Type == lessonTypeSection:
+			entries = append(entries, entry)
+			part = len(entries) - 1
+		case part >= 0:
+			entries[part].Children = append(entries[part].Children, entry)
+		default:
+			entries = append(entries, entry)
+		}
+	}
+
+	// Navigation document
+	nav := page(pkg.Title)
+	nav.Entries = entries
+	nav.Landmarks = landmarks
+	if err := render(epubNav, "nav", nav); err != nil {
+		return nil, err
+	}
+
+	// Package document
+	if err := render(epubPackage, "package", pkg); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// lessonPage prepares the chapter page of a lesson. The lesson items are
+// rendered with the HTML exporter's item templates and converted to XHTML.
+//
+// Parameters:
+//   - lesson: The lesson data model to export
+//   - chapter: The page with its title already set
+//
+// Returns:
+//   - The chapter page with description and body
+//   - An error if rendering an item fails
+func (e *EPUBExporter) lessonPage(lesson *models.Lesson, chapter epubPage) (epubPage, error) {
+	var body bytes.Buffer
+	for _, item := range prepareItems(lesson.Items, e.htmlCleaner) {
+		if err := e.html.tmpl.ExecuteTemplate(&body, "item", item); err != nil {
+			return chapter, fmt.Errorf("failed to render lesson %s: %w", lesson.Title, err)
+		}
+	}
+
+	chapter.Description = xhtmlFragment(lesson.Description)
+	chapter.Body = xhtmlFragment(body.String())
+	return chapter, nil
+}
+
+// coverImage returns the course cover image and its media type. The image is
+// read from the course's source package when it is bundled there and
+// downloaded from its original URL otherwise. Courses without a cover, or
+// whose cover cannot be loaded or is not a supported image, have none.
+//
+// Parameters:
+//   - course: The course data model to export
+//
+// Returns:
+//   - The image data
+//   - The image media type, a key of epubCoverTypes
+//   - Whether a cover image is available
+func (e *EPUBExporter) coverImage(course *models.Course) ([]byte, string, bool) {
+	cover := course.Course.CoverImage
+	if cover == nil || cover.Image == nil {
+		return nil, "", false
+	}
+	image := cover.Image
+
+	var data []byte
+	var mediaType string
+	for _, key := range []string{image.Key, image.OriginalURL} {
+		rc, err := services.OpenPackageMedia(course.Package, key)
+		if err != nil {
+			continue
+		}
+		data, err = io.ReadAll(io.LimitReader(rc, epubMaxCoverSize))
+		_ = rc.Close() // Read-only archive; close errors cannot affect the data read
+		if err == nil {
+			mediaType = mime.TypeByExtension(path.Ext(key))
+			break
+		}
+		data = nil
+	}
+
+	if data == nil && image.OriginalURL != "" {
+		data, mediaType = e.downloadCover(image.OriginalURL)
+	}
+	if data == nil {
+		return nil, "", false
+	}
+
+	mediaType, _, _ = mime.ParseMediaType(mediaType)
+	if _, ok := epubCoverTypes[mediaType]; !ok {
+		mediaType = http.DetectContentType(data)
+	}
+	if _, ok := epubCoverTypes[mediaType]; !ok {
+		return nil, "", false
+	}
+	return data, mediaType, true
+}
+
+// downloadCover downloads a cover image.
+//
+// Parameters:
+//   - url: The URL of the image
+//
+// Returns:
+//   - The image data, or nil if the download failed
+//   - The Content-Type reported by the server
+func (e *EPUBExporter) downloadCover(url string) ([]byte, string) {
+	resp, err := e.client.Get(url) // #nosec G107 - URL comes from the course data
+	if err != nil {
+		return nil, ""
+	}
+	defer func() {
+		_ = resp.Body.Close() // The body has been read in full; close errors are irrelevant
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ""
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, epubMaxCoverSize))
+	if err != nil {
+		return nil, ""
+	}
+	return data, resp.Header.Get("Content-Type")
+}
+
+// SupportedFormat returns the format name this exporter supports.
+//
+// Returns:
+//   - A string representing the supported format ("epub")
+func (e *EPUBExporter) SupportedFormat() string {
+	return FormatEPUB
+}
+
+// courseURN returns a stable unique URN for the course, derived from the
+// course ID, the share ID or, failing both, the title. It identifies EPUB
+// publications and is the base of cmi5 activity IRIs.
+func courseURN(course *models.Course) string {
+	switch {
+	case course.Course.ID != "":
+		return "urn:articulate-rise:course:" + course.Course.ID
+	case course.ShareID != "":
+		return "urn:articulate-rise:share:" + course.ShareID
+	}
+	sum := sha256.Sum256([]byte(course.Course.Title))
+	return "urn:articulate-rise:title:" + hex.EncodeToString(sum[:8])
+}
+
+// xhtmlFragment converts an HTML fragment from course data into well-formed
+// XHTML. Unclosed and void elements are closed, named entities are replaced
+// by characters, and content EPUB cannot hold without further declarations,
+// such as scripts, event handlers and remote images, is removed.
+func xhtmlFragment(fragment string) string {
+	if strings.TrimSpace(fragment) == "" {
+		return ""
+	}
+
+	context := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), context)
+	if err != nil {
+		return stdhtml.EscapeString(fragment)
+	}
+
+	var buf bytes.Buffer
+	for _, n := range nodes {
+		if n.Type == html.ElementNode && epubDroppedElements[n.DataAtom] {
+			continue
+		}
+		removeDroppedElements(n)
+		// Rendering to an in-memory buffer cannot fail
+		_ = html.Render(&buf, n)
+	}
+	return buf.String()
+}
+
+// removeDroppedElements removes the descendants of n listed in
+// epubDroppedElements and the event handler attributes of n and its descendants.
+func removeDroppedElements(n *html.Node) {
+	attrs := n.Attr[:0]
+	for _, attr := range n.Attr {
+		if !strings.HasPrefix(attr.Key, "on") {
+			attrs = append(attrs, attr)
+		}
+	}
+	n.Attr = attrs
+
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode && epubDroppedElements[c.DataAtom] {
+			n.RemoveChild(c)
+		} else {
+			removeDroppedElements(c)
+		}
+		c = next
+	}
+}
@@ -0,0 +1,379 @@
+package exporters
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// pngHeader is enough of a PNG file for content sniffing.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// epubOPF is the subset of the package document checked by the tests.
+type epubOPF struct {
+	UniqueIdentifier string `xml:"unique-identifier,attr"`
+	Version          string `xml:"version,attr"`
+	Metadata         struct {
+		Identifiers []struct {
+			ID    string `xml:"id,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"identifier"`
+		Title    string `xml:"title"`
+		Language string `xml:"language"`
+		Creator  string `xml:"creator"`
+		Meta     []struct {
+			Property string `xml:"property,attr"`
+			Value    string `xml:",chardata"`
+		} `xml:"meta"`
+	} `xml:"metadata"`
+	Items []epubOPFItem `xml:"manifest>item"`
+	Spine []struct {
+		IDRef string `xml:"idref,attr"`
+	} `xml:"spine>itemref"`
+}
+
+// epubOPFItem is a manifest item of the package document.
+type epubOPFItem struct {
+	ID         string `xml:"id,attr"`
+	Href       string `xml:"href,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Properties string `xml:"properties,attr"`
+}
+
+// TestNewEPUBExporter tests the NewEPUBExporter constructor.
+func TestNewEPUBExporter(t *testing.T) {
+	exporter := NewEPUBExporter(services.NewHTMLCleaner())
+
+	epubExporter, ok := exporter.(*EPUBExporter)
+	if !ok {
+		t.Fatal("NewEPUBExporter() returned wrong type")
+	}
+	if epubExporter.htmlCleaner == nil || epubExporter.html == nil || epubExporter.tmpl == nil {
+		t.Error("Expected cleaner, HTML exporter and templates to be set")
+	}
+	if exporter.SupportedFormat() != "epub" {
+		t.Errorf("Expected format 'epub', got '%s'", exporter.SupportedFormat())
+	}
+}
+
+// TestEPUBExporter_Export tests that Export writes an EPUB file.
+func TestEPUBExporter_Export(t *testing.T) {
+	exporter := NewEPUBExporter(services.NewHTMLCleaner())
+	outputPath := filepath.Join(t.TempDir(), "course.epub")
+
+	if err := exporter.Export(createTestCourseForDocx(), outputPath); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte("PK")) || !bytes.Contains(content[:64], []byte("mimetypeapplication/epub+zip")) {
+		t.Error("Expected the uncompressed mimetype as the first entry of the container")
+	}
+}
+
+// TestEPUBExporter_Structure validates the container, package document and
+// navigation document the way epubcheck does for the structural rules.
+func TestEPUBExporter_Structure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(pngHeader)
+	}))
+	defer server.Close()
+
+	course := &models.Course{
+		ShareID: "share-1",
+		Author:  "Jane Doe",
+		Course: models.CourseInfo{
+			ID:          "course-1",
+			Title:       "Safety & Health",
+			Description: "<p>Stay <b>safe</b><br>at work &nbsp;today</p>",
+			CoverImage:  &models.Media{Image: &models.ImageMedia{OriginalURL: server.URL + "/cover"}},
+			Lessons: []models.Lesson{
+				{Title: "Welcome", Description: "<p>Start here"},
+				{Title: "Basics", Type: "section"},
+				{
+					Title: "Hazards",
+					Items: []models.Item{
+						{Type: "text", Items: []models.SubItem{{Heading: "<h2>Spot them</h2>", Paragraph: "<p>Look<script>alert(1)</script> around <img src=\"https://example.com/x.png\"></p>"}}},
+						{Type: "list", Variant: "checkboxes", Items: []models.SubItem{{Paragraph: "<p>Gloves</p>"}}},
+						{Type: "divider"},
+						{Type: "knowledgeCheck", Variant: "matching", Items: []models.SubItem{{
+							Title:   "<p>Match</p>",
+							Answers: []models.Answer{{Title: "Fire", MatchTitle: "Extinguisher"}},
+						}}},
+					},
+				},
+				{Title: "Signs"},
+			},
+		},
+	}
+
+	exporter := NewEPUBExporter(services.NewHTMLCleaner()).(*EPUBExporter)
+	exporter.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+	files := readZipFiles(t, buf.Bytes())
+
+	// Container
+	if files[0].name != "mimetype" || files[0].method != zip.Store || files[0].data != "application/epub+zip" {
+		t.Errorf("Expected stored mimetype as first entry, got %+v", files[0])
+	}
+	byName := make(map[string]string)
+	for _, f := range files {
+		byName[f.name] = f.data
+	}
+	if !strings.Contains(byName["META-INF/container.xml"], `full-path="OEBPS/content.opf"`) {
+		t.Fatalf("Expected container.xml to point to the package document, got %s", byName["META-INF/container.xml"])
+	}
+
+	// Package document
+	var opf epubOPF
+	if err := xml.Unmarshal([]byte(byName["OEBPS/content.opf"]), &opf); err != nil {
+		t.Fatalf("Package document is not well-formed: %v", err)
+	}
+	if opf.Version != "3.0" {
+		t.Errorf("Expected EPUB 3.0 package, got '%s'", opf.Version)
+	}
+	if len(opf.Metadata.Identifiers) != 1 || opf.Metadata.Identifiers[0].ID != opf.UniqueIdentifier ||
+		opf.Metadata.Identifiers[0].Value != "urn:articulate-rise:course:course-1" {
+		t.Errorf("Unexpected identifiers: %+v", opf.Metadata.Identifiers)
+	}
+	if opf.Metadata.Title != "Safety & Health" || opf.Metadata.Creator != "Jane Doe" || opf.Metadata.Language != "en" {
+		t.Errorf("Unexpected metadata: %+v", opf.Metadata)
+	}
+	modified := ""
+	for _, meta := range opf.Metadata.Meta {
+		if meta.Property == "dcterms:modified" {
+			modified = meta.Value
+		}
+	}
+	if modified != "2024-05-01T12:00:00Z" {
+		t.Errorf("Expected dcterms:modified '2024-05-01T12:00:00Z', got '%s'", modified)
+	}
+
+	manifest := make(map[string]string)
+	navItems, coverItems := 0, 0
+	for _, item := range opf.Items {
+		if _, ok := byName[path.Join("OEBPS", item.Href)]; !ok {
+			t.Errorf("Manifest item %s refers to missing file %s", item.ID, item.Href)
+		}
+		manifest[item.ID] = item.MediaType
+		switch item.Properties {
+		case "nav":
+			navItems++
+		case "cover-image":
+			coverItems++
+			if item.MediaType != "image/png" || item.Href != "images/cover.png" {
+				t.Errorf("Unexpected cover image item: %+v", item)
+			}
+		}
+	}
+	if navItems != 1 || coverItems != 1 {
+		t.Errorf("Expected one nav and one cover image item, got %d and %d", navItems, coverItems)
+	}
+	for name := range byName {
+		if strings.HasPrefix(name, "OEBPS/") && name != "OEBPS/content.opf" && !manifestHasHref(opf.Items, strings.TrimPrefix(name, "OEBPS/")) {
+			t.Errorf("File %s is not listed in the manifest", name)
+		}
+	}
+
+	var spine []string
+	for _, ref := range opf.Spine {
+		if manifest[ref.IDRef] != "application/xhtml+xml" {
+			t.Errorf("Spine item %s is not an XHTML manifest item", ref.IDRef)
+		}
+		spine = append(spine, ref.IDRef)
+	}
+	expectedSpine := "cover title-page nav lesson-001 part-001 lesson-002 lesson-003"
+	if strings.Join(spine, " ") != expectedSpine {
+		t.Errorf("Expected spine '%s', got '%s'", expectedSpine, strings.Join(spine, " "))
+	}
+
+	// Every content document must be well-formed XML
+	for name, data := range byName {
+		if !strings.HasSuffix(name, ".xhtml") {
+			continue
+		}
+		dec := xml.NewDecoder(strings.NewReader(data))
+		dec.Strict = true
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Errorf("%s is not well-formed: %v\n%s", name, err, data)
+				break
+			}
+		}
+	}
+
+	// Navigation document: sections are parts holding the following lessons
+	nav := byName["OEBPS/nav.xhtml"]
+	for _, expected := range []string{
+		`epub:type="toc"`,
+		`<li><a href="lesson-001.xhtml">Lesson 1: Welcome</a>`,
+		`<li><a href="part-001.xhtml">Basics</a>`,
+		`<li><a href="lesson-002.xhtml">Lesson 2: Hazards</a></li>`,
+		`epub:type="cover" href="cover.xhtml"`,
+	} {
+		if !strings.Contains(nav, expected) {
+			t.Errorf("Expected nav document to contain '%s'", expected)
+		}
+	}
+	if strings.Index(nav, "part-001.xhtml") > strings.Index(nav, "lesson-003.xhtml") ||
+		strings.Count(nav, "<ol>") != 3 {
+		t.Errorf("Expected lessons after a section to be nested in its part:\n%s", nav)
+	}
+
+	// Chapter content
+	chapter := byName["OEBPS/lesson-002.xhtml"]
+	for _, expected := range []string{`epub:type="chapter"`, "<h1>Lesson 2: Hazards</h1>", "Spot them", "<hr/>", "Fire → Extinguisher", `disabled=""`} {
+		if !strings.Contains(chapter, expected) {
+			t.Errorf("Expected chapter to contain '%s'", expected)
+		}
+	}
+	for _, unexpected := range []string{"<script", "alert(1)", "<img"} {
+		if strings.Contains(chapter, unexpected) {
+			t.Errorf("Expected chapter not to contain '%s'", unexpected)
+		}
+	}
+	if title := byName["OEBPS/title.xhtml"]; !strings.Contains(title, "<b>safe</b><br/>") || !strings.Contains(title, "Jane Doe") {
+		t.Errorf("Unexpected title page:\n%s", title)
+	}
+}
+
+// TestEPUBExporter_CoverImage tests where the cover image is taken from.
+func TestEPUBExporter_CoverImage(t *testing.T) {
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	html := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html>not an image</html>"))
+	}))
+	defer html.Close()
+
+	// A package that bundles the cover image
+	packagePath := filepath.Join(t.TempDir(), "course.zip")
+	f, err := os.Create(packagePath)
+	if err != nil {
+		t.Fatalf("Failed to create package: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	w, _ := zw.Create("scormcontent/assets/cover.jpg")
+	_, _ = w.Write([]byte("\xff\xd8\xff\xe0jpeg"))
+	_ = zw.Close()
+	_ = f.Close()
+
+	tests := []struct {
+		name      string
+		cover     *models.Media
+		pkg       *models.SourcePackage
+		mediaType string
+	}{
+		{"no cover", nil, nil, ""},
+		{"download fails", &models.Media{Image: &models.ImageMedia{OriginalURL: notFound.URL}}, nil, ""},
+		{"not an image", &models.Media{Image: &models.ImageMedia{OriginalURL: html.URL}}, nil, ""},
+		{
+			name:      "bundled in package",
+			cover:     &models.Media{Image: &models.ImageMedia{Key: "assets/cover.jpg", OriginalURL: notFound.URL}},
+			pkg:       &models.SourcePackage{Path: packagePath, Media: map[string]string{"assets/cover.jpg": "scormcontent/assets/cover.jpg"}},
+			mediaType: "image/jpeg",
+		},
+	}
+
+	exporter := NewEPUBExporter(services.NewHTMLCleaner()).(*EPUBExporter)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			course := &models.Course{Course: models.CourseInfo{Title: "Cover", CoverImage: tt.cover}, Package: tt.pkg}
+
+			_, mediaType, ok := exporter.coverImage(course)
+			if ok != (tt.mediaType != "") || mediaType != tt.mediaType {
+				t.Errorf("Expected cover type '%s', got '%s' (ok=%v)", tt.mediaType, mediaType, ok)
+			}
+
+			var buf bytes.Buffer
+			if err := exporter.ExportTo(course, &buf); err != nil {
+				t.Fatalf("ExportTo failed: %v", err)
+			}
+			hasCover := bytes.Contains(buf.Bytes(), []byte("OEBPS/cover.xhtml"))
+			if hasCover != ok {
+				t.Errorf("Expected cover page to be present: %v", ok)
+			}
+		})
+	}
+}
+
+// TestXHTMLFragment tests converting course HTML into well-formed XHTML.
+func TestXHTMLFragment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"plain & simple", "plain &amp; simple"},
+		{"<p>Line<br>break", "<p>Line<br/>break</p>"},
+		{"<p>A&nbsp;B &rarr; C</p>", "<p>A B → C</p>"},
+		{`<p onclick="x()">Hi<script>bad()</script></p><script>worse()</script>`, `<p>Hi</p>`},
+		{`<ul><li>One<li>Two</ul>`, `<ul><li>One</li><li>Two</li></ul>`},
+	}
+
+	for _, tt := range tests {
+		if got := xhtmlFragment(tt.input); got != tt.expected {
+			t.Errorf("xhtmlFragment(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+// zipEntry is a file read back from a zip archive.
+type zipEntry struct {
+	name   string
+	method uint16
+	data   string
+}
+
+// readZipFiles returns the entries of a zip archive in order.
+func readZipFiles(t *testing.T, data []byte) []zipEntry {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Output is not a valid zip archive: %v", err)
+	}
+	entries := make([]zipEntry, 0, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", f.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		_ = rc.Close()
+		entries = append(entries, zipEntry{name: f.Name, method: f.Method, data: string(content)})
+	}
+	return entries
+}
+
+// manifestHasHref reports whether the manifest lists href.
+func manifestHasHref(items []epubOPFItem, href string) bool {
+	for _, item := range items {
+		if item.Href == href {
+			return true
+		}
+	}
+	return false
+}
@@ -18,7 +18,7 @@ func ExampleNewFactory() {
 	// Get supported formats
 	formats := factory.SupportedFormats()
 	fmt.Printf("Supported formats: %d\n", len(formats))
-	// Output: Supported formats: 6
+	// Output: Supported formats: 20
 }
 
 // ExampleFactory_CreateExporter demonstrates creating exporters.
@@ -10,14 +10,28 @@ import (
 
 // Format constants for supported export formats.
 const (
-	FormatMarkdown = "markdown"
-	FormatDocx     = "docx"
-	FormatHTML     = "html"
+	FormatMarkdown   = "markdown"
+	FormatDocx       = "docx"
+	FormatHTML       = "html"
+	FormatHTMLSite   = "html-site"
+	FormatPDF        = "pdf"
+	FormatEPUB       = "epub"
+	FormatSCORM12    = "scorm12"
+	FormatSCORM2004  = "scorm2004"
+	FormatCMI5       = "cmi5"
+	FormatQTI        = "qti"
+	FormatGIFT       = "gift"
+	FormatMoodleXML  = "moodlexml"
+	FormatFlashcards = "flashcards"
+	FormatJSON       = "json"
+	FormatYAML       = "yaml"
 
 	// Format aliases accepted by CreateExporter.
 	formatAliasMarkdown = "md"
 	formatAliasDocx     = "word"
 	formatAliasHTML     = "htm"
+	formatAliasSCORM    = "scorm"
+	formatAliasYAML     = "yml"
 )
 
 // Factory implements the ExporterFactory interface.
@@ -25,6 +39,19 @@ const (
 type Factory struct {
 	// htmlCleaner is used by exporters to convert HTML content to plain text
 	htmlCleaner *services.HTMLCleaner
+	// options customizes the Markdown, HTML and DOCX exporters
+	options Options
+}
+
+// Options customizes the exporters created by a factory. The zero value uses
+// the built-in templates and styles.
+type Options struct {
+	// Markdown applies to the markdown exporter
+	Markdown MarkdownOptions
+	// HTML applies to the html and html-site exporters
+	HTML HTMLOptions
+	// Docx applies to the docx exporter
+	Docx DocxOptions
 }
 
 // NewFactory creates a new exporter factory.
@@ -42,16 +69,56 @@ func NewFactory(htmlCleaner *services.HTMLCleaner) interfaces.ExporterFactory {
 	}
 }
 
+// NewFactoryWithOptions creates a new exporter factory whose Markdown, HTML,
+// static website and DOCX exporters are customized by options.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//   - options: The customizations of the exporters
+//
+// Returns:
+//   - An implementation of the ExporterFactory interface
+func NewFactoryWithOptions(htmlCleaner *services.HTMLCleaner, options Options) interfaces.ExporterFactory {
+	return &Factory{
+		htmlCleaner: htmlCleaner,
+		options:     options,
+	}
+}
+
 // CreateExporter creates an exporter for the specified format.
 // Format strings are case-insensitive (e.g., "markdown", "DOCX").
 func (f *Factory) CreateExporter(format string) (interfaces.Exporter, error) {
 	switch strings.ToLower(format) {
 	case FormatMarkdown, formatAliasMarkdown:
-		return NewMarkdownExporter(f.htmlCleaner), nil
+		return NewMarkdownExporterWithOptions(f.htmlCleaner, f.options.Markdown)
 	case FormatDocx, formatAliasDocx:
-		return NewDocxExporter(f.htmlCleaner), nil
+		return NewDocxExporterWithOptions(f.htmlCleaner, f.options.Docx)
 	case FormatHTML, formatAliasHTML:
-		return NewHTMLExporter(f.htmlCleaner), nil
+		return NewHTMLExporterWithOptions(f.htmlCleaner, f.options.HTML)
+	case FormatHTMLSite:
+		return NewHTMLSiteExporterWithOptions(f.htmlCleaner, f.options.HTML)
+	case FormatPDF:
+		return NewPDFExporter(f.htmlCleaner), nil
+	case FormatEPUB:
+		return NewEPUBExporter(f.htmlCleaner), nil
+	case FormatSCORM12, formatAliasSCORM:
+		return NewSCORMExporter(f.htmlCleaner, SCORMVersion12), nil
+	case FormatSCORM2004:
+		return NewSCORMExporter(f.htmlCleaner, SCORMVersion2004), nil
+	case FormatCMI5:
+		return NewCMI5Exporter(f.htmlCleaner), nil
+	case FormatQTI:
+		return NewQTIExporter(f.htmlCleaner), nil
+	case FormatGIFT:
+		return NewGIFTExporter(f.htmlCleaner), nil
+	case FormatMoodleXML:
+		return NewMoodleXMLExporter(f.htmlCleaner), nil
+	case FormatFlashcards:
+		return NewFlashcardsExporter(f.htmlCleaner), nil
+	case FormatJSON:
+		return NewNormalizedExporter(f.htmlCleaner, FormatJSON), nil
+	case FormatYAML, formatAliasYAML:
+		return NewNormalizedExporter(f.htmlCleaner, FormatYAML), nil
 	default:
 		return nil, fmt.Errorf("unsupported export format: %s", format)
 	}
@@ -64,5 +131,17 @@ func (f *Factory) SupportedFormats() []string {
 		FormatMarkdown, formatAliasMarkdown,
 		FormatDocx, formatAliasDocx,
 		FormatHTML, formatAliasHTML,
+		FormatHTMLSite,
+		FormatPDF,
+		FormatEPUB,
+		FormatSCORM12, formatAliasSCORM,
+		FormatSCORM2004,
+		FormatCMI5,
+		FormatQTI,
+		FormatGIFT,
+		FormatMoodleXML,
+		FormatFlashcards,
+		FormatJSON,
+		FormatYAML, formatAliasYAML,
 	}
 }
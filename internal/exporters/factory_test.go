@@ -83,9 +83,107 @@ func TestFactory_CreateExporter(t *testing.T) {
 			expectedFormat: "html",
 			shouldError:    false,
 		},
+		{
+			name:           "html-site format",
+			format:         "html-site",
+			expectedType:   "*exporters.HTMLSiteExporter",
+			expectedFormat: "html-site",
+			shouldError:    false,
+		},
+		{
+			name:           "pdf format",
+			format:         "pdf",
+			expectedType:   "*exporters.PDFExporter",
+			expectedFormat: "pdf",
+			shouldError:    false,
+		},
+		{
+			name:           "epub format",
+			format:         "epub",
+			expectedType:   "*exporters.EPUBExporter",
+			expectedFormat: "epub",
+			shouldError:    false,
+		},
+		{
+			name:           "scorm12 format",
+			format:         "scorm12",
+			expectedType:   "*exporters.SCORMExporter",
+			expectedFormat: "scorm12",
+			shouldError:    false,
+		},
+		{
+			name:           "scorm format alias",
+			format:         "scorm",
+			expectedType:   "*exporters.SCORMExporter",
+			expectedFormat: "scorm12",
+			shouldError:    false,
+		},
+		{
+			name:           "scorm2004 format",
+			format:         "scorm2004",
+			expectedType:   "*exporters.SCORMExporter",
+			expectedFormat: "scorm2004",
+			shouldError:    false,
+		},
+		{
+			name:           "cmi5 format",
+			format:         "cmi5",
+			expectedType:   "*exporters.CMI5Exporter",
+			expectedFormat: "cmi5",
+			shouldError:    false,
+		},
+		{
+			name:           "qti format",
+			format:         "qti",
+			expectedType:   "*exporters.QTIExporter",
+			expectedFormat: "qti",
+			shouldError:    false,
+		},
+		{
+			name:           "gift format",
+			format:         "gift",
+			expectedType:   "*exporters.GIFTExporter",
+			expectedFormat: "gift",
+			shouldError:    false,
+		},
+		{
+			name:           "moodlexml format",
+			format:         "moodlexml",
+			expectedType:   "*exporters.MoodleXMLExporter",
+			expectedFormat: "moodlexml",
+			shouldError:    false,
+		},
+		{
+			name:           "flashcards format",
+			format:         "flashcards",
+			expectedType:   "*exporters.FlashcardsExporter",
+			expectedFormat: "flashcards",
+			shouldError:    false,
+		},
+		{
+			name:           "json format",
+			format:         "json",
+			expectedType:   "*exporters.NormalizedExporter",
+			expectedFormat: "json",
+			shouldError:    false,
+		},
+		{
+			name:           "yaml format",
+			format:         "yaml",
+			expectedType:   "*exporters.NormalizedExporter",
+			expectedFormat: "yaml",
+			shouldError:    false,
+		},
+		{
+			name:           "yml alias",
+			format:         "yml",
+			expectedType:   "*exporters.NormalizedExporter",
+			expectedFormat: "yaml",
+			shouldError:    false,
+		},
 		{
 			name:        "unsupported format",
-			format:      "pdf",
+			format:      "txt",
 			shouldError: true,
 		},
 		{
@@ -158,6 +256,21 @@ func TestFactory_CreateExporter_CaseInsensitive(t *testing.T) {
 		{"HTM", "html"},
 		{"Htm", "html"},
 		{"HtM", "html"},
+		{"PDF", "pdf"},
+		{"HTML-Site", "html-site"},
+		{"Pdf", "pdf"},
+		{"EPUB", "epub"},
+		{"ePub", "epub"},
+		{"SCORM", "scorm12"},
+		{"SCORM2004", "scorm2004"},
+		{"CMI5", "cmi5"},
+		{"QTI", "qti"},
+		{"GIFT", "gift"},
+		{"MoodleXML", "moodlexml"},
+		{"Flashcards", "flashcards"},
+		{"JSON", "json"},
+		{"YAML", "yaml"},
+		{"YML", "yaml"},
 	}
 
 	for _, tc := range testCases {
@@ -185,9 +298,8 @@ func TestFactory_CreateExporter_ErrorMessages(t *testing.T) {
 	factory := NewFactory(htmlCleaner)
 
 	testCases := []string{
-		"pdf",
+		"rtf",
 		"txt",
-		"json",
 		"xml",
 		"unknown",
 		"123",
@@ -230,7 +342,7 @@ func TestFactory_SupportedFormats(t *testing.T) {
 		t.Fatal("SupportedFormats() returned nil")
 	}
 
-	expected := []string{"markdown", "md", "docx", "word", "html", "htm"}
+	expected := []string{"markdown", "md", "docx", "word", "html", "htm", "html-site", "pdf", "epub", "scorm12", "scorm", "scorm2004", "cmi5", "qti", "gift", "moodlexml", "flashcards", "json", "yaml", "yml"}
 
 	// Sort both slices for comparison
 	sort.Strings(formats)
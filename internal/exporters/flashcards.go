@@ -0,0 +1,193 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	stdhtml "html"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kjanat/articulate-parser/internal/interfaces"
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// errNoFlashcards is returned when a course without flashcards is exported
+// to a flashcard deck, since there would be nothing to import.
+var errNoFlashcards = errors.New("course has no flashcards to export")
+
+// FlashcardsExporter implements the Exporter interface for flashcard decks.
+// It writes the course's flashcards as a tab-separated file that Anki imports
+// as a deck, with a note per card tagged with the card's lesson.
+type FlashcardsExporter struct {
+	// htmlCleaner turns the course and lesson titles into plain text for the
+	// deck name and tags; card sides are written as HTML, which Anki renders
+	htmlCleaner *services.HTMLCleaner
+}
+
+// flashcardNote is a card as written to the deck: the HTML of both sides and
+// the space-separated tags.
+type flashcardNote struct {
+	Front string
+	Back  string
+	Tags  string
+}
+
+// NewFlashcardsExporter creates a new FlashcardsExporter instance.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//
+// Returns:
+//   - An implementation of the Exporter interface for flashcard decks
+func NewFlashcardsExporter(htmlCleaner *services.HTMLCleaner) interfaces.Exporter {
+	return &FlashcardsExporter{
+		htmlCleaner: htmlCleaner,
+	}
+}
+
+// Export writes the course's flashcards as an Anki deck to the output path.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - outputPath: The file path where the deck will be written
+//
+// Returns:
+//   - An error if the course has no flashcards or writing the file fails
+func (e *FlashcardsExporter) Export(course *models.Course, outputPath string) error {
+	var buf bytes.Buffer
+	if err := e.writeDeck(&buf, course); err != nil {
+		return err
+	}
+
+	// #nosec G306 - 0644 is appropriate for export files that should be readable by others
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write flashcards file: %w", err)
+	}
+	return nil
+}
+
+// ExportTo writes the course's flashcards as an Anki deck to w.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - w: The writer the deck will be written to
+//
+// Returns:
+//   - An error if the course has no flashcards or writing fails
+func (e *FlashcardsExporter) ExportTo(course *models.Course, w io.Writer) error {
+	var buf bytes.Buffer
+	if err := e.writeDeck(&buf, course); err != nil {
+		return err
+	}
+
+	if _, err := buf.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write flashcards: %w", err)
+	}
+	return nil
+}
+
+// SupportedFormat returns the format name this exporter supports.
+//
+// Returns:
+//   - A string representing the supported format ("flashcards")
+func (e *FlashcardsExporter) SupportedFormat() string {
+	return FormatFlashcards
+}
+
+// writeDeck renders the deck into buf. The header lines tell Anki how to read
+// the file, so it can be imported without changing the import options.
+func (e *FlashcardsExporter) writeDeck(buf *bytes.Buffer, course *models.Course) error {
+	notes := e.flashcardNotes(course)
+	if len(notes) == 0 {
+		return errNoFlashcards
+	}
+
+	deck := strings.Join(strings.Fields(e.htmlCleaner.CleanHTML(course.Course.Title)), " ")
+	if deck == "" {
+		deck = "Flashcards"
+	}
+	buf.WriteString("#separator:tab\n")
+	buf.WriteString("#html:true\n")
+	fmt.Fprintf(buf, "#deck:%s\n", deck)
+	buf.WriteString("#columns:Front\tBack\tTags\n")
+	buf.WriteString("#tags column:3\n")
+
+	cw := csv.NewWriter(buf)
+	cw.Comma = '\t'
+	for _, note := range notes {
+		if err := cw.Write([]string{note.Front, note.Back, note.Tags}); err != nil {
+			return fmt.Errorf("failed to write flashcard: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write flashcards: %w", err)
+	}
+	return nil
+}
+
+// flashcardNotes returns the flashcards of all lessons in course order,
+// whether they come from flashcard blocks or from interactive blocks.
+func (e *FlashcardsExporter) flashcardNotes(course *models.Course) []flashcardNote {
+	var notes []flashcardNote
+	lessonCounter := 0
+	for _, lesson := range course.Course.Lessons {
+		if lesson.Type == lessonTypeSection {
+			continue
+		}
+		lessonCounter++
+
+		tag := strings.Join(strings.Fields(e.htmlCleaner.CleanHTML(lesson.Title)), "_")
+		if tag == "" {
+			tag = fmt.Sprintf("Lesson_%d", lessonCounter)
+		}
+		for _, item := range lesson.Items {
+			for i := range item.Items {
+				subItem := &item.Items[i]
+				if !isFlashcard(subItem) {
+					continue
+				}
+				notes = append(notes, flashcardNote{
+					Front: cardSideHTML(subItem.Front),
+					Back:  cardSideHTML(subItem.Back),
+					Tags:  tag,
+				})
+			}
+		}
+	}
+	return notes
+}
+
+// isFlashcard reports whether a sub-item is a flashcard, i.e. has a front or
+// a back side.
+func isFlashcard(subItem *models.SubItem) bool {
+	return subItem.Front != nil || subItem.Back != nil
+}
+
+// cardSideHTML returns the HTML of a card side for a deck field: its
+// description followed by its image or a link to its video.
+func cardSideHTML(side *models.CardSide) string {
+	if side == nil {
+		return ""
+	}
+
+	parts := []string{}
+	if side.Description != "" {
+		parts = append(parts, side.Description)
+	}
+	if side.Media != nil {
+		if side.Media.Image != nil && side.Media.Image.OriginalURL != "" {
+			parts = append(parts, fmt.Sprintf(`<img src="%s">`, stdhtml.EscapeString(side.Media.Image.OriginalURL)))
+		}
+		if side.Media.Video != nil && side.Media.Video.OriginalURL != "" {
+			parts = append(parts, fmt.Sprintf(`<a href="%s">Video</a>`, stdhtml.EscapeString(side.Media.Video.OriginalURL)))
+		}
+	}
+
+	// Keep each note on a single line of the deck
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(strings.Join(parts, "<br>"))
+}
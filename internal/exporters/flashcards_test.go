@@ -0,0 +1,150 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// createTestCourseForFlashcards returns a course with flashcards in a
+// flashcard block and in an interactive block, with media on some sides.
+func createTestCourseForFlashcards() *models.Course {
+	return &models.Course{
+		Course: models.CourseInfo{
+			Title: "Biology  &amp; <b>Basics</b>",
+			Lessons: []models.Lesson{
+				{Title: "Part one", Type: "section"},
+				{
+					Title: "<p>Plant cells</p>",
+					Items: []models.Item{
+						{Type: "text", Items: []models.SubItem{{Paragraph: "<p>Intro</p>"}}},
+						{Type: "flashcard", Items: []models.SubItem{
+							{
+								Front: &models.CardSide{Description: "<p>Photosynthesis</p>"},
+								Back: &models.CardSide{
+									Description: "<p>Turning \"light\" into\nenergy</p>",
+									Media:       &models.Media{Image: &models.ImageMedia{OriginalURL: "https://example.com/leaf.jpg?a=1&b=2"}},
+								},
+							},
+						}},
+					},
+				},
+				{
+					Items: []models.Item{
+						{Type: "interactive", Items: []models.SubItem{
+							{
+								Front: &models.CardSide{Description: "<p>Osmosis</p>"},
+								Back:  &models.CardSide{Media: &models.Media{Video: &models.VideoMedia{OriginalURL: "https://example.com/osmosis.mp4"}}},
+							},
+							{Title: "<p>Not a card</p>"},
+						}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// TestFlashcardsExporter_SupportedFormat tests the SupportedFormat method.
+func TestFlashcardsExporter_SupportedFormat(t *testing.T) {
+	exporter := NewFlashcardsExporter(services.NewHTMLCleaner())
+	if got := exporter.SupportedFormat(); got != "flashcards" {
+		t.Errorf("Expected format 'flashcards', got '%s'", got)
+	}
+}
+
+// TestFlashcardsExporter_ExportTo tests the Anki header lines, the card
+// fields with their media and the lesson tags.
+func TestFlashcardsExporter_ExportTo(t *testing.T) {
+	exporter := NewFlashcardsExporter(services.NewHTMLCleaner())
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(createTestCourseForFlashcards(), &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	var header, body []string
+	for line := range strings.Lines(buf.String()) {
+		if strings.HasPrefix(line, "#") {
+			header = append(header, strings.TrimSuffix(line, "\n"))
+		} else {
+			body = append(body, line)
+		}
+	}
+	expectedHeader := []string{
+		"#separator:tab",
+		"#html:true",
+		"#deck:Biology & Basics",
+		"#columns:Front\tBack\tTags",
+		"#tags column:3",
+	}
+	if !slices.Equal(header, expectedHeader) {
+		t.Errorf("Expected header %q, got %q", expectedHeader, header)
+	}
+
+	r := csv.NewReader(strings.NewReader(strings.Join(body, "")))
+	r.Comma = '\t'
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read notes: %v", err)
+	}
+	expected := [][]string{
+		{
+			"<p>Photosynthesis</p>",
+			`<p>Turning "light" into energy</p><br><img src="https://example.com/leaf.jpg?a=1&amp;b=2">`,
+			"Plant_cells",
+		},
+		{
+			"<p>Osmosis</p>",
+			`<a href="https://example.com/osmosis.mp4">Video</a>`,
+			"Lesson_2",
+		},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d notes, got %d: %q", len(expected), len(records), records)
+	}
+	for i := range expected {
+		if !slices.Equal(records[i], expected[i]) {
+			t.Errorf("Expected note %d to be %q, got %q", i+1, expected[i], records[i])
+		}
+	}
+	if len(body) != len(expected) {
+		t.Errorf("Expected one line per note, got %d lines", len(body))
+	}
+}
+
+// TestFlashcardsExporter_NoFlashcards tests that a course without flashcards is rejected.
+func TestFlashcardsExporter_NoFlashcards(t *testing.T) {
+	exporter := NewFlashcardsExporter(services.NewHTMLCleaner())
+	course := &models.Course{Course: models.CourseInfo{Lessons: []models.Lesson{{Title: "Text only"}}}}
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); !errors.Is(err, errNoFlashcards) {
+		t.Errorf("Expected errNoFlashcards, got %v", err)
+	}
+}
+
+// TestFlashcardsExporter_Export tests that Export writes the deck to a file.
+func TestFlashcardsExporter_Export(t *testing.T) {
+	exporter := NewFlashcardsExporter(services.NewHTMLCleaner())
+	outputPath := filepath.Join(t.TempDir(), "deck.txt")
+
+	if err := exporter.Export(createTestCourseForFlashcards(), outputPath); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "#separator:tab\n") {
+		t.Errorf("Unexpected file start: %q", string(content[:min(len(content), 40)]))
+	}
+}
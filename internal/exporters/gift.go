@@ -0,0 +1,188 @@
+package exporters
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kjanat/articulate-parser/internal/interfaces"
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// giftEscaper escapes the characters that have a meaning in GIFT markup.
+// Line breaks are escaped too, since a blank line ends a question.
+var giftEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`~`, `\~`,
+	`=`, `\=`,
+	`#`, `\#`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`:`, `\:`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+// GIFTExporter implements the Exporter interface for Moodle's GIFT format.
+// It writes the course's knowledge check questions, in a category per lesson,
+// as a text file Moodle's question bank can import.
+type GIFTExporter struct {
+	// htmlCleaner is used to derive question names from question text
+	htmlCleaner *services.HTMLCleaner
+}
+
+// NewGIFTExporter creates a new GIFTExporter instance.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//
+// Returns:
+//   - An implementation of the Exporter interface for GIFT format
+func NewGIFTExporter(htmlCleaner *services.HTMLCleaner) interfaces.Exporter {
+	return &GIFTExporter{
+		htmlCleaner: htmlCleaner,
+	}
+}
+
+// Export writes the course's questions in GIFT format to the output path.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - outputPath: The file path where the GIFT file will be written
+//
+// Returns:
+//   - An error if the course has no questions or writing the file fails
+func (e *GIFTExporter) Export(course *models.Course, outputPath string) error {
+	var buf bytes.Buffer
+	if err := e.writeQuestions(&buf, course); err != nil {
+		return err
+	}
+
+	// #nosec G306 - 0644 is appropriate for export files that should be readable by others
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write GIFT file: %w", err)
+	}
+	return nil
+}
+
+// ExportTo writes the course's questions in GIFT format to w.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - w: The writer the GIFT output will be written to
+//
+// Returns:
+//   - An error if the course has no questions or writing fails
+func (e *GIFTExporter) ExportTo(course *models.Course, w io.Writer) error {
+	var buf bytes.Buffer
+	if err := e.writeQuestions(&buf, course); err != nil {
+		return err
+	}
+
+	if _, err := buf.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write GIFT: %w", err)
+	}
+	return nil
+}
+
+// SupportedFormat returns the format name this exporter supports.
+//
+// Returns:
+//   - A string representing the supported format ("gift")
+func (e *GIFTExporter) SupportedFormat() string {
+	return FormatGIFT
+}
+
+// writeQuestions renders the questions of all lessons into buf, each lesson
+// starting a new category.
+func (e *GIFTExporter) writeQuestions(buf *bytes.Buffer, course *models.Course) error {
+	lessons := quizLessons(course)
+	if len(lessons) == 0 {
+		return errNoQuestions
+	}
+
+	fmt.Fprintf(buf, "// %s\n\n", strings.ReplaceAll(course.Course.Title, "\n", " "))
+	questionCounter := 0
+	for _, lesson := range lessons {
+		fmt.Fprintf(buf, "$CATEGORY: %s\n\n", moodleCategory(e.htmlCleaner, lesson))
+		for _, question := range lesson.Questions {
+			questionCounter++
+			e.writeQuestion(buf, questionCounter, question)
+		}
+	}
+	return nil
+}
+
+// writeQuestion renders a question with its answers and general feedback.
+// Single-choice questions become multiple choice, multiple-response questions
+// multiple choice with weighted answers, fill-in questions short answer and
+// matching questions matching.
+func (e *GIFTExporter) writeQuestion(buf *bytes.Buffer, n int, question quizQuestion) {
+	subItem := question.SubItem
+	name := e.htmlCleaner.CleanHTML(subItem.Title)
+	if name == "" {
+		name = fmt.Sprintf("Question %d", n)
+	}
+	fmt.Fprintf(buf, "::%s::[html]%s {\n", giftEscaper.Replace(name), giftEscaper.Replace(subItem.Title))
+
+	// Moodle compares short answers and matches as plain text
+	switch question.Type {
+	case models.QuestionFillIn:
+		for _, answer := range subItem.Answers {
+			fmt.Fprintf(buf, "\t=%s\n", giftEscaper.Replace(e.htmlCleaner.CleanHTML(answer.Title)))
+		}
+	case models.QuestionMatching:
+		for _, answer := range subItem.Answers {
+			fmt.Fprintf(buf, "\t=%s -> %s\n", giftEscaper.Replace(e.htmlCleaner.CleanHTML(answer.Title)),
+				giftEscaper.Replace(e.htmlCleaner.CleanHTML(answer.MatchTitle)))
+		}
+	default:
+		correct := correctAnswers(subItem.Answers)
+		if question.Type == models.QuestionMultipleChoice || correct > 1 {
+			// Correct answers share the full mark; any wrong answer cancels it
+			weight := moodleFraction(100 / float64(max(correct, 1)))
+			for _, answer := range subItem.Answers {
+				if answer.Correct {
+					fmt.Fprintf(buf, "\t~%%%s%%%s\n", weight, giftEscaper.Replace(answer.Title))
+				} else {
+					fmt.Fprintf(buf, "\t~%%-100%%%s\n", giftEscaper.Replace(answer.Title))
+				}
+			}
+		} else {
+			for _, answer := range subItem.Answers {
+				mark := "~"
+				if answer.Correct {
+					mark = "="
+				}
+				fmt.Fprintf(buf, "\t%s%s\n", mark, giftEscaper.Replace(answer.Title))
+			}
+		}
+	}
+
+	if subItem.Feedback != "" {
+		fmt.Fprintf(buf, "\t####[html]%s\n", giftEscaper.Replace(subItem.Feedback))
+	}
+	buf.WriteString("}\n\n")
+}
+
+// moodleCategory returns the question bank category of a lesson's questions,
+// below the course's default category, named after the lesson title as plain
+// text. Slashes separate categories in Moodle, so slashes in the title are
+// doubled.
+func moodleCategory(htmlCleaner *services.HTMLCleaner, lesson quizLesson) string {
+	title := strings.Join(strings.Fields(htmlCleaner.CleanHTML(lesson.Title)), " ")
+	if title == "" {
+		title = fmt.Sprintf("Lesson %d", lesson.Number)
+	}
+	return "$course$/top/" + strings.ReplaceAll(title, "/", "//")
+}
+
+// moodleFraction formats a grade percentage the way Moodle lists its grade
+// options, with at most five decimals.
+func moodleFraction(percent float64) string {
+	return strings.TrimRight(strings.TrimRight(strconv.FormatFloat(percent, 'f', 5, 64), "0"), ".")
+}
@@ -0,0 +1,163 @@
+package exporters
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// createTestCourseForMoodle returns a course with one question of each type,
+// special characters in questions and answers, and a slash in a lesson title.
+func createTestCourseForMoodle() *models.Course {
+	return &models.Course{
+		Course: models.CourseInfo{
+			Title: "Maths & Logic",
+			Lessons: []models.Lesson{
+				{
+					Title: "Equations",
+					Items: []models.Item{
+						{Type: "knowledgeCheck", Variant: "multipleChoice", Items: []models.SubItem{{
+							Title:    "<p>What is {x} if x = 2 + 2?</p>",
+							Answers:  []models.Answer{{Title: "4", Correct: true}, {Title: "5 ~ roughly"}},
+							Feedback: "<p>Add: 2 + 2 = 4 #math</p>",
+						}}},
+						{Type: "knowledgeCheck", Variant: "multipleResponse", Items: []models.SubItem{{
+							Title:   "<p>Which are even?</p>",
+							Answers: []models.Answer{{Title: "2", Correct: true}, {Title: "3"}, {Title: "4", Correct: true}},
+						}}},
+					},
+				},
+				{Title: "Intro"},
+				{
+					Title: "<p>Terms/<b>Definitions</b></p>",
+					Items: []models.Item{
+						{Type: "knowledgeCheck", Variant: "fillIn", Items: []models.SubItem{{
+							Title:   "<p>A three-sided shape</p>",
+							Answers: []models.Answer{{Title: "Triangle"}, {Title: "<p>Trigon</p>"}},
+						}}},
+						{Type: "knowledgeCheck", Variant: "matching", Items: []models.SubItem{{
+							Title:    "<p>Match the symbols</p>",
+							Answers:  []models.Answer{{Title: "Pi", MatchTitle: "π"}, {Title: "<b>Sum</b>", MatchTitle: "<p>Σ</p>"}},
+							Feedback: "<p>Greek letters</p>",
+						}}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// TestGIFTExporter_SupportedFormat tests the SupportedFormat method.
+func TestGIFTExporter_SupportedFormat(t *testing.T) {
+	exporter := NewGIFTExporter(services.NewHTMLCleaner())
+	if got := exporter.SupportedFormat(); got != "gift" {
+		t.Errorf("Expected format 'gift', got '%s'", got)
+	}
+}
+
+// TestGIFTExporter_ExportTo tests categories, question types, feedback and
+// escaping in the GIFT output.
+func TestGIFTExporter_ExportTo(t *testing.T) {
+	exporter := NewGIFTExporter(services.NewHTMLCleaner())
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(createTestCourseForMoodle(), &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		"$CATEGORY: $course$/top/Equations\n",
+		"$CATEGORY: $course$/top/Terms//Definitions\n",
+		// Single choice, with GIFT control characters escaped
+		"::What is \\{x\\} if x \\= 2 + 2?::[html]<p>What is \\{x\\} if x \\= 2 + 2?</p> {\n\t=4\n\t~5 \\~ roughly\n",
+		"\t####[html]<p>Add\\: 2 + 2 \\= 4 \\#math</p>\n}",
+		// Multiple response shares the mark between the correct answers
+		"\t~%50%2\n\t~%-100%3\n\t~%50%4\n}",
+		// Short answer
+		"[html]<p>A three-sided shape</p> {\n\t=Triangle\n\t=Trigon\n}",
+		// Matching
+		"{\n\t=Pi -> π\n\t=Sum -> Σ\n\t####[html]<p>Greek letters</p>\n}",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q\ngot:\n%s", exp, output)
+		}
+	}
+	if strings.Contains(output, "Intro") {
+		t.Error("Expected lessons without questions to be left out")
+	}
+	if strings.Contains(output, "\n\n\n") {
+		t.Error("Expected questions to be separated by a single blank line")
+	}
+}
+
+// TestGIFTEscaper tests the escaping of GIFT special characters.
+func TestGIFTEscaper(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain text", "plain text"},
+		{`a ~ b = c # d`, `a \~ b \= c \# d`},
+		{"{x}: y", `\{x\}\: y`},
+		{`back\slash`, `back\\slash`},
+		{"two\nlines", `two\nlines`},
+	}
+
+	for _, tt := range tests {
+		if got := giftEscaper.Replace(tt.input); got != tt.expected {
+			t.Errorf("Expected %q for %q, got %q", tt.expected, tt.input, got)
+		}
+	}
+}
+
+// TestMoodleFraction tests the formatting of answer weights.
+func TestMoodleFraction(t *testing.T) {
+	tests := map[float64]string{
+		100:         "100",
+		50:          "50",
+		100.0 / 3.0: "33.33333",
+		100.0 / 6.0: "16.66667",
+		12.5:        "12.5",
+	}
+	for input, expected := range tests {
+		if got := moodleFraction(input); got != expected {
+			t.Errorf("Expected %s for %v, got %s", expected, input, got)
+		}
+	}
+}
+
+// TestGIFTExporter_NoQuestions tests that a course without questions is rejected.
+func TestGIFTExporter_NoQuestions(t *testing.T) {
+	exporter := NewGIFTExporter(services.NewHTMLCleaner())
+	course := &models.Course{Course: models.CourseInfo{Lessons: []models.Lesson{{Title: "Text only"}}}}
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); !errors.Is(err, errNoQuestions) {
+		t.Errorf("Expected errNoQuestions, got %v", err)
+	}
+}
+
+// TestGIFTExporter_Export tests that Export writes the questions to a file.
+func TestGIFTExporter_Export(t *testing.T) {
+	exporter := NewGIFTExporter(services.NewHTMLCleaner())
+	outputPath := filepath.Join(t.TempDir(), "questions.gift")
+
+	if err := exporter.Export(createTestCourseForMoodle(), outputPath); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "// Maths & Logic\n") {
+		t.Errorf("Unexpected file start: %q", string(content[:min(len(content), 40)]))
+	}
+}
@@ -25,10 +25,17 @@ type HTMLExporter struct {
 	htmlCleaner *services.HTMLCleaner
 	// tmpl holds the parsed HTML template
 	tmpl *template.Template
+	// css is the stylesheet embedded in the page
+	css string
+	// theme is ThemeDefault or ThemeCourse
+	theme string
+	// trustHTML embeds course HTML without sanitizing it
+	trustHTML bool
 }
 
 // NewHTMLExporter creates a new HTMLExporter instance.
 // It takes an HTMLCleaner to handle HTML content conversion when plain text is needed.
+// Course HTML is sanitized before it is embedded in the page.
 //
 // Parameters:
 //   - htmlCleaner: Service for cleaning HTML content in course data
@@ -36,22 +43,50 @@ type HTMLExporter struct {
 // Returns:
 //   - An implementation of the Exporter interface for HTML format
 func NewHTMLExporter(htmlCleaner *services.HTMLCleaner) interfaces.Exporter {
+	e := &HTMLExporter{
+		htmlCleaner: htmlCleaner,
+		css:         defaultCSS,
+		theme:       ThemeDefault,
+	}
+
 	// Parse the template with custom functions
 	funcMap := template.FuncMap{
-		"safeHTML": func(s string) template.HTML {
-			return template.HTML(s) // #nosec G203 - HTML content is from trusted course data
-		},
+		"safeHTML": e.safeHTML,
 		"safeCSS": func(s string) template.CSS {
 			return template.CSS(s) // #nosec G203 - CSS content is from trusted embedded file
 		},
 	}
 
-	tmpl := template.Must(template.New("html").Funcs(funcMap).Parse(htmlTemplate))
+	e.tmpl = template.Must(template.New("html").Funcs(funcMap).Parse(htmlTemplate))
+	return e
+}
 
-	return &HTMLExporter{
-		htmlCleaner: htmlCleaner,
-		tmpl:        tmpl,
+// NewHTMLExporterWithOptions creates a new HTMLExporter instance with custom
+// templates, stylesheet or theme. Templates from opts.TemplateDir override the
+// built-in templates they redefine; the page itself is the "html" template.
+// With opts.TrustHTML the course HTML is embedded without sanitizing it.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//   - opts: The templates, stylesheet and theme to use
+//
+// Returns:
+//   - An implementation of the Exporter interface for HTML format
+//   - An error if the theme is unknown or a template or stylesheet cannot be loaded
+func NewHTMLExporterWithOptions(htmlCleaner *services.HTMLCleaner, opts HTMLOptions) (interfaces.Exporter, error) {
+	e := NewHTMLExporter(htmlCleaner).(*HTMLExporter)
+
+	tmpl, css, err := applyHTMLOptions(e.tmpl, e.css, opts)
+	if err != nil {
+		return nil, err
+	}
+	e.tmpl = tmpl
+	e.css = css
+	e.trustHTML = opts.TrustHTML
+	if opts.Theme != "" {
+		e.theme = opts.Theme
 	}
+	return e, nil
 }
 
 // Export exports a course to HTML format.
@@ -83,6 +118,19 @@ func (e *HTMLExporter) Export(course *models.Course, outputPath string) error {
 	return e.WriteHTML(f, course)
 }
 
+// ExportTo exports a course to HTML format written to w.
+// It is equivalent to WriteHTML and satisfies the Exporter interface.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - w: The writer to output HTML content to
+//
+// Returns:
+//   - An error if writing fails
+func (e *HTMLExporter) ExportTo(course *models.Course, w io.Writer) error {
+	return e.WriteHTML(w, course)
+}
+
 // WriteHTML writes the HTML content to an io.Writer.
 // This allows for better testability and flexibility in output destinations.
 //
@@ -95,6 +143,7 @@ func (e *HTMLExporter) Export(course *models.Course, outputPath string) error {
 func (e *HTMLExporter) WriteHTML(w io.Writer, course *models.Course) error {
 	// Prepare template data
 	data := prepareTemplateData(course, e.htmlCleaner)
+	data.CSS = e.stylesheet(course)
 
 	// Execute template
 	if err := e.tmpl.Execute(w, data); err != nil {
@@ -112,3 +161,22 @@ func (e *HTMLExporter) WriteHTML(w io.Writer, course *models.Course) error {
 func (e *HTMLExporter) SupportedFormat() string {
 	return FormatHTML
 }
+
+// safeHTML marks course HTML as safe to embed in the page. Unless the
+// exporter trusts the course, the HTML is sanitized first, so that scripts,
+// event handlers and javascript: URLs from the course cannot run.
+func (e *HTMLExporter) safeHTML(s string) template.HTML {
+	if e.trustHTML {
+		return template.HTML(s) // #nosec G203 - The user opted in to trusting the course HTML
+	}
+	return template.HTML(e.htmlCleaner.SanitizeHTML(s)) // #nosec G203 - HTML content is sanitized
+}
+
+// stylesheet returns the stylesheet for a course, with the course theme
+// applied if it is enabled.
+func (e *HTMLExporter) stylesheet(course *models.Course) string {
+	if e.theme != ThemeCourse {
+		return e.css
+	}
+	return e.css + "\n" + courseThemeCSS(course.Course.Color)
+}
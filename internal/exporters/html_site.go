@@ -0,0 +1,316 @@
+package exporters
+
+import (
+	"archive/zip"
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kjanat/articulate-parser/internal/interfaces"
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+//go:embed html_site.gohtml
+var htmlSiteTemplate string
+
+//go:embed html_site.css
+var htmlSiteCSS string
+
+//go:embed html_site_search.js
+var htmlSiteSearch string
+
+// Files shared by all pages of a static site.
+const (
+	siteIndex       = "index.html"
+	siteStylesheet  = "styles.css"
+	siteSearch      = "search.js"
+	siteSearchIndex = "search-index.js"
+)
+
+// HTMLSiteExporter implements the Exporter interface for multi-page static
+// websites. It writes an index page and one page per lesson, rendered with
+// the HTML exporter's templates, with a sidebar grouped by section,
+// previous/next links and a client-side search. All links are relative, so
+// the site works from any static host or when opened from disk.
+type HTMLSiteExporter struct {
+	// htmlCleaner is used to build the plain text search index
+	htmlCleaner *services.HTMLCleaner
+	// html provides the item templates and the stylesheet
+	html *HTMLExporter
+	// page renders the index and lesson pages; it extends the HTML exporter's templates
+	page *htmltemplate.Template
+}
+
+// sitePageData is the data passed to the page template. Lesson is nil for
+// the index page.
+type sitePageData struct {
+	Course  models.CourseInfo
+	Nav     []siteNavGroup
+	Current string
+	Lesson  *templateSection
+	Prev    *siteLink
+	Next    *siteLink
+}
+
+// siteNavGroup is a section in the sidebar with the lessons that follow it.
+// Lessons before the first section are in a group without a title.
+type siteNavGroup struct {
+	Title string
+	Links []siteLink
+}
+
+// siteLink is a link to a lesson page.
+type siteLink struct {
+	Href   string
+	Number int
+	Title  string
+}
+
+// siteSearchEntry is a page in the search index.
+type siteSearchEntry struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	Text  string `json:"text"`
+}
+
+// siteFile is a file of the site, in the order it is written.
+type siteFile struct {
+	Name string
+	Data []byte
+}
+
+// NewHTMLSiteExporter creates a new HTMLSiteExporter instance.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//
+// Returns:
+//   - An implementation of the Exporter interface for static websites
+func NewHTMLSiteExporter(htmlCleaner *services.HTMLCleaner) interfaces.Exporter {
+	return newHTMLSiteExporter(htmlCleaner, NewHTMLExporter(htmlCleaner).(*HTMLExporter))
+}
+
+// NewHTMLSiteExporterWithOptions creates a new HTMLSiteExporter instance with
+// custom templates, stylesheet or theme, see NewHTMLExporterWithOptions. The
+// site's own styles are added after the stylesheet.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//   - opts: The templates, stylesheet and theme to use
+//
+// Returns:
+//   - An implementation of the Exporter interface for static websites
+//   - An error if the theme is unknown or a template or stylesheet cannot be loaded
+func NewHTMLSiteExporterWithOptions(htmlCleaner *services.HTMLCleaner, opts HTMLOptions) (interfaces.Exporter, error) {
+	html, err := NewHTMLExporterWithOptions(htmlCleaner, opts)
+	if err != nil {
+		return nil, err
+	}
+	return newHTMLSiteExporter(htmlCleaner, html.(*HTMLExporter)), nil
+}
+
+// newHTMLSiteExporter creates a site exporter whose pages extend the
+// templates of html.
+func newHTMLSiteExporter(htmlCleaner *services.HTMLCleaner, html *HTMLExporter) *HTMLSiteExporter {
+	page := htmltemplate.Must(htmltemplate.Must(html.tmpl.Clone()).Parse(htmlSiteTemplate))
+
+	return &HTMLSiteExporter{
+		htmlCleaner: htmlCleaner,
+		html:        html,
+		page:        page,
+	}
+}
+
+// Export writes the site into the output directory, creating it if needed.
+// Existing files with the same names are overwritten.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - outputPath: The directory the site will be written to
+//
+// Returns:
+//   - An error if rendering the pages or writing the files fails
+func (e *HTMLSiteExporter) Export(course *models.Course, outputPath string) error {
+	files, err := e.buildSite(course)
+	if err != nil {
+		return err
+	}
+
+	// #nosec G301 - 0755 is appropriate for a website directory that should be readable by others
+	if err := os.MkdirAll(outputPath, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	for _, file := range files {
+		// #nosec G306 - 0644 is appropriate for export files that should be readable by others
+		if err := os.WriteFile(filepath.Join(outputPath, file.Name), file.Data, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Name, err)
+		}
+	}
+	return nil
+}
+
+// ExportTo writes the site as a zip archive to w, since a directory cannot
+// be written to a stream.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - w: The writer the zip archive will be written to
+//
+// Returns:
+//   - An error if rendering the pages or writing the archive fails
+func (e *HTMLSiteExporter) ExportTo(course *models.Course, w io.Writer) error {
+	files, err := e.buildSite(course)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	for _, file := range files {
+		fw, err := zw.Create(file.Name)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Name, err)
+		}
+		if _, err := fw.Write(file.Data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write site archive: %w", err)
+	}
+	return nil
+}
+
+// SupportedFormat returns the format name this exporter supports.
+//
+// Returns:
+//   - A string representing the supported format ("html-site")
+func (e *HTMLSiteExporter) SupportedFormat() string {
+	return FormatHTMLSite
+}
+
+// buildSite renders all files of the site: the index page, the shared
+// stylesheet and scripts, and the lesson pages in course order.
+func (e *HTMLSiteExporter) buildSite(course *models.Course) ([]siteFile, error) {
+	data := prepareTemplateData(course, e.htmlCleaner)
+
+	// Collect the lessons and the sidebar groups first, since every page
+	// links to every lesson
+	var lessons []*templateSection
+	var links []siteLink
+	var nav []siteNavGroup
+	for i := range data.Sections {
+		section := &data.Sections[i]
+		if section.Type == lessonTypeSection {
+			nav = append(nav, siteNavGroup{Title: section.Title})
+			continue
+		}
+		if len(nav) == 0 {
+			nav = append(nav, siteNavGroup{})
+		}
+
+		link := siteLink{Href: fmt.Sprintf("lesson-%03d.html", section.Number), Number: section.Number, Title: section.Title}
+		nav[len(nav)-1].Links = append(nav[len(nav)-1].Links, link)
+		lessons = append(lessons, section)
+		links = append(links, link)
+	}
+
+	// Sections without lessons have nothing to link to
+	groups := nav[:0]
+	for _, group := range nav {
+		if len(group.Links) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	nav = groups
+
+	index := sitePageData{Course: course.Course, Nav: nav, Current: siteIndex}
+	if len(links) > 0 {
+		index.Next = &links[0]
+	}
+	indexPage, err := e.render(siteIndex, index)
+	if err != nil {
+		return nil, err
+	}
+
+	search := make([]siteSearchEntry, 0, len(lessons))
+	lessonFiles := make([]siteFile, 0, len(lessons))
+	for i, lesson := range lessons {
+		page := sitePageData{Course: course.Course, Nav: nav, Current: links[i].Href, Lesson: lesson}
+		if i > 0 {
+			page.Prev = &links[i-1]
+		}
+		if i+1 < len(links) {
+			page.Next = &links[i+1]
+		}
+
+		html, err := e.render(links[i].Href, page)
+		if err != nil {
+			return nil, err
+		}
+		lessonFiles = append(lessonFiles, siteFile{Name: links[i].Href, Data: html})
+		search = append(search, siteSearchEntry{
+			Title: fmt.Sprintf("Lesson %d: %s", lesson.Number, lesson.Title),
+			URL:   links[i].Href,
+			Text:  e.searchText(lesson),
+		})
+	}
+
+	searchIndex, err := json.Marshal(search)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build search index: %w", err)
+	}
+
+	files := []siteFile{
+		{Name: siteIndex, Data: indexPage},
+		{Name: siteStylesheet, Data: []byte(e.html.stylesheet(course) + "\n" + htmlSiteCSS)},
+		{Name: siteSearch, Data: []byte(htmlSiteSearch)},
+		{Name: siteSearchIndex, Data: []byte("window.siteSearchIndex = " + string(searchIndex) + ";\n")},
+	}
+	return append(files, lessonFiles...), nil
+}
+
+// render executes the page template for the named file.
+func (e *HTMLSiteExporter) render(name string, data sitePageData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := e.page.ExecuteTemplate(&buf, "sitePage", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// searchText returns the plain text of a lesson for the search index.
+func (e *HTMLSiteExporter) searchText(lesson *templateSection) string {
+	var parts []string
+	add := func(html string) {
+		if text := e.htmlCleaner.CleanHTML(html); text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	add(lesson.Description)
+	for _, item := range lesson.Items {
+		for _, subItem := range item.Items {
+			add(subItem.Heading)
+			add(subItem.Title)
+			add(subItem.Paragraph)
+			add(subItem.Caption)
+			for _, answer := range subItem.Answers {
+				add(answer.Title)
+			}
+			add(subItem.Feedback)
+			for _, side := range []*models.CardSide{subItem.Front, subItem.Back} {
+				if side != nil {
+					add(side.Description)
+				}
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
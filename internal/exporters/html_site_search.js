@@ -0,0 +1,94 @@
+// Searches the course pages using the index in search-index.js.
+// The index is a script rather than JSON so that it also loads when the site
+// is opened from disk, where browsers refuse to fetch local files.
+(function () {
+  "use strict";
+
+  var maxResults = 20;
+
+  function terms(query) {
+    return query.toLowerCase().split(/\s+/).filter(function (term) {
+      return term !== "";
+    });
+  }
+
+  // Every term must occur in the title or text; title matches rank first.
+  function search(index, query) {
+    var words = terms(query);
+    if (words.length === 0) {
+      return [];
+    }
+
+    var results = [];
+    index.forEach(function (page) {
+      var title = page.title.toLowerCase();
+      var text = page.text.toLowerCase();
+      var score = 0;
+      for (var i = 0; i < words.length; i++) {
+        if (title.indexOf(words[i]) !== -1) {
+          score += 2;
+        } else if (text.indexOf(words[i]) !== -1) {
+          score += 1;
+        } else {
+          return;
+        }
+      }
+      results.push({ page: page, score: score });
+    });
+
+    results.sort(function (a, b) {
+      return b.score - a.score;
+    });
+    return results.slice(0, maxResults).map(function (result) {
+      return result.page;
+    });
+  }
+
+  function snippet(text, query) {
+    var words = terms(query);
+    var at = words.length ? text.toLowerCase().indexOf(words[0]) : -1;
+    if (at === -1) {
+      return text.slice(0, 120);
+    }
+    var start = Math.max(0, at - 40);
+    return (start > 0 ? "…" : "") + text.slice(start, start + 120);
+  }
+
+  function render(list, pages, query) {
+    list.textContent = "";
+    if (pages.length === 0) {
+      var empty = document.createElement("li");
+      empty.textContent = "No results";
+      list.appendChild(empty);
+      return;
+    }
+    pages.forEach(function (page) {
+      var item = document.createElement("li");
+      var link = document.createElement("a");
+      link.href = page.url;
+      link.textContent = page.title;
+      var text = document.createElement("p");
+      text.textContent = snippet(page.text, query);
+      item.appendChild(link);
+      item.appendChild(text);
+      list.appendChild(item);
+    });
+  }
+
+  document.addEventListener("DOMContentLoaded", function () {
+    var input = document.getElementById("site-search-input");
+    var list = document.getElementById("site-search-results");
+    var index = window.siteSearchIndex || [];
+    if (!input || !list) {
+      return;
+    }
+
+    input.addEventListener("input", function () {
+      var query = input.value.trim();
+      list.hidden = query === "";
+      if (!list.hidden) {
+        render(list, search(index, query), query);
+      }
+    });
+  });
+})();
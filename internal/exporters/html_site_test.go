@@ -0,0 +1,216 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// createTestCourseForSite returns a course with a lesson before the first
+// section, two sections with lessons and an empty trailing section.
+func createTestCourseForSite() *models.Course {
+	return &models.Course{
+		Course: models.CourseInfo{
+			Title:       "Site <Course>",
+			Description: "<p>Welcome</p>",
+			Lessons: []models.Lesson{
+				{Title: "Introduction", Items: []models.Item{
+					{Type: "text", Items: []models.SubItem{{Paragraph: "<p>Photosynthesis basics</p>"}}},
+				}},
+				{Title: "Part one", Type: "section"},
+				{Title: "Cells", Description: "<p>About cells</p>", Items: []models.Item{
+					{Type: "knowledgeCheck", Items: []models.SubItem{{
+						Title:   "<p>What is a cell?</p>",
+						Answers: []models.Answer{{Title: "A unit of life", Correct: true}},
+					}}},
+				}},
+				{Title: "Part two", Type: "section"},
+				{Title: "Plants", Items: []models.Item{
+					{Type: "flashcard", Items: []models.SubItem{{
+						Front: &models.CardSide{Description: "<p>Chlorophyll</p>"},
+						Back:  &models.CardSide{Description: "<p>Green pigment</p>"},
+					}}},
+				}},
+				{Title: "Empty part", Type: "section"},
+			},
+		},
+	}
+}
+
+// exportSite exports a course with the site exporter and returns the files by name.
+func exportSite(t *testing.T, course *models.Course) ([]string, map[string]string) {
+	t.Helper()
+	exporter := NewHTMLSiteExporter(services.NewHTMLCleaner())
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	var names []string
+	files := map[string]string{}
+	for _, entry := range readZipFiles(t, buf.Bytes()) {
+		names = append(names, entry.name)
+		files[entry.name] = entry.data
+	}
+	return names, files
+}
+
+// TestHTMLSiteExporter_SupportedFormat tests the SupportedFormat method.
+func TestHTMLSiteExporter_SupportedFormat(t *testing.T) {
+	exporter := NewHTMLSiteExporter(services.NewHTMLCleaner())
+	if got := exporter.SupportedFormat(); got != "html-site" {
+		t.Errorf("Expected format 'html-site', got '%s'", got)
+	}
+}
+
+// TestHTMLSiteExporter_Files tests the files of the site and the index page.
+func TestHTMLSiteExporter_Files(t *testing.T) {
+	names, files := exportSite(t, createTestCourseForSite())
+
+	expected := []string{"index.html", "styles.css", "search.js", "search-index.js", "lesson-001.html", "lesson-002.html", "lesson-003.html"}
+	if !slices.Equal(names, expected) {
+		t.Fatalf("Expected files %v, got %v", expected, names)
+	}
+
+	index := files["index.html"]
+	for _, s := range []string{
+		"<h1>Site &lt;Course&gt;</h1>",
+		"<p>Welcome</p>",
+		`<a class="site-next" href="lesson-001.html" rel="next">Start: Introduction`,
+		`<a href="lesson-003.html">3. Plants</a>`,
+	} {
+		if !strings.Contains(index, s) {
+			t.Errorf("Expected index to contain '%s'", s)
+		}
+	}
+	if strings.Contains(index, "aria-current") {
+		t.Error("Expected the index page not to mark a lesson as current")
+	}
+	if !strings.Contains(files["styles.css"], ".site-sidebar") || !strings.Contains(files["styles.css"], ".lesson {") {
+		t.Error("Expected the stylesheet to combine the HTML and site styles")
+	}
+}
+
+// TestHTMLSiteExporter_Navigation tests the sidebar groups and the
+// previous/next links of the lesson pages.
+func TestHTMLSiteExporter_Navigation(t *testing.T) {
+	_, files := exportSite(t, createTestCourseForSite())
+
+	page := files["lesson-002.html"]
+	for _, s := range []string{
+		"<title>Cells - Site &lt;Course&gt;</title>",
+		"<h3>Lesson 2: Cells</h3>",
+		"<p>About cells</p>",
+		"What is a cell?",
+		`<a href="lesson-002.html" aria-current="page">2. Cells</a>`,
+		`<a href="lesson-001.html">1. Introduction</a>`,
+		`<h2 class="site-nav-section">Part one</h2>`,
+		`<h2 class="site-nav-section">Part two</h2>`,
+		`<a class="site-prev" href="lesson-001.html" rel="prev">&larr; Introduction</a>`,
+		`<a class="site-next" href="lesson-003.html" rel="next">Plants &rarr;</a>`,
+	} {
+		if !strings.Contains(page, s) {
+			t.Errorf("Expected lesson page to contain '%s'", s)
+		}
+	}
+	if strings.Contains(page, "Empty part") {
+		t.Error("Expected sections without lessons to be left out of the sidebar")
+	}
+
+	// The sidebar groups appear in course order, lessons within their section
+	if strings.Index(page, "1. Introduction") > strings.Index(page, "Part one") ||
+		strings.Index(page, "Part one") > strings.Index(page, "2. Cells") ||
+		strings.Index(page, "Part two") > strings.Index(page, "3. Plants") {
+		t.Error("Expected the sidebar to group lessons below their sections")
+	}
+
+	if strings.Contains(files["lesson-001.html"], `rel="prev"`) {
+		t.Error("Expected the first lesson to have no previous link")
+	}
+	if strings.Contains(files["lesson-003.html"], `rel="next"`) {
+		t.Error("Expected the last lesson to have no next link")
+	}
+	if !strings.Contains(files["lesson-003.html"], "Chlorophyll") {
+		t.Error("Expected lesson pages to render their items")
+	}
+}
+
+// TestHTMLSiteExporter_RelativeLinks tests that every page refers to the
+// other files of the site by relative path.
+func TestHTMLSiteExporter_RelativeLinks(t *testing.T) {
+	names, files := exportSite(t, createTestCourseForSite())
+
+	link := regexp.MustCompile(`(?:href|src)="([^"]*)"`)
+	for name, data := range files {
+		if !strings.HasSuffix(name, ".html") {
+			continue
+		}
+		for _, m := range link.FindAllStringSubmatch(data, -1) {
+			if !slices.Contains(names, m[1]) {
+				t.Errorf("%s: expected %q to be a file of the site", name, m[1])
+			}
+		}
+	}
+}
+
+// TestHTMLSiteExporter_SearchIndex tests the search index script.
+func TestHTMLSiteExporter_SearchIndex(t *testing.T) {
+	_, files := exportSite(t, createTestCourseForSite())
+
+	script := files["search-index.js"]
+	const prefix = "window.siteSearchIndex = "
+	if !strings.HasPrefix(script, prefix) || !strings.HasSuffix(script, ";\n") {
+		t.Fatalf("Unexpected search index script: %q", script)
+	}
+
+	var entries []siteSearchEntry
+	if err := json.Unmarshal([]byte(strings.TrimSuffix(strings.TrimPrefix(script, prefix), ";\n")), &entries); err != nil {
+		t.Fatalf("Search index is not valid JSON: %v", err)
+	}
+	expected := []siteSearchEntry{
+		{Title: "Lesson 1: Introduction", URL: "lesson-001.html", Text: "Photosynthesis basics"},
+		{Title: "Lesson 2: Cells", URL: "lesson-002.html", Text: "About cells What is a cell? A unit of life"},
+		{Title: "Lesson 3: Plants", URL: "lesson-003.html", Text: "Chlorophyll Green pigment"},
+	}
+	if !slices.Equal(entries, expected) {
+		t.Errorf("Expected search index %+v, got %+v", expected, entries)
+	}
+}
+
+// TestHTMLSiteExporter_Export tests that Export writes the site into a directory.
+func TestHTMLSiteExporter_Export(t *testing.T) {
+	exporter := NewHTMLSiteExporter(services.NewHTMLCleaner())
+	outputDir := filepath.Join(t.TempDir(), "site")
+
+	if err := exporter.Export(createTestCourseForSite(), outputDir); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	for _, name := range []string{"index.html", "styles.css", "search.js", "search-index.js", "lesson-003.html"} {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Errorf("Expected %s to be written: %v", name, err)
+		}
+	}
+}
+
+// TestHTMLSiteExporter_Export_InvalidPath tests that an output path below a
+// file is rejected.
+func TestHTMLSiteExporter_Export_InvalidPath(t *testing.T) {
+	exporter := NewHTMLSiteExporter(services.NewHTMLCleaner())
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	if err := exporter.Export(createTestCourseForSite(), filepath.Join(file, "site")); err == nil {
+		t.Error("Expected an error for an output path below a file")
+	}
+}
@@ -19,6 +19,7 @@ const (
 	itemTypeImage          = "image"
 	itemTypeInteractive    = "interactive"
 	itemTypeDivider        = "divider"
+	itemTypeFlashcard      = "flashcard"
 )
 
 // lessonTypeSection identifies a lesson that acts as a section header.
@@ -43,9 +44,11 @@ type templateSection struct {
 
 // templateItem represents a course item with preprocessed data.
 type templateItem struct {
-	Type      string
-	TypeTitle string
-	Items     []templateSubItem
+	Type         string
+	TypeTitle    string
+	ListStyle    string
+	QuestionType string
+	Items        []templateSubItem
 }
 
 // templateSubItem represents a sub-item with preprocessed data.
@@ -58,6 +61,8 @@ type templateSubItem struct {
 	Answers   []models.Answer
 	Feedback  string
 	Media     *models.Media
+	Front     *models.CardSide
+	Back      *models.CardSide
 }
 
 // prepareTemplateData converts a Course model into template-friendly data.
@@ -102,11 +107,20 @@ func prepareItems(items []models.Item, htmlCleaner *services.HTMLCleaner) []temp
 		// Set type title for unknown items
 		if tItem.Type != itemTypeText && tItem.Type != itemTypeList && tItem.Type != itemTypeKnowledgeCheck &&
 			tItem.Type != itemTypeMultimedia && tItem.Type != itemTypeImage && tItem.Type != itemTypeInteractive &&
-			tItem.Type != itemTypeDivider {
+			tItem.Type != itemTypeDivider && tItem.Type != itemTypeFlashcard {
 			caser := cases.Title(language.English)
 			tItem.TypeTitle = caser.String(item.Type)
 		}
 
+		// Apply the decoded item settings
+		details := services.ItemDetails(&item)
+		if details.List != nil {
+			tItem.ListStyle = details.List.Style
+		}
+		if details.KnowledgeCheck != nil {
+			tItem.QuestionType = details.KnowledgeCheck.QuestionType
+		}
+
 		// Process sub-items
 		for _, subItem := range item.Items {
 			tSubItem := templateSubItem{
@@ -117,6 +131,8 @@ func prepareItems(items []models.Item, htmlCleaner *services.HTMLCleaner) []temp
 				Answers:   subItem.Answers,
 				Feedback:  subItem.Feedback,
 				Media:     subItem.Media,
+				Front:     subItem.Front,
+				Back:      subItem.Back,
 			}
 
 			// Clean HTML for list items
@@ -1,6 +1,7 @@
 package exporters
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"strings"
@@ -126,6 +127,24 @@ func TestHTMLExporter_Export(t *testing.T) {
 	}
 }
 
+// TestHTMLExporter_ExportTo tests writing HTML to an io.Writer.
+func TestHTMLExporter_ExportTo(t *testing.T) {
+	exporter := NewHTMLExporter(services.NewHTMLCleaner())
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(createTestCourseForHTML(), &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.HasPrefix(output, "<!DOCTYPE html>") {
+		t.Error("Output should start with the HTML doctype")
+	}
+	if !strings.Contains(output, "<title>Test Course</title>") {
+		t.Error("Output should contain the course title")
+	}
+}
+
 // TestHTMLExporter_Export_InvalidPath tests export with invalid output path.
 func TestHTMLExporter_Export_InvalidPath(t *testing.T) {
 	htmlCleaner := services.NewHTMLCleaner()
@@ -390,6 +409,46 @@ func TestHTMLExporter_HTMLCleaning(t *testing.T) {
 	if !strings.Contains(contentStr, "List item with bold text") {
 		t.Error("Should clean HTML from list items")
 	}
+
+	// Scripts in course HTML should be removed
+	if strings.Contains(contentStr, "alert('xss')") {
+		t.Error("Should remove scripts from course HTML")
+	}
+}
+
+// TestHTMLExporter_SanitizeHTML tests that untrusted course HTML is
+// sanitized unless the course is trusted.
+func TestHTMLExporter_SanitizeHTML(t *testing.T) {
+	course := &models.Course{Course: models.CourseInfo{
+		Title:       "Untrusted",
+		Description: `<p onclick="steal()">Intro <a href="javascript:steal()">link</a></p>`,
+		Lessons: []models.Lesson{{
+			Title: "Lesson",
+			Items: []models.Item{{
+				Type: "text",
+				Items: []models.SubItem{{
+					Paragraph: `<p><strong>Kept</strong><img src="x.png" onerror="steal()"><script>steal()</script></p>`,
+				}},
+			}},
+		}},
+	}}
+
+	sanitized := exportHTMLWithOptions(t, course, HTMLOptions{})
+	for _, unsafe := range []string{"onclick", "onerror", "javascript:", "<script>steal()"} {
+		if strings.Contains(sanitized, unsafe) {
+			t.Errorf("Expected '%s' to be removed", unsafe)
+		}
+	}
+	for _, kept := range []string{"<strong>Kept</strong>", `<img src="x.png"/>`, "<a>link</a>"} {
+		if !strings.Contains(sanitized, kept) {
+			t.Errorf("Expected output to contain '%s'", kept)
+		}
+	}
+
+	trusted := exportHTMLWithOptions(t, course, HTMLOptions{TrustHTML: true})
+	if !strings.Contains(trusted, `<script>steal()</script>`) || !strings.Contains(trusted, `onerror="steal()"`) {
+		t.Error("Expected trusted course HTML to be embedded as it is")
+	}
 }
 
 // createTestCourseForHTML creates a test course for HTML export tests.
@@ -506,3 +565,98 @@ func BenchmarkHTMLExporter_ComplexCourse(b *testing.B) {
 		}
 	}
 }
+
+// TestHTMLExporter_ItemVariants tests that list styles and question types shape the markup.
+func TestHTMLExporter_ItemVariants(t *testing.T) {
+	exporter := NewHTMLExporter(services.NewHTMLCleaner())
+	course := &models.Course{
+		Course: models.CourseInfo{
+			Title: "Variants",
+			Lessons: []models.Lesson{{
+				Title: "Lesson",
+				Items: []models.Item{
+					{Type: "list", Variant: "numbered", Items: []models.SubItem{{Paragraph: "<p>Step one</p>"}}},
+					{Type: "list", Variant: "checkboxes", Items: []models.SubItem{{Paragraph: "<p>Task</p>"}}},
+					{
+						Type:    "knowledgeCheck",
+						Variant: "multipleResponse",
+						Items: []models.SubItem{{
+							Title:   "<p>Pick colours</p>",
+							Answers: []models.Answer{{Title: "Red", Correct: true}, {Title: "Blue", Correct: true}},
+						}},
+					},
+				},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	output := buf.String()
+	expected := []string{
+		"<ol>",
+		`<ul class="checklist">`,
+		`type="checkbox"`,
+		`data-question-type="multiple"`,
+		`class="question-hint"`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(output, s) {
+			t.Errorf("Expected output to contain '%s'", s)
+		}
+	}
+}
+
+// TestHTMLExporter_Flashcards tests that flashcard blocks and cards in
+// interactive blocks render both sides.
+func TestHTMLExporter_Flashcards(t *testing.T) {
+	exporter := NewHTMLExporter(services.NewHTMLCleaner())
+	course := &models.Course{
+		Course: models.CourseInfo{
+			Title: "Cards",
+			Lessons: []models.Lesson{{
+				Title: "Lesson",
+				Items: []models.Item{
+					{Type: "flashcard", Items: []models.SubItem{{
+						Front: &models.CardSide{Description: "<p>Photosynthesis</p>"},
+						Back: &models.CardSide{
+							Description: "<p>Turning light into energy</p>",
+							Media:       &models.Media{Image: &models.ImageMedia{OriginalURL: "https://example.com/leaf.jpg"}},
+						},
+					}}},
+					{Type: "interactive", Items: []models.SubItem{{
+						Title: "<p>Flip me</p>",
+						Front: &models.CardSide{Description: "<p>Osmosis</p>"},
+					}}},
+				},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	output := buf.String()
+	expected := []string{
+		`class="item flashcard-item"`,
+		`<div class="flashcard-side flashcard-front">`,
+		"<p>Photosynthesis</p>",
+		`<div class="flashcard-side flashcard-back">`,
+		"<p>Turning light into energy</p>",
+		"https://example.com/leaf.jpg",
+		"<p>Osmosis</p>",
+	}
+	for _, s := range expected {
+		if !strings.Contains(output, s) {
+			t.Errorf("Expected output to contain '%s'", s)
+		}
+	}
+	if strings.Count(output, `class="flashcard"`) != 2 {
+		t.Errorf("Expected 2 flashcards, got %d", strings.Count(output, `class="flashcard"`))
+	}
+}
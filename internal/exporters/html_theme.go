@@ -0,0 +1,136 @@
+package exporters
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Themes of the HTML exporters.
+const (
+	// ThemeDefault uses the accent colours of the stylesheet
+	ThemeDefault = "default"
+	// ThemeCourse derives the accent colours from the course colour
+	ThemeCourse = "course"
+)
+
+// HTMLOptions customizes the templates and stylesheet of the HTML exporters.
+// The zero value uses the built-in templates, stylesheet and theme.
+type HTMLOptions struct {
+	// TemplateDir is a directory of *.gohtml files whose {{define}} blocks
+	// replace the built-in templates of the same name, such as "textItem" or
+	// "knowledgeCheckItem". Templates it does not define keep their built-in
+	// version.
+	TemplateDir string
+	// Stylesheet is a CSS file used instead of the built-in stylesheet
+	Stylesheet string
+	// Theme is ThemeDefault or ThemeCourse; empty means ThemeDefault
+	Theme string
+	// TrustHTML embeds the course HTML as it is instead of sanitizing it.
+	// Only set it for courses from trusted authors.
+	TrustHTML bool
+}
+
+// applyHTMLOptions returns the templates and stylesheet described by opts,
+// starting from the built-in tmpl and css.
+//
+// Parameters:
+//   - tmpl: The built-in templates; they are cloned, not modified
+//   - css: The built-in stylesheet
+//   - opts: The customizations to apply
+//
+// Returns:
+//   - The templates with the overrides from opts.TemplateDir
+//   - The stylesheet to use
+//   - An error if the theme is unknown or a file cannot be read or parsed
+func applyHTMLOptions(tmpl *template.Template, css string, opts HTMLOptions) (*template.Template, string, error) {
+	switch opts.Theme {
+	case "", ThemeDefault, ThemeCourse:
+	default:
+		return nil, "", fmt.Errorf("unknown theme %q, expected %q or %q", opts.Theme, ThemeDefault, ThemeCourse)
+	}
+
+	if opts.TemplateDir != "" {
+		files, err := filepath.Glob(filepath.Join(opts.TemplateDir, "*.gohtml"))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list templates: %w", err)
+		}
+		if len(files) == 0 {
+			return nil, "", fmt.Errorf("no *.gohtml templates found in %s", opts.TemplateDir)
+		}
+
+		tmpl = template.Must(tmpl.Clone())
+		for _, file := range files {
+			// #nosec G304 - Template files are chosen by the user via CLI flag or environment
+			text, err := os.ReadFile(file)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to read template: %w", err)
+			}
+			// Templates the file defines replace the built-in ones of the same name
+			if _, err := tmpl.New(filepath.Base(file)).Parse(string(text)); err != nil {
+				return nil, "", fmt.Errorf("failed to parse template %s: %w", file, err)
+			}
+		}
+	}
+
+	if opts.Stylesheet != "" {
+		// #nosec G304 - The stylesheet is chosen by the user via CLI flag or environment
+		data, err := os.ReadFile(opts.Stylesheet)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read stylesheet: %w", err)
+		}
+		css = string(data)
+	}
+
+	return tmpl, css, nil
+}
+
+// courseThemeCSS returns CSS that sets the accent colours to the course
+// colour, a hex colour such as "#2e8b57". The gradient runs from the colour
+// to a darker shade, and text on accent backgrounds turns dark when the
+// colour is light. It returns an empty string if the colour is not valid.
+func courseThemeCSS(color string) string {
+	r, g, b, ok := parseHexColor(color)
+	if !ok {
+		return ""
+	}
+
+	text := "white"
+	if relativeLuminance(r, g, b) > 0.5 {
+		text = "#1a202c"
+	}
+	accent := fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	end := fmt.Sprintf("#%02x%02x%02x", r*7/10, g*7/10, b*7/10)
+
+	return fmt.Sprintf(":root {\n  --accent: %s;\n  --accent-start: %s;\n  --accent-end: %s;\n  --accent-text: %s;\n}\n",
+		accent, accent, end, text)
+}
+
+// parseHexColor parses a CSS hex colour in "#rgb" or "#rrggbb" form.
+func parseHexColor(color string) (r, g, b int, ok bool) {
+	hex, found := strings.CutPrefix(strings.TrimSpace(color), "#")
+	if !found {
+		return 0, 0, 0, false
+	}
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return 0, 0, 0, false
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return int(v >> 16), int(v >> 8 & 0xff), int(v & 0xff), true
+}
+
+// relativeLuminance approximates the perceived brightness of a colour, from
+// 0 for black to 1 for white.
+func relativeLuminance(r, g, b int) float64 {
+	return (0.2126*float64(r) + 0.7152*float64(g) + 0.0722*float64(b)) / 255
+}
@@ -0,0 +1,238 @@
+package exporters
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// exportHTMLWithOptions exports the HTML test course with the given options.
+func exportHTMLWithOptions(t *testing.T, course *models.Course, opts HTMLOptions) string {
+	t.Helper()
+	exporter, err := NewHTMLExporterWithOptions(services.NewHTMLCleaner(), opts)
+	if err != nil {
+		t.Fatalf("NewHTMLExporterWithOptions failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+	return buf.String()
+}
+
+// writeTestFile writes a file below dir and returns its path.
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// TestHTMLExporter_TemplateDir tests that templates in the template directory
+// replace the built-in templates of the same name only.
+func TestHTMLExporter_TemplateDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "text.gohtml",
+		`{{define "textItem"}}<div class="custom-text">{{range .Items}}{{safeHTML .Paragraph}}{{end}}</div>{{end}}`)
+	writeTestFile(t, dir, "notes.txt", `{{define "listItem"}}ignored{{end}}`)
+
+	output := exportHTMLWithOptions(t, createTestCourseForHTML(), HTMLOptions{TemplateDir: dir})
+
+	if !strings.Contains(output, `<div class="custom-text"><p>Test paragraph content.</p></div>`) {
+		t.Error("Expected text items to use the custom template")
+	}
+	if strings.Contains(output, `class="item text-item"`) {
+		t.Error("Expected the built-in text template to be replaced")
+	}
+	if !strings.Contains(output, `<div class="item list-item">`) {
+		t.Error("Expected list items to fall back to the built-in template")
+	}
+	if strings.Contains(output, "ignored") {
+		t.Error("Expected files without the .gohtml extension to be ignored")
+	}
+
+	// The built-in templates are not modified
+	if strings.Contains(exportHTMLWithOptions(t, createTestCourseForHTML(), HTMLOptions{}), "custom-text") {
+		t.Error("Expected exporters without options to keep the built-in templates")
+	}
+}
+
+// TestHTMLExporter_Stylesheet tests that a stylesheet replaces the built-in one.
+func TestHTMLExporter_Stylesheet(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "brand.css", "body { font-family: serif; }")
+
+	output := exportHTMLWithOptions(t, createTestCourseForHTML(), HTMLOptions{Stylesheet: path})
+
+	if !strings.Contains(output, "body { font-family: serif; }") {
+		t.Error("Expected the page to include the custom stylesheet")
+	}
+	if strings.Contains(output, "--accent-start") {
+		t.Error("Expected the built-in stylesheet to be replaced")
+	}
+}
+
+// TestHTMLExporter_CourseTheme tests that the course theme derives the accent
+// colours from the course colour.
+func TestHTMLExporter_CourseTheme(t *testing.T) {
+	course := createTestCourseForHTML()
+	course.Course.Color = "#2E8B57"
+
+	output := exportHTMLWithOptions(t, course, HTMLOptions{Theme: ThemeCourse})
+	if !strings.Contains(output, "--accent: #2e8b57;") || !strings.Contains(output, "--accent-end: #20613c;") {
+		t.Error("Expected the accent colours to follow the course colour")
+	}
+	// The theme overrides the defaults, so it must come after them
+	if strings.Index(output, "--accent: #2e8b57;") < strings.Index(output, "--accent: #4299e1;") {
+		t.Error("Expected the theme to follow the built-in stylesheet")
+	}
+
+	if strings.Contains(exportHTMLWithOptions(t, course, HTMLOptions{}), "#2e8b57") {
+		t.Error("Expected the default theme to ignore the course colour")
+	}
+}
+
+// TestNewHTMLExporterWithOptions_Errors tests the errors for invalid options.
+func TestNewHTMLExporterWithOptions_Errors(t *testing.T) {
+	dir := t.TempDir()
+	broken := t.TempDir()
+	writeTestFile(t, broken, "broken.gohtml", `{{define "textItem"}}{{.Items`)
+
+	tests := []struct {
+		name          string
+		opts          HTMLOptions
+		expectedError string
+	}{
+		{"unknown theme", HTMLOptions{Theme: "neon"}, `unknown theme "neon"`},
+		{"empty template dir", HTMLOptions{TemplateDir: dir}, "no *.gohtml templates found"},
+		{"invalid template", HTMLOptions{TemplateDir: broken}, "failed to parse template"},
+		{"missing stylesheet", HTMLOptions{Stylesheet: filepath.Join(dir, "missing.css")}, "failed to read stylesheet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHTMLExporterWithOptions(services.NewHTMLCleaner(), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing '%s', got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+// TestCourseThemeCSS tests the accent colours derived from course colours.
+func TestCourseThemeCSS(t *testing.T) {
+	tests := []struct {
+		name     string
+		color    string
+		expected []string
+	}{
+		{"dark colour", "#003366", []string{"--accent: #003366;", "--accent-start: #003366;", "--accent-end: #002347;", "--accent-text: white;"}},
+		{"light colour", "#fc0", []string{"--accent: #ffcc00;", "--accent-end: #b28e00;", "--accent-text: #1a202c;"}},
+		{"invalid colour", "teal", nil},
+		{"empty colour", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			css := courseThemeCSS(tt.color)
+			if tt.expected == nil {
+				if css != "" {
+					t.Errorf("Expected no CSS, got %q", css)
+				}
+				return
+			}
+			for _, s := range tt.expected {
+				if !strings.Contains(css, s) {
+					t.Errorf("Expected CSS to contain '%s', got %q", s, css)
+				}
+			}
+		})
+	}
+}
+
+// TestParseHexColor tests parsing CSS hex colours.
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		color   string
+		r, g, b int
+		ok      bool
+	}{
+		{"#ffffff", 255, 255, 255, true},
+		{"#1A2b3C", 26, 43, 60, true},
+		{" #abc ", 170, 187, 204, true},
+		{"ffffff", 0, 0, 0, false},
+		{"#ffff", 0, 0, 0, false},
+		{"#gggggg", 0, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.color, func(t *testing.T) {
+			r, g, b, ok := parseHexColor(tt.color)
+			if ok != tt.ok || r != tt.r || g != tt.g || b != tt.b {
+				t.Errorf("Expected (%d, %d, %d, %v), got (%d, %d, %d, %v)", tt.r, tt.g, tt.b, tt.ok, r, g, b, ok)
+			}
+		})
+	}
+}
+
+// TestHTMLSiteExporter_Options tests that the site exporter uses the HTML
+// options for its pages and stylesheet.
+func TestHTMLSiteExporter_Options(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "text.gohtml", `{{define "textItem"}}<div class="custom-text"></div>{{end}}`)
+	course := createTestCourseForSite()
+	course.Course.Color = "#2e8b57"
+
+	exporter, err := NewHTMLSiteExporterWithOptions(services.NewHTMLCleaner(), HTMLOptions{TemplateDir: dir, Theme: ThemeCourse})
+	if err != nil {
+		t.Fatalf("NewHTMLSiteExporterWithOptions failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	files := map[string]string{}
+	for _, entry := range readZipFiles(t, buf.Bytes()) {
+		files[entry.name] = entry.data
+	}
+	if !strings.Contains(files["lesson-001.html"], `<div class="custom-text"></div>`) {
+		t.Error("Expected lesson pages to use the custom template")
+	}
+	if !strings.Contains(files["styles.css"], "--accent: #2e8b57;") || !strings.Contains(files["styles.css"], ".site-sidebar") {
+		t.Error("Expected the stylesheet to combine the course theme and the site styles")
+	}
+
+	if _, err := NewHTMLSiteExporterWithOptions(services.NewHTMLCleaner(), HTMLOptions{Theme: "neon"}); err == nil {
+		t.Error("Expected an error for an unknown theme")
+	}
+}
+
+// TestFactory_HTMLOptions tests that the factory passes the HTML options to
+// the HTML exporters.
+func TestFactory_HTMLOptions(t *testing.T) {
+	factory := NewFactoryWithOptions(services.NewHTMLCleaner(), Options{HTML: HTMLOptions{Theme: ThemeCourse}})
+	for _, format := range []string{"html", "htm", "html-site"} {
+		if _, err := factory.CreateExporter(format); err != nil {
+			t.Errorf("Expected %s exporter, got error: %v", format, err)
+		}
+	}
+
+	factory = NewFactoryWithOptions(services.NewHTMLCleaner(), Options{HTML: HTMLOptions{Theme: "neon"}})
+	for _, format := range []string{"html", "html-site"} {
+		if _, err := factory.CreateExporter(format); err == nil {
+			t.Errorf("Expected an error creating the %s exporter with an unknown theme", format)
+		}
+	}
+	if _, err := factory.CreateExporter("markdown"); err != nil {
+		t.Errorf("Expected other exporters to ignore the HTML options, got error: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package exporters
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -17,8 +18,18 @@ import (
 // MarkdownExporter implements the Exporter interface for Markdown format.
 // It converts Articulate Rise course data into a structured Markdown document.
 type MarkdownExporter struct {
-	// htmlCleaner is used to convert HTML content to plain text
+	// htmlCleaner is used to convert HTML content to Markdown
 	htmlCleaner *services.HTMLCleaner
+	// flavor is the Markdown flavor of converted HTML; empty means services.MarkdownGFM
+	flavor string
+}
+
+// MarkdownOptions customizes the Markdown exporter. The zero value produces
+// GitHub Flavored Markdown.
+type MarkdownOptions struct {
+	// Flavor is services.MarkdownGFM, services.MarkdownCommonMark or
+	// services.MarkdownPlain; empty means services.MarkdownGFM
+	Flavor string
 }
 
 // NewMarkdownExporter creates a new MarkdownExporter instance.
@@ -35,24 +46,67 @@ func NewMarkdownExporter(htmlCleaner *services.HTMLCleaner) interfaces.Exporter
 	}
 }
 
+// NewMarkdownExporterWithOptions creates a new MarkdownExporter instance that
+// converts course HTML to the given Markdown flavor.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//   - opts: The Markdown flavor to produce
+//
+// Returns:
+//   - An implementation of the Exporter interface for Markdown format
+//   - An error if the flavor is unknown
+func NewMarkdownExporterWithOptions(htmlCleaner *services.HTMLCleaner, opts MarkdownOptions) (interfaces.Exporter, error) {
+	if err := services.ValidateMarkdownFlavor(opts.Flavor); err != nil {
+		return nil, err
+	}
+	return &MarkdownExporter{
+		htmlCleaner: htmlCleaner,
+		flavor:      opts.Flavor,
+	}, nil
+}
+
 // Export converts the course to Markdown format and writes it to the output path.
 func (e *MarkdownExporter) Export(course *models.Course, outputPath string) error {
 	var buf bytes.Buffer
+	e.writeCourse(&buf, course)
 
+	// #nosec G306 - 0644 is appropriate for export files that should be readable by others
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write markdown file: %w", err)
+	}
+	return nil
+}
+
+// ExportTo converts the course to Markdown format and writes it to w.
+func (e *MarkdownExporter) ExportTo(course *models.Course, w io.Writer) error {
+	var buf bytes.Buffer
+	e.writeCourse(&buf, course)
+
+	if _, err := buf.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write markdown: %w", err)
+	}
+	return nil
+}
+
+// writeCourse renders the complete course as Markdown into buf.
+func (e *MarkdownExporter) writeCourse(buf *bytes.Buffer, course *models.Course) {
 	// Write course header
-	fmt.Fprintf(&buf, "# %s\n\n", course.Course.Title)
+	fmt.Fprintf(buf, "# %s\n\n", course.Course.Title)
 
 	if course.Course.Description != "" {
-		fmt.Fprintf(&buf, "%s\n\n", e.htmlCleaner.CleanHTML(course.Course.Description))
+		if description := e.markdown(course.Course.Description); description != "" {
+			fmt.Fprintf(buf, "%s\n\n", description)
+		}
 	}
 
 	// Add metadata
 	buf.WriteString("## Course Information\n\n")
-	fmt.Fprintf(&buf, "- **Course ID**: %s\n", course.Course.ID)
-	fmt.Fprintf(&buf, "- **Share ID**: %s\n", course.ShareID)
-	fmt.Fprintf(&buf, "- **Navigation Mode**: %s\n", course.Course.NavigationMode)
+	fmt.Fprintf(buf, "- **Course ID**: %s\n", course.Course.ID)
+	fmt.Fprintf(buf, "- **Share ID**: %s\n", course.ShareID)
+	fmt.Fprintf(buf, "- **Navigation Mode**: %s\n", course.Course.NavigationMode)
 	if course.Course.ExportSettings != nil {
-		fmt.Fprintf(&buf, "- **Export Format**: %s\n", course.Course.ExportSettings.Format)
+		fmt.Fprintf(buf, "- **Export Format**: %s\n", course.Course.ExportSettings.Format)
 	}
 	buf.WriteString("\n---\n\n")
 
@@ -60,30 +114,26 @@ func (e *MarkdownExporter) Export(course *models.Course, outputPath string) erro
 	lessonCounter := 0
 	for _, lesson := range course.Course.Lessons {
 		if lesson.Type == lessonTypeSection {
-			fmt.Fprintf(&buf, "# %s\n\n", lesson.Title)
+			fmt.Fprintf(buf, "# %s\n\n", lesson.Title)
 			continue
 		}
 
 		lessonCounter++
-		fmt.Fprintf(&buf, "## Lesson %d: %s\n\n", lessonCounter, lesson.Title)
+		fmt.Fprintf(buf, "## Lesson %d: %s\n\n", lessonCounter, lesson.Title)
 
 		if lesson.Description != "" {
-			fmt.Fprintf(&buf, "%s\n\n", e.htmlCleaner.CleanHTML(lesson.Description))
+			if description := e.markdown(lesson.Description); description != "" {
+				fmt.Fprintf(buf, "%s\n\n", description)
+			}
 		}
 
 		// Process lesson items
 		for _, item := range lesson.Items {
-			e.processItemToMarkdown(&buf, item, 3)
+			e.processItemToMarkdown(buf, item, 3)
 		}
 
 		buf.WriteString("\n---\n\n")
 	}
-
-	// #nosec G306 - 0644 is appropriate for export files that should be readable by others
-	if err := os.WriteFile(outputPath, buf.Bytes(), 0o644); err != nil {
-		return fmt.Errorf("failed to write markdown file: %w", err)
-	}
-	return nil
 }
 
 // SupportedFormat returns "markdown".
@@ -112,6 +162,8 @@ func (e *MarkdownExporter) processItemToMarkdown(buf *bytes.Buffer, item models.
 		e.processKnowledgeCheckItem(buf, item, headingPrefix)
 	case itemTypeInteractive:
 		e.processInteractiveItem(buf, item, headingPrefix)
+	case itemTypeFlashcard:
+		e.processFlashcardItem(buf, item, headingPrefix)
 	case itemTypeDivider:
 		e.processDividerItem(buf)
 	default:
@@ -123,13 +175,13 @@ func (e *MarkdownExporter) processItemToMarkdown(buf *bytes.Buffer, item models.
 func (e *MarkdownExporter) processTextItem(buf *bytes.Buffer, item models.Item, headingPrefix string) {
 	for _, subItem := range item.Items {
 		if subItem.Heading != "" {
-			heading := e.htmlCleaner.CleanHTML(subItem.Heading)
+			heading := e.inline(subItem.Heading)
 			if heading != "" {
 				fmt.Fprintf(buf, "%s %s\n\n", headingPrefix, heading)
 			}
 		}
 		if subItem.Paragraph != "" {
-			paragraph := e.htmlCleaner.CleanHTML(subItem.Paragraph)
+			paragraph := e.markdown(subItem.Paragraph)
 			if paragraph != "" {
 				fmt.Fprintf(buf, "%s\n\n", paragraph)
 			}
@@ -137,13 +189,28 @@ func (e *MarkdownExporter) processTextItem(buf *bytes.Buffer, item models.Item,
 	}
 }
 
-// processListItem handles list items with bullet points.
+// processListItem handles list items, rendering numbered lists as ordered
+// lists and checkbox lists as task lists. Task lists are a GFM extension, so
+// other flavors render checkbox lists as bulleted lists.
 func (e *MarkdownExporter) processListItem(buf *bytes.Buffer, item models.Item) {
+	style := services.ItemDetails(&item).List.Style
+
+	number := 0
 	for _, subItem := range item.Items {
 		if subItem.Paragraph != "" {
-			paragraph := e.htmlCleaner.CleanHTML(subItem.Paragraph)
+			paragraph := e.markdown(subItem.Paragraph)
 			if paragraph != "" {
-				fmt.Fprintf(buf, "- %s\n", paragraph)
+				number++
+				marker := "- "
+				switch style {
+				case models.ListStyleNumbered:
+					marker = fmt.Sprintf("%d. ", number)
+				case models.ListStyleCheckboxes:
+					if e.flavor == "" || e.flavor == services.MarkdownGFM {
+						marker = "- [ ] "
+					}
+				}
+				fmt.Fprintf(buf, "%s%s\n", marker, indentMarkdown(paragraph, len(marker)))
 			}
 		}
 	}
@@ -166,7 +233,7 @@ func (e *MarkdownExporter) processMediaSubItem(buf *bytes.Buffer, subItem models
 		e.processImageMedia(buf, subItem.Media)
 	}
 	if subItem.Caption != "" {
-		caption := e.htmlCleaner.CleanHTML(subItem.Caption)
+		caption := e.inline(subItem.Caption)
 		fmt.Fprintf(buf, "*%s*\n", caption)
 	}
 }
@@ -196,7 +263,7 @@ func (e *MarkdownExporter) processImageItem(buf *bytes.Buffer, item models.Item,
 			fmt.Fprintf(buf, "**Image**: %s\n", subItem.Media.Image.OriginalURL)
 		}
 		if subItem.Caption != "" {
-			caption := e.htmlCleaner.CleanHTML(subItem.Caption)
+			caption := e.inline(subItem.Caption)
 			fmt.Fprintf(buf, "*%s*\n", caption)
 		}
 	}
@@ -206,27 +273,55 @@ func (e *MarkdownExporter) processImageItem(buf *bytes.Buffer, item models.Item,
 // processKnowledgeCheckItem handles quiz questions and knowledge checks.
 func (e *MarkdownExporter) processKnowledgeCheckItem(buf *bytes.Buffer, item models.Item, headingPrefix string) {
 	fmt.Fprintf(buf, "%s Knowledge Check\n\n", headingPrefix)
+	questionType := services.ItemDetails(&item).KnowledgeCheck.QuestionType
 	for _, subItem := range item.Items {
-		e.processQuestionSubItem(buf, subItem)
+		e.processQuestionSubItem(buf, subItem, questionType)
 	}
 	buf.WriteString("\n")
 }
 
 // processQuestionSubItem processes individual question items.
-func (e *MarkdownExporter) processQuestionSubItem(buf *bytes.Buffer, subItem models.SubItem) {
+// The question type determines how the answers are presented.
+func (e *MarkdownExporter) processQuestionSubItem(buf *bytes.Buffer, subItem models.SubItem, questionType string) {
 	if subItem.Title != "" {
-		title := e.htmlCleaner.CleanHTML(subItem.Title)
+		title := e.inline(subItem.Title)
 		fmt.Fprintf(buf, "**Question**: %s\n\n", title)
 	}
 
-	e.processAnswers(buf, subItem.Answers)
+	switch questionType {
+	case models.QuestionMultipleChoice:
+		buf.WriteString("*Select all that apply.*\n\n")
+		e.processAnswers(buf, subItem.Answers)
+	case models.QuestionFillIn:
+		e.processAcceptedAnswers(buf, subItem.Answers)
+	case models.QuestionMatching:
+		e.processMatchingAnswers(buf, subItem.Answers)
+	default:
+		e.processAnswers(buf, subItem.Answers)
+	}
 
 	if subItem.Feedback != "" {
-		feedback := e.htmlCleaner.CleanHTML(subItem.Feedback)
+		feedback := e.markdown(subItem.Feedback)
 		fmt.Fprintf(buf, "\n**Feedback**: %s\n", feedback)
 	}
 }
 
+// processAcceptedAnswers lists the accepted answers of a fill-in-the-blank question.
+func (e *MarkdownExporter) processAcceptedAnswers(buf *bytes.Buffer, answers []models.Answer) {
+	buf.WriteString("**Accepted answers**:\n")
+	for _, answer := range answers {
+		fmt.Fprintf(buf, "- %s\n", e.inline(answer.Title))
+	}
+}
+
+// processMatchingAnswers lists the pairs of a matching question.
+func (e *MarkdownExporter) processMatchingAnswers(buf *bytes.Buffer, answers []models.Answer) {
+	buf.WriteString("**Matches**:\n")
+	for i, answer := range answers {
+		fmt.Fprintf(buf, "%d. %s → %s\n", i+1, e.inline(answer.Title), e.inline(answer.MatchTitle))
+	}
+}
+
 // processAnswers processes answer choices for quiz questions.
 func (e *MarkdownExporter) processAnswers(buf *bytes.Buffer, answers []models.Answer) {
 	buf.WriteString("**Answers**:\n")
@@ -235,7 +330,7 @@ func (e *MarkdownExporter) processAnswers(buf *bytes.Buffer, answers []models.An
 		if answer.Correct {
 			correctMark = " ✓"
 		}
-		fmt.Fprintf(buf, "%d. %s%s\n", i+1, answer.Title, correctMark)
+		fmt.Fprintf(buf, "%d. %s%s\n", i+1, e.inline(answer.Title), correctMark)
 	}
 }
 
@@ -244,10 +339,54 @@ func (e *MarkdownExporter) processInteractiveItem(buf *bytes.Buffer, item models
 	fmt.Fprintf(buf, "%s Interactive Content\n\n", headingPrefix)
 	for _, subItem := range item.Items {
 		if subItem.Title != "" {
-			title := e.htmlCleaner.CleanHTML(subItem.Title)
+			title := e.inline(subItem.Title)
 			fmt.Fprintf(buf, "**%s**\n\n", title)
 		}
+		if isFlashcard(&subItem) {
+			e.processCard(buf, subItem)
+		}
+	}
+}
+
+// processFlashcardItem handles flashcard decks, rendering each card's front
+// and back.
+func (e *MarkdownExporter) processFlashcardItem(buf *bytes.Buffer, item models.Item, headingPrefix string) {
+	fmt.Fprintf(buf, "%s Flashcards\n\n", headingPrefix)
+	cardCounter := 0
+	for _, subItem := range item.Items {
+		if !isFlashcard(&subItem) {
+			continue
+		}
+		cardCounter++
+		fmt.Fprintf(buf, "**Card %d**\n\n", cardCounter)
+		e.processCard(buf, subItem)
+	}
+}
+
+// processCard lists the front and back of a flashcard with their media.
+func (e *MarkdownExporter) processCard(buf *bytes.Buffer, subItem models.SubItem) {
+	for _, side := range []struct {
+		label string
+		side  *models.CardSide
+	}{{"Front", subItem.Front}, {"Back", subItem.Back}} {
+		if side.side == nil {
+			continue
+		}
+		if text := e.markdown(side.side.Description); text != "" {
+			fmt.Fprintf(buf, "- **%s**: %s\n", side.label, indentMarkdown(text, 2))
+		} else {
+			fmt.Fprintf(buf, "- **%s**\n", side.label)
+		}
+		if media := side.side.Media; media != nil {
+			if media.Image != nil {
+				fmt.Fprintf(buf, "  - **Image**: %s\n", media.Image.OriginalURL)
+			}
+			if media.Video != nil {
+				fmt.Fprintf(buf, "  - **Video**: %s\n", media.Video.OriginalURL)
+			}
+		}
 	}
+	buf.WriteString("\n")
 }
 
 // processDividerItem handles divider elements.
@@ -269,11 +408,34 @@ func (e *MarkdownExporter) processUnknownItem(buf *bytes.Buffer, item models.Ite
 // processGenericSubItem processes sub-items for unknown types.
 func (e *MarkdownExporter) processGenericSubItem(buf *bytes.Buffer, subItem models.SubItem) {
 	if subItem.Title != "" {
-		title := e.htmlCleaner.CleanHTML(subItem.Title)
+		title := e.inline(subItem.Title)
 		fmt.Fprintf(buf, "**%s**\n\n", title)
 	}
 	if subItem.Paragraph != "" {
-		paragraph := e.htmlCleaner.CleanHTML(subItem.Paragraph)
+		paragraph := e.markdown(subItem.Paragraph)
 		fmt.Fprintf(buf, "%s\n\n", paragraph)
 	}
 }
+
+// markdown converts course HTML to Markdown blocks of the exporter's flavor.
+func (e *MarkdownExporter) markdown(htmlStr string) string {
+	return e.htmlCleaner.ToMarkdown(htmlStr, e.flavor)
+}
+
+// inline converts course HTML to a single line of Markdown of the exporter's
+// flavor, for headings, titles and captions.
+func (e *MarkdownExporter) inline(htmlStr string) string {
+	return e.htmlCleaner.ToMarkdownInline(htmlStr, e.flavor)
+}
+
+// indentMarkdown indents all but the first line of text by width spaces, so
+// that multi-paragraph text stays inside the list item it starts.
+func indentMarkdown(text string, width int) string {
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = strings.Repeat(" ", width) + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
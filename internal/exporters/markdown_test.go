@@ -121,6 +121,34 @@ func TestMarkdownExporter_Export_InvalidPath(t *testing.T) {
 	}
 }
 
+// TestMarkdownExporter_ExportTo tests writing Markdown to an io.Writer.
+func TestMarkdownExporter_ExportTo(t *testing.T) {
+	exporter := NewMarkdownExporter(services.NewHTMLCleaner())
+	testCourse := createTestCourseForMarkdown()
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(testCourse, &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	// The streamed output must match the file export exactly
+	outputPath := filepath.Join(t.TempDir(), "test-course.md")
+	if err := exporter.Export(testCourse, outputPath); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+
+	if buf.String() != string(content) {
+		t.Error("ExportTo output should match Export output")
+	}
+	if !strings.Contains(buf.String(), "# Test Course") {
+		t.Error("Output should contain course title as main heading")
+	}
+}
+
 // TestMarkdownExporter_ProcessTextItem tests the processTextItem method.
 func TestMarkdownExporter_ProcessTextItem(t *testing.T) {
 	htmlCleaner := services.NewHTMLCleaner()
@@ -143,7 +171,7 @@ func TestMarkdownExporter_ProcessTextItem(t *testing.T) {
 	exporter.processTextItem(&buf, item, "###")
 
 	result := buf.String()
-	expected := "### Test Heading\n\nTest paragraph with bold text.\n\nAnother paragraph.\n\n"
+	expected := "### Test Heading\n\nTest paragraph with **bold** text.\n\nAnother paragraph.\n\n"
 
 	if result != expected {
 		t.Errorf("Expected:\n%q\nGot:\n%q", expected, result)
@@ -168,13 +196,63 @@ func TestMarkdownExporter_ProcessListItem(t *testing.T) {
 	exporter.processListItem(&buf, item)
 
 	result := buf.String()
-	expected := "- First item\n- Second item with emphasis\n- Third item\n\n"
+	expected := "- First item\n- Second item with *emphasis*\n- Third item\n\n"
 
 	if result != expected {
 		t.Errorf("Expected:\n%q\nGot:\n%q", expected, result)
 	}
 }
 
+// TestMarkdownExporter_ProcessListItem_Variants tests list rendering per list variant.
+func TestMarkdownExporter_ProcessListItem_Variants(t *testing.T) {
+	exporter := &MarkdownExporter{htmlCleaner: services.NewHTMLCleaner()}
+	items := []models.SubItem{{Paragraph: "<p>First</p>"}, {Paragraph: "<p>Second</p>"}}
+
+	tests := []struct {
+		variant  string
+		expected string
+	}{
+		{"numbered", "1. First\n2. Second\n\n"},
+		{"checkboxes", "- [ ] First\n- [ ] Second\n\n"},
+		{"bulleted", "- First\n- Second\n\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.variant, func(t *testing.T) {
+			var buf bytes.Buffer
+			exporter.processListItem(&buf, models.Item{Type: "list", Variant: tt.variant, Items: items})
+			if buf.String() != tt.expected {
+				t.Errorf("Expected:\n%q\nGot:\n%q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+// TestMarkdownExporter_ProcessListItem_Flavors tests that checkbox lists are
+// task lists only in GFM.
+func TestMarkdownExporter_ProcessListItem_Flavors(t *testing.T) {
+	item := models.Item{Type: "list", Variant: "checkboxes", Items: []models.SubItem{{Paragraph: "<p>First</p>"}, {Paragraph: "<p>Second</p>"}}}
+
+	tests := []struct {
+		flavor   string
+		expected string
+	}{
+		{"", "- [ ] First\n- [ ] Second\n\n"},
+		{services.MarkdownGFM, "- [ ] First\n- [ ] Second\n\n"},
+		{services.MarkdownCommonMark, "- First\n- Second\n\n"},
+		{services.MarkdownPlain, "- First\n- Second\n\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.flavor, func(t *testing.T) {
+			exporter := &MarkdownExporter{htmlCleaner: services.NewHTMLCleaner(), flavor: tt.flavor}
+			var buf bytes.Buffer
+			exporter.processListItem(&buf, item)
+			if buf.String() != tt.expected {
+				t.Errorf("Expected:\n%q\nGot:\n%q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
 // TestMarkdownExporter_ProcessMultimediaItem tests the processMultimediaItem method.
 func TestMarkdownExporter_ProcessMultimediaItem(t *testing.T) {
 	htmlCleaner := services.NewHTMLCleaner()
@@ -291,6 +369,53 @@ func TestMarkdownExporter_ProcessKnowledgeCheckItem(t *testing.T) {
 	}
 }
 
+// TestMarkdownExporter_ProcessKnowledgeCheckItem_QuestionTypes tests answer rendering per question type.
+func TestMarkdownExporter_ProcessKnowledgeCheckItem_QuestionTypes(t *testing.T) {
+	exporter := &MarkdownExporter{htmlCleaner: services.NewHTMLCleaner()}
+
+	tests := []struct {
+		variant  string
+		answers  []models.Answer
+		expected []string
+	}{
+		{
+			variant:  "multipleResponse",
+			answers:  []models.Answer{{Title: "<p>Red</p>", Correct: true}, {Title: "<b>Blue</b>", Correct: true}},
+			expected: []string{"*Select all that apply.*", "1. Red ✓", "2. **Blue** ✓"},
+		},
+		{
+			variant:  "fillIn",
+			answers:  []models.Answer{{Title: "<p>Paris</p>"}, {Title: "paris"}},
+			expected: []string{"**Accepted answers**:", "- Paris\n- paris"},
+		},
+		{
+			variant:  "matching",
+			answers:  []models.Answer{{Title: "<p>France</p>", MatchTitle: "<em>Paris</em>"}},
+			expected: []string{"**Matches**:", "1. France → *Paris*"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.variant, func(t *testing.T) {
+			var buf bytes.Buffer
+			item := models.Item{
+				Type:    "knowledgeCheck",
+				Variant: tt.variant,
+				Items:   []models.SubItem{{Title: "<p>Question</p>", Answers: tt.answers}},
+			}
+			exporter.processKnowledgeCheckItem(&buf, item, "###")
+
+			for _, expected := range tt.expected {
+				if !strings.Contains(buf.String(), expected) {
+					t.Errorf("Expected output to contain %q, got:\n%s", expected, buf.String())
+				}
+			}
+			if strings.Contains(buf.String(), "<") {
+				t.Errorf("Expected no HTML in the answers, got:\n%s", buf.String())
+			}
+		})
+	}
+}
+
 // TestMarkdownExporter_ProcessInteractiveItem tests the processInteractiveItem method.
 func TestMarkdownExporter_ProcessInteractiveItem(t *testing.T) {
 	htmlCleaner := services.NewHTMLCleaner()
@@ -316,6 +441,46 @@ func TestMarkdownExporter_ProcessInteractiveItem(t *testing.T) {
 	}
 }
 
+// TestMarkdownExporter_ProcessFlashcardItem tests the processFlashcardItem method.
+func TestMarkdownExporter_ProcessFlashcardItem(t *testing.T) {
+	htmlCleaner := services.NewHTMLCleaner()
+	exporter := &MarkdownExporter{htmlCleaner: htmlCleaner}
+
+	var buf bytes.Buffer
+	item := models.Item{
+		Type: "flashcard",
+		Items: []models.SubItem{
+			{
+				Front: &models.CardSide{Description: "<p>Photosynthesis</p>"},
+				Back: &models.CardSide{
+					Description: "<p>Turning <strong>light</strong> into energy</p>",
+					Media:       &models.Media{Image: &models.ImageMedia{OriginalURL: "https://example.com/leaf.jpg"}},
+				},
+			},
+			{Title: "<p>Not a card</p>"},
+			{
+				Front: &models.CardSide{Description: "<p>Osmosis</p>"},
+				Back:  &models.CardSide{Media: &models.Media{Video: &models.VideoMedia{OriginalURL: "https://example.com/osmosis.mp4"}}},
+			},
+		},
+	}
+
+	exporter.processItemToMarkdown(&buf, item, 3)
+
+	expected := "### Flashcards\n\n" +
+		"**Card 1**\n\n" +
+		"- **Front**: Photosynthesis\n" +
+		"- **Back**: Turning **light** into energy\n" +
+		"  - **Image**: https://example.com/leaf.jpg\n\n" +
+		"**Card 2**\n\n" +
+		"- **Front**: Osmosis\n" +
+		"- **Back**\n" +
+		"  - **Video**: https://example.com/osmosis.mp4\n\n"
+	if result := buf.String(); result != expected {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, result)
+	}
+}
+
 // TestMarkdownExporter_ProcessDividerItem tests the processDividerItem method.
 func TestMarkdownExporter_ProcessDividerItem(t *testing.T) {
 	htmlCleaner := services.NewHTMLCleaner()
@@ -592,7 +757,7 @@ func TestMarkdownExporter_ComplexCourse(t *testing.T) {
 	// Verify various elements are present
 	checks := []string{
 		"# Complex Test Course",
-		"This is a complex course description.",
+		"This is a **complex** course description.",
 		"- **Export Format**: scorm",
 		"# Course Section",
 		"## Lesson 1: Introduction Lesson",
@@ -614,6 +779,107 @@ func TestMarkdownExporter_ComplexCourse(t *testing.T) {
 	}
 }
 
+// TestMarkdownExporter_RichText tests that formatting, links, nested lists
+// and tables in course HTML are kept.
+func TestMarkdownExporter_RichText(t *testing.T) {
+	exporter := NewMarkdownExporter(services.NewHTMLCleaner())
+	course := &models.Course{
+		Course: models.CourseInfo{
+			Title: "Rich",
+			Lessons: []models.Lesson{{
+				Title:       "Lesson",
+				Description: `<p>Read the <a href="https://example.com/guide">guide</a> first.</p>`,
+				Items: []models.Item{
+					{
+						Type: "text",
+						Items: []models.SubItem{{
+							Heading:   "<h2>Key <em>terms</em></h2>",
+							Paragraph: "<p>Line one<br>Line two</p><ul><li>Outer<ul><li>Inner</li></ul></li></ul><table><tr><th>Term</th><th>Meaning</th></tr><tr><td>API</td><td><code>GET /</code></td></tr></table>",
+						}},
+					},
+					{
+						Type:    "list",
+						Variant: "numbered",
+						Items:   []models.SubItem{{Paragraph: "<p>First</p><p>More about <b>first</b></p>"}},
+					},
+				},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+	output := buf.String()
+
+	for _, expected := range []string{
+		"Read the [guide](https://example.com/guide) first.",
+		"### Key *terms*\n",
+		"Line one\\\nLine two",
+		"- Outer\n  - Inner",
+		"| Term | Meaning |\n| --- | --- |\n| API | `GET /` |",
+		"1. First\n\n   More about **first**\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+// TestNewMarkdownExporterWithOptions tests the Markdown flavors of the exporter.
+func TestNewMarkdownExporterWithOptions(t *testing.T) {
+	course := createTestCourseForMarkdown()
+	course.Course.Description = `<p><strong>Welcome</strong>, see <a href="https://example.com">our site</a> and <del>old</del> news</p>`
+
+	tests := []struct {
+		flavor   string
+		expected string
+	}{
+		{"", "**Welcome**, see [our site](https://example.com) and ~~old~~ news"},
+		{services.MarkdownGFM, "**Welcome**, see [our site](https://example.com) and ~~old~~ news"},
+		{services.MarkdownCommonMark, "**Welcome**, see [our site](https://example.com) and old news"},
+		{services.MarkdownPlain, "Welcome, see our site (https://example.com) and old news"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.flavor, func(t *testing.T) {
+			exporter, err := NewMarkdownExporterWithOptions(services.NewHTMLCleaner(), MarkdownOptions{Flavor: tt.flavor})
+			if err != nil {
+				t.Fatalf("NewMarkdownExporterWithOptions failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := exporter.ExportTo(course, &buf); err != nil {
+				t.Fatalf("ExportTo failed: %v", err)
+			}
+			if !strings.Contains(buf.String(), tt.expected+"\n\n") {
+				t.Errorf("Expected output to contain %q, got:\n%s", tt.expected, buf.String())
+			}
+		})
+	}
+
+	if _, err := NewMarkdownExporterWithOptions(services.NewHTMLCleaner(), MarkdownOptions{Flavor: "rst"}); err == nil {
+		t.Error("Expected an error for an unknown flavor")
+	}
+}
+
+// TestFactory_MarkdownOptions tests that the factory passes the Markdown
+// options to the Markdown exporter.
+func TestFactory_MarkdownOptions(t *testing.T) {
+	factory := NewFactoryWithOptions(services.NewHTMLCleaner(), Options{Markdown: MarkdownOptions{Flavor: services.MarkdownPlain}})
+	exporter, err := factory.CreateExporter("md")
+	if err != nil {
+		t.Fatalf("Expected markdown exporter, got error: %v", err)
+	}
+	if flavor := exporter.(*MarkdownExporter).flavor; flavor != services.MarkdownPlain {
+		t.Errorf("Expected flavor '%s', got '%s'", services.MarkdownPlain, flavor)
+	}
+
+	factory = NewFactoryWithOptions(services.NewHTMLCleaner(), Options{Markdown: MarkdownOptions{Flavor: "rst"}})
+	if _, err := factory.CreateExporter("markdown"); err == nil {
+		t.Error("Expected an error creating the markdown exporter with an unknown flavor")
+	}
+}
+
 // createTestCourseForMarkdown creates a test course for markdown export testing.
 func createTestCourseForMarkdown() *models.Course {
 	return &models.Course{
@@ -0,0 +1,188 @@
+package exporters
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	stdhtml "html"
+	"io"
+	"os"
+	"text/template"
+
+	"github.com/kjanat/articulate-parser/internal/interfaces"
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+//go:embed moodlexml_template.gotmpl
+var moodleXMLTemplate string
+
+// Moodle question types the knowledge check questions are mapped to.
+const (
+	moodleMultiChoice = "multichoice"
+	moodleShortAnswer = "shortanswer"
+	moodleMatching    = "matching"
+)
+
+// MoodleXMLExporter implements the Exporter interface for Moodle XML.
+// It writes the course's knowledge check questions, in a category per lesson,
+// as a quiz file Moodle's question bank can import.
+type MoodleXMLExporter struct {
+	// htmlCleaner is used to derive question names from question text
+	htmlCleaner *services.HTMLCleaner
+	// tmpl renders the quiz file
+	tmpl *template.Template
+}
+
+// moodleCategoryData is a question bank category with its questions.
+type moodleCategoryData struct {
+	Path      string
+	Questions []moodleQuestion
+}
+
+// moodleQuestion is a question in Moodle XML terms. Text and Feedback are
+// HTML; Answers are set for multiple choice and short answer questions and
+// Subquestions for matching questions.
+type moodleQuestion struct {
+	Type         string
+	Name         string
+	Text         string
+	Feedback     string
+	Single       bool
+	Answers      []moodleAnswer
+	Subquestions []moodleAnswer
+}
+
+// moodleAnswer is an answer with its grade percentage, or a matching pair
+// of a subquestion Text and its Match.
+type moodleAnswer struct {
+	Fraction string
+	Text     string
+	Match    string
+}
+
+// NewMoodleXMLExporter creates a new MoodleXMLExporter instance.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//
+// Returns:
+//   - An implementation of the Exporter interface for Moodle XML format
+func NewMoodleXMLExporter(htmlCleaner *services.HTMLCleaner) interfaces.Exporter {
+	tmpl := template.Must(template.New("moodlexml").Funcs(template.FuncMap{
+		"esc": stdhtml.EscapeString,
+	}).Parse(moodleXMLTemplate))
+
+	return &MoodleXMLExporter{
+		htmlCleaner: htmlCleaner,
+		tmpl:        tmpl,
+	}
+}
+
+// Export writes the course's questions in Moodle XML format to the output path.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - outputPath: The file path where the XML file will be written
+//
+// Returns:
+//   - An error if the course has no questions or writing the file fails
+func (e *MoodleXMLExporter) Export(course *models.Course, outputPath string) error {
+	var buf bytes.Buffer
+	if err := e.ExportTo(course, &buf); err != nil {
+		return err
+	}
+
+	// #nosec G306 - 0644 is appropriate for export files that should be readable by others
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write Moodle XML file: %w", err)
+	}
+	return nil
+}
+
+// ExportTo writes the course's questions in Moodle XML format to w.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - w: The writer the XML will be written to
+//
+// Returns:
+//   - An error if the course has no questions or writing fails
+func (e *MoodleXMLExporter) ExportTo(course *models.Course, w io.Writer) error {
+	lessons := quizLessons(course)
+	if len(lessons) == 0 {
+		return errNoQuestions
+	}
+
+	categories := make([]moodleCategoryData, 0, len(lessons))
+	questionCounter := 0
+	for _, lesson := range lessons {
+		category := moodleCategoryData{Path: moodleCategory(e.htmlCleaner, lesson)}
+		for _, question := range lesson.Questions {
+			questionCounter++
+			category.Questions = append(category.Questions, e.buildQuestion(questionCounter, question))
+		}
+		categories = append(categories, category)
+	}
+
+	if err := e.tmpl.ExecuteTemplate(w, "quiz", categories); err != nil {
+		return fmt.Errorf("failed to write Moodle XML: %w", err)
+	}
+	return nil
+}
+
+// SupportedFormat returns the format name this exporter supports.
+//
+// Returns:
+//   - A string representing the supported format ("moodlexml")
+func (e *MoodleXMLExporter) SupportedFormat() string {
+	return FormatMoodleXML
+}
+
+// buildQuestion converts a question to its Moodle question type, like
+// GIFTExporter.writeQuestion does.
+func (e *MoodleXMLExporter) buildQuestion(n int, question quizQuestion) moodleQuestion {
+	subItem := question.SubItem
+	mq := moodleQuestion{
+		Name:     e.htmlCleaner.CleanHTML(subItem.Title),
+		Text:     subItem.Title,
+		Feedback: subItem.Feedback,
+	}
+	if mq.Name == "" {
+		mq.Name = fmt.Sprintf("Question %d", n)
+	}
+
+	// Moodle compares short answers and matches as plain text
+	switch question.Type {
+	case models.QuestionFillIn:
+		mq.Type = moodleShortAnswer
+		for _, answer := range subItem.Answers {
+			mq.Answers = append(mq.Answers, moodleAnswer{Fraction: "100", Text: e.htmlCleaner.CleanHTML(answer.Title)})
+		}
+	case models.QuestionMatching:
+		mq.Type = moodleMatching
+		for _, answer := range subItem.Answers {
+			mq.Subquestions = append(mq.Subquestions, moodleAnswer{
+				Text:  e.htmlCleaner.CleanHTML(answer.Title),
+				Match: e.htmlCleaner.CleanHTML(answer.MatchTitle),
+			})
+		}
+	default:
+		mq.Type = moodleMultiChoice
+		correct := correctAnswers(subItem.Answers)
+		mq.Single = question.Type != models.QuestionMultipleChoice && correct <= 1
+		weight := moodleFraction(100 / float64(max(correct, 1)))
+		for _, answer := range subItem.Answers {
+			fraction := "0"
+			switch {
+			case answer.Correct:
+				fraction = weight
+			case !mq.Single:
+				fraction = "-100"
+			}
+			mq.Answers = append(mq.Answers, moodleAnswer{Fraction: fraction, Text: answer.Title})
+		}
+	}
+
+	return mq
+}
@@ -0,0 +1,127 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// moodleQuizDoc is a Moodle XML quiz file as read by the tests.
+type moodleQuizDoc struct {
+	Questions []struct {
+		Type     string `xml:"type,attr"`
+		Category string `xml:"category>text"`
+		Name     string `xml:"name>text"`
+		Text     string `xml:"questiontext>text"`
+		Feedback string `xml:"generalfeedback>text"`
+		Single   string `xml:"single"`
+		Answers  []struct {
+			Fraction string `xml:"fraction,attr"`
+			Text     string `xml:"text"`
+		} `xml:"answer"`
+		Subquestions []struct {
+			Text   string `xml:"text"`
+			Answer string `xml:"answer>text"`
+		} `xml:"subquestion"`
+	} `xml:"question"`
+}
+
+// TestMoodleXMLExporter_SupportedFormat tests the SupportedFormat method.
+func TestMoodleXMLExporter_SupportedFormat(t *testing.T) {
+	exporter := NewMoodleXMLExporter(services.NewHTMLCleaner())
+	if got := exporter.SupportedFormat(); got != "moodlexml" {
+		t.Errorf("Expected format 'moodlexml', got '%s'", got)
+	}
+}
+
+// TestMoodleXMLExporter_ExportTo tests categories, question types, answers
+// and feedback in the Moodle XML output.
+func TestMoodleXMLExporter_ExportTo(t *testing.T) {
+	exporter := NewMoodleXMLExporter(services.NewHTMLCleaner())
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(createTestCourseForMoodle(), &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	var quiz moodleQuizDoc
+	if err := xml.Unmarshal(buf.Bytes(), &quiz); err != nil {
+		t.Fatalf("Output is not well-formed: %v", err)
+	}
+
+	types := make([]string, 0, len(quiz.Questions))
+	for _, q := range quiz.Questions {
+		types = append(types, q.Type)
+	}
+	expectedTypes := []string{"category", "multichoice", "multichoice", "category", "shortanswer", "matching"}
+	if !slices.Equal(types, expectedTypes) {
+		t.Fatalf("Expected question types %v, got %v", expectedTypes, types)
+	}
+
+	if quiz.Questions[0].Category != "$course$/top/Equations" || quiz.Questions[3].Category != "$course$/top/Terms//Definitions" {
+		t.Errorf("Unexpected categories: %q, %q", quiz.Questions[0].Category, quiz.Questions[3].Category)
+	}
+
+	// Special characters survive the round trip through XML escaping
+	single := quiz.Questions[1]
+	if single.Name != "What is {x} if x = 2 + 2?" || single.Text != "<p>What is {x} if x = 2 + 2?</p>" ||
+		single.Feedback != "<p>Add: 2 + 2 = 4 #math</p>" || single.Single != "true" {
+		t.Errorf("Unexpected single choice question: %+v", single)
+	}
+	if len(single.Answers) != 2 || single.Answers[0].Fraction != "100" || single.Answers[1].Fraction != "0" ||
+		single.Answers[1].Text != "5 ~ roughly" {
+		t.Errorf("Unexpected single choice answers: %+v", single.Answers)
+	}
+
+	multiple := quiz.Questions[2]
+	if multiple.Single != "false" || len(multiple.Answers) != 3 || multiple.Answers[0].Fraction != "50" ||
+		multiple.Answers[1].Fraction != "-100" || multiple.Answers[2].Fraction != "50" {
+		t.Errorf("Unexpected multiple response question: %+v", multiple)
+	}
+
+	shortAnswer := quiz.Questions[4]
+	if len(shortAnswer.Answers) != 2 || shortAnswer.Answers[1].Text != "Trigon" || shortAnswer.Answers[1].Fraction != "100" {
+		t.Errorf("Unexpected short answer question: %+v", shortAnswer)
+	}
+
+	matching := quiz.Questions[5]
+	if len(matching.Subquestions) != 2 || matching.Subquestions[1].Text != "Sum" || matching.Subquestions[1].Answer != "Σ" ||
+		matching.Feedback != "<p>Greek letters</p>" {
+		t.Errorf("Unexpected matching question: %+v", matching)
+	}
+}
+
+// TestMoodleXMLExporter_NoQuestions tests that a course without questions is rejected.
+func TestMoodleXMLExporter_NoQuestions(t *testing.T) {
+	exporter := NewMoodleXMLExporter(services.NewHTMLCleaner())
+	course := &models.Course{Course: models.CourseInfo{Lessons: []models.Lesson{{Title: "Text only"}}}}
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); !errors.Is(err, errNoQuestions) {
+		t.Errorf("Expected errNoQuestions, got %v", err)
+	}
+}
+
+// TestMoodleXMLExporter_Export tests that Export writes the questions to a file.
+func TestMoodleXMLExporter_Export(t *testing.T) {
+	exporter := NewMoodleXMLExporter(services.NewHTMLCleaner())
+	outputPath := filepath.Join(t.TempDir(), "questions.xml")
+
+	if err := exporter.Export(createTestCourseForMoodle(), outputPath); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte(`<?xml version="1.0" encoding="UTF-8"?>`)) {
+		t.Error("Expected an XML declaration")
+	}
+}
@@ -0,0 +1,386 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kjanat/articulate-parser/internal/interfaces"
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// normalizedSchemaVersion is the version of the normalized course schema,
+// published as schemas/course.v1.schema.json. It changes whenever a field is
+// removed or changes meaning; new optional fields keep the version.
+const normalizedSchemaVersion = 1
+
+// NormalizedExporter implements the Exporter interface for the normalized
+// course schema. It writes a cleaned, stable representation of the course as
+// JSON or YAML: text is given both as the original HTML and as plain text,
+// media URLs are resolved, lessons are numbered and grouped by section, and
+// keys always appear in the same order.
+type NormalizedExporter struct {
+	// htmlCleaner is used to derive plain text from HTML content
+	htmlCleaner *services.HTMLCleaner
+	// format is FormatJSON or FormatYAML
+	format string
+}
+
+// normalizedDocument is the root of the normalized schema. Field order is
+// the key order of the output, so it must match the published schema.
+type normalizedDocument struct {
+	SchemaVersion int              `json:"schemaVersion"`
+	Course        normalizedCourse `json:"course"`
+}
+
+// normalizedCourse holds the course metadata and its sections.
+type normalizedCourse struct {
+	ID             string              `json:"id,omitempty"`
+	ShareID        string              `json:"shareId,omitempty"`
+	Title          string              `json:"title"`
+	Author         string              `json:"author,omitempty"`
+	Description    *normalizedText     `json:"description,omitempty"`
+	Color          string              `json:"color,omitempty"`
+	NavigationMode string              `json:"navigationMode,omitempty"`
+	CoverImage     *normalizedMedia    `json:"coverImage,omitempty"`
+	LessonCount    int                 `json:"lessonCount"`
+	Sections       []normalizedSection `json:"sections"`
+}
+
+// normalizedSection groups the lessons that follow a section header. Lessons
+// before the first section header are in a section without ID and title.
+type normalizedSection struct {
+	ID      string             `json:"id,omitempty"`
+	Title   string             `json:"title,omitempty"`
+	Lessons []normalizedLesson `json:"lessons"`
+}
+
+// normalizedLesson is a lesson with its number, counted across sections.
+type normalizedLesson struct {
+	Number      int              `json:"number"`
+	ID          string           `json:"id,omitempty"`
+	Title       string           `json:"title"`
+	Description *normalizedText  `json:"description,omitempty"`
+	Items       []normalizedItem `json:"items"`
+}
+
+// normalizedItem is a content block. QuestionType and ListStyle are the
+// typed item details; Entries are its sub-items.
+type normalizedItem struct {
+	ID           string            `json:"id,omitempty"`
+	Type         string            `json:"type"`
+	Variant      string            `json:"variant,omitempty"`
+	QuestionType string            `json:"questionType,omitempty"`
+	ListStyle    string            `json:"listStyle,omitempty"`
+	Media        *normalizedMedia  `json:"media,omitempty"`
+	Entries      []normalizedEntry `json:"entries"`
+}
+
+// normalizedEntry is a sub-item: a paragraph, list entry, question, card or
+// other element of a block. Only the fields the sub-item has are set.
+type normalizedEntry struct {
+	ID        string             `json:"id,omitempty"`
+	Title     *normalizedText    `json:"title,omitempty"`
+	Heading   *normalizedText    `json:"heading,omitempty"`
+	Paragraph *normalizedText    `json:"paragraph,omitempty"`
+	Caption   *normalizedText    `json:"caption,omitempty"`
+	Media     *normalizedMedia   `json:"media,omitempty"`
+	Answers   []normalizedAnswer `json:"answers,omitempty"`
+	Feedback  *normalizedText    `json:"feedback,omitempty"`
+	Front     *normalizedCard    `json:"front,omitempty"`
+	Back      *normalizedCard    `json:"back,omitempty"`
+}
+
+// normalizedAnswer is an answer option of a question; Match is set for
+// matching questions.
+type normalizedAnswer struct {
+	ID      string          `json:"id,omitempty"`
+	Text    *normalizedText `json:"text"`
+	Correct bool            `json:"correct"`
+	Match   *normalizedText `json:"match,omitempty"`
+}
+
+// normalizedCard is one side of a flashcard.
+type normalizedCard struct {
+	Description *normalizedText  `json:"description,omitempty"`
+	Media       *normalizedMedia `json:"media,omitempty"`
+}
+
+// normalizedText is rich text as the original HTML and as plain text.
+type normalizedText struct {
+	HTML string `json:"html"`
+	Text string `json:"text"`
+}
+
+// normalizedMedia is the image and video of an element.
+type normalizedMedia struct {
+	Image *normalizedImage `json:"image,omitempty"`
+	Video *normalizedVideo `json:"video,omitempty"`
+}
+
+// normalizedImage is an image with its resolved URL. PackagePath is set when
+// the image is bundled in the package the course was loaded from.
+type normalizedImage struct {
+	URL         string `json:"url,omitempty"`
+	Key         string `json:"key,omitempty"`
+	PackagePath string `json:"packagePath,omitempty"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+}
+
+// normalizedVideo is a video with its resolved URL. PackagePath is set when
+// the video is bundled in the package the course was loaded from.
+type normalizedVideo struct {
+	URL         string `json:"url,omitempty"`
+	Key         string `json:"key,omitempty"`
+	PackagePath string `json:"packagePath,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Poster      string `json:"poster,omitempty"`
+	Duration    int    `json:"duration,omitempty"`
+}
+
+// NewNormalizedExporter creates a new NormalizedExporter instance.
+// Formats other than FormatYAML produce JSON.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//   - format: The output encoding, FormatJSON or FormatYAML
+//
+// Returns:
+//   - An implementation of the Exporter interface for the normalized schema
+func NewNormalizedExporter(htmlCleaner *services.HTMLCleaner, format string) interfaces.Exporter {
+	if format != FormatYAML {
+		format = FormatJSON
+	}
+
+	return &NormalizedExporter{
+		htmlCleaner: htmlCleaner,
+		format:      format,
+	}
+}
+
+// Export writes the normalized course to the output path.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - outputPath: The file path where the JSON or YAML file will be written
+//
+// Returns:
+//   - An error if writing the file fails
+func (e *NormalizedExporter) Export(course *models.Course, outputPath string) error {
+	var buf bytes.Buffer
+	if err := e.ExportTo(course, &buf); err != nil {
+		return err
+	}
+
+	// #nosec G306 - 0644 is appropriate for export files that should be readable by others
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s file: %w", e.format, err)
+	}
+	return nil
+}
+
+// ExportTo writes the normalized course to w.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - w: The writer the JSON or YAML will be written to
+//
+// Returns:
+//   - An error if encoding or writing fails
+func (e *NormalizedExporter) ExportTo(course *models.Course, w io.Writer) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // Keep the HTML fields readable
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(e.normalize(course)); err != nil {
+		return fmt.Errorf("failed to encode course: %w", err)
+	}
+
+	if e.format == FormatYAML {
+		var yaml bytes.Buffer
+		if err := jsonToYAML(&yaml, buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to encode course: %w", err)
+		}
+		buf = yaml
+	}
+
+	if _, err := buf.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write %s: %w", e.format, err)
+	}
+	return nil
+}
+
+// SupportedFormat returns the format name this exporter supports.
+//
+// Returns:
+//   - A string representing the supported format ("json" or "yaml")
+func (e *NormalizedExporter) SupportedFormat() string {
+	return e.format
+}
+
+// normalize converts a course to the normalized schema.
+func (e *NormalizedExporter) normalize(course *models.Course) normalizedDocument {
+	info := &course.Course
+	nc := normalizedCourse{
+		ID:             info.ID,
+		ShareID:        course.ShareID,
+		Title:          info.Title,
+		Author:         course.Author,
+		Description:    e.text(info.Description),
+		Color:          info.Color,
+		NavigationMode: info.NavigationMode,
+		CoverImage:     normalizeMedia(course.Package, info.CoverImage),
+		Sections:       []normalizedSection{},
+	}
+
+	// Lessons before the first section header go into an untitled section,
+	// which is left out if there are none; sections with a header are kept
+	// even when empty
+	section := normalizedSection{Lessons: []normalizedLesson{}}
+	hasHeader := false
+	for i := range info.Lessons {
+		lesson := &info.Lessons[i]
+		if lesson.Type == lessonTypeSection {
+			if hasHeader || len(section.Lessons) > 0 {
+				nc.Sections = append(nc.Sections, section)
+			}
+			section = normalizedSection{ID: lesson.ID, Title: lesson.Title, Lessons: []normalizedLesson{}}
+			hasHeader = true
+			continue
+		}
+
+		nc.LessonCount++
+		nl := normalizedLesson{
+			Number:      nc.LessonCount,
+			ID:          lesson.ID,
+			Title:       lesson.Title,
+			Description: e.text(lesson.Description),
+			Items:       make([]normalizedItem, 0, len(lesson.Items)),
+		}
+		for j := range lesson.Items {
+			nl.Items = append(nl.Items, e.normalizeItem(course.Package, &lesson.Items[j]))
+		}
+		section.Lessons = append(section.Lessons, nl)
+	}
+	if hasHeader || len(section.Lessons) > 0 {
+		nc.Sections = append(nc.Sections, section)
+	}
+
+	return normalizedDocument{SchemaVersion: normalizedSchemaVersion, Course: nc}
+}
+
+// normalizeItem converts a content block and its sub-items.
+func (e *NormalizedExporter) normalizeItem(pkg *models.SourcePackage, item *models.Item) normalizedItem {
+	ni := normalizedItem{
+		ID:      item.ID,
+		Type:    item.Type,
+		Variant: item.Variant,
+		Media:   normalizeMedia(pkg, item.Media),
+		Entries: make([]normalizedEntry, 0, len(item.Items)),
+	}
+	details := services.ItemDetails(item)
+	if details.KnowledgeCheck != nil {
+		ni.QuestionType = details.KnowledgeCheck.QuestionType
+	}
+	if details.List != nil {
+		ni.ListStyle = details.List.Style
+	}
+
+	for i := range item.Items {
+		subItem := &item.Items[i]
+		entry := normalizedEntry{
+			ID:        subItem.ID,
+			Title:     e.text(subItem.Title),
+			Heading:   e.text(subItem.Heading),
+			Paragraph: e.text(subItem.Paragraph),
+			Caption:   e.text(subItem.Caption),
+			Media:     normalizeMedia(pkg, subItem.Media),
+			Feedback:  e.text(subItem.Feedback),
+			Front:     e.card(pkg, subItem.Front),
+			Back:      e.card(pkg, subItem.Back),
+		}
+		for _, answer := range subItem.Answers {
+			text := e.text(answer.Title)
+			if text == nil {
+				text = &normalizedText{} // Answers always have text, even if empty
+			}
+			entry.Answers = append(entry.Answers, normalizedAnswer{
+				ID:      answer.ID,
+				Text:    text,
+				Correct: answer.Correct,
+				Match:   e.text(answer.MatchTitle),
+			})
+		}
+		ni.Entries = append(ni.Entries, entry)
+	}
+	return ni
+}
+
+// text returns the HTML and plain text of a field, or nil if it is empty.
+func (e *NormalizedExporter) text(html string) *normalizedText {
+	if html == "" {
+		return nil
+	}
+	return &normalizedText{HTML: html, Text: e.htmlCleaner.CleanHTML(html)}
+}
+
+// card converts a flashcard side, or returns nil if there is none.
+func (e *NormalizedExporter) card(pkg *models.SourcePackage, side *models.CardSide) *normalizedCard {
+	if side == nil {
+		return nil
+	}
+	return &normalizedCard{
+		Description: e.text(side.Description),
+		Media:       normalizeMedia(pkg, side.Media),
+	}
+}
+
+// normalizeMedia resolves the URLs of an element's image and video, or
+// returns nil if it has neither. Videos without an original URL fall back to
+// their streaming URL.
+func normalizeMedia(pkg *models.SourcePackage, media *models.Media) *normalizedMedia {
+	if media == nil || (media.Image == nil && media.Video == nil) {
+		return nil
+	}
+
+	nm := &normalizedMedia{}
+	if image := media.Image; image != nil {
+		nm.Image = &normalizedImage{
+			URL:         image.OriginalURL,
+			Key:         image.Key,
+			PackagePath: packagePath(pkg, image.Key, image.OriginalURL),
+			Width:       image.Width,
+			Height:      image.Height,
+		}
+	}
+	if video := media.Video; video != nil {
+		url := video.OriginalURL
+		if url == "" {
+			url = video.URL
+		}
+		nm.Video = &normalizedVideo{
+			URL:         url,
+			Key:         video.Key,
+			PackagePath: packagePath(pkg, video.Key, url),
+			Type:        video.Type,
+			Poster:      video.Poster,
+			Duration:    video.Duration,
+		}
+	}
+	return nm
+}
+
+// packagePath returns the path of a media file inside the source package,
+// trying each key in turn, or an empty string if it is not bundled.
+func packagePath(pkg *models.SourcePackage, keys ...string) string {
+	for _, key := range keys {
+		if name, ok := services.ResolvePackageMedia(pkg, key); ok {
+			return name
+		}
+	}
+	return ""
+}
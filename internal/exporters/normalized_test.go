@@ -0,0 +1,356 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// normalizedSchemaPath is the published JSON Schema, relative to this package.
+const normalizedSchemaPath = "../../schemas/course.v1.schema.json"
+
+// createTestCourseForNormalized returns a course that sets every field of the
+// normalized schema, with lessons before and after a section header.
+func createTestCourseForNormalized() *models.Course {
+	return &models.Course{
+		ShareID: "share-1",
+		Author:  "Ada",
+		Package: &models.SourcePackage{Media: map[string]string{"leaf.jpg": "scormcontent/assets/leaf.jpg"}},
+		Course: models.CourseInfo{
+			ID:             "course-1",
+			Title:          "Biology",
+			Description:    "<p>All about <em>cells</em></p>",
+			Color:          "#336699",
+			NavigationMode: "free",
+			CoverImage:     &models.Media{Image: &models.ImageMedia{Key: "leaf.jpg", OriginalURL: "https://example.com/leaf.jpg", Width: 800, Height: 600}},
+			Lessons: []models.Lesson{
+				{ID: "l1", Title: "Welcome", Description: "<p>Start here</p>", Position: 3.5, Items: []models.Item{
+					{ID: "i1", Type: "text", Variant: "paragraph", Items: []models.SubItem{{
+						ID: "s1", Heading: "<h2>Cells</h2>", Paragraph: "<p>Cells &amp; more</p>",
+					}}},
+					{ID: "i2", Type: "list", Variant: "numbered", Items: []models.SubItem{{Paragraph: "<p>One</p>"}}},
+				}},
+				{ID: "sec1", Title: "Part one", Type: "section"},
+				{ID: "l2", Title: "Quiz", Items: []models.Item{
+					{ID: "i3", Type: "knowledgeCheck", Variant: "matching", Items: []models.SubItem{{
+						Title:    "<p>Match</p>",
+						Answers:  []models.Answer{{ID: "a1", Title: "Pi", MatchTitle: "π"}, {ID: "a2", Correct: true}},
+						Feedback: "<p>Well done</p>",
+					}}},
+					{ID: "i4", Type: "multimedia", Media: &models.Media{Video: &models.VideoMedia{
+						Key: "v1", URL: "https://example.com/stream.m3u8", Type: "mp4", Poster: "https://example.com/poster.jpg", Duration: 42,
+					}}, Items: []models.SubItem{{Caption: "<p>Watch</p>", Media: &models.Media{Image: &models.ImageMedia{OriginalURL: "https://example.com/a.png"}}}}},
+					{ID: "i5", Type: "flashcard", Items: []models.SubItem{{
+						Front: &models.CardSide{Description: "<p>Front</p>"},
+						Back:  &models.CardSide{Media: &models.Media{Image: &models.ImageMedia{OriginalURL: "https://example.com/b.png"}}},
+					}}},
+					{ID: "i6", Type: "divider"},
+				}},
+				{ID: "sec2", Title: "Empty part", Type: "section"},
+			},
+		},
+	}
+}
+
+// exportNormalized exports a course in the given format and returns the output.
+func exportNormalized(t *testing.T, course *models.Course, format string) []byte {
+	t.Helper()
+	exporter := NewNormalizedExporter(services.NewHTMLCleaner(), format)
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// TestNormalizedExporter_SupportedFormat tests that the format follows the
+// requested encoding.
+func TestNormalizedExporter_SupportedFormat(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{FormatJSON, "json"},
+		{FormatYAML, "yaml"},
+		{"", "json"},
+	}
+
+	for _, tt := range tests {
+		exporter := NewNormalizedExporter(services.NewHTMLCleaner(), tt.format)
+		if got := exporter.SupportedFormat(); got != tt.expected {
+			t.Errorf("Expected format '%s' for '%s', got '%s'", tt.expected, tt.format, got)
+		}
+	}
+}
+
+// TestNormalizedExporter_JSON tests section grouping, lesson numbering,
+// cleaned text and resolved media in the JSON output.
+func TestNormalizedExporter_JSON(t *testing.T) {
+	output := exportNormalized(t, createTestCourseForNormalized(), FormatJSON)
+
+	var doc normalizedDocument
+	if err := json.Unmarshal(output, &doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if doc.SchemaVersion != 1 {
+		t.Errorf("Expected schema version 1, got %d", doc.SchemaVersion)
+	}
+
+	course := doc.Course
+	if course.LessonCount != 2 || len(course.Sections) != 3 {
+		t.Fatalf("Expected 2 lessons in 3 sections, got %d in %d", course.LessonCount, len(course.Sections))
+	}
+	if course.Sections[0].ID != "" || course.Sections[1].Title != "Part one" || len(course.Sections[2].Lessons) != 0 {
+		t.Errorf("Unexpected sections: %+v", course.Sections)
+	}
+	if course.Description.Text != "All about cells" || course.Description.HTML != "<p>All about <em>cells</em></p>" {
+		t.Errorf("Unexpected description: %+v", course.Description)
+	}
+	if course.CoverImage.Image.PackagePath != "scormcontent/assets/leaf.jpg" {
+		t.Errorf("Expected the cover image to resolve to the package, got %+v", course.CoverImage.Image)
+	}
+
+	quiz := course.Sections[1].Lessons[0]
+	if quiz.Number != 2 || quiz.Title != "Quiz" {
+		t.Errorf("Expected lesson 2 'Quiz', got %d '%s'", quiz.Number, quiz.Title)
+	}
+	matching := quiz.Items[0]
+	if matching.QuestionType != models.QuestionMatching || matching.Entries[0].Answers[0].Match.Text != "π" ||
+		matching.Entries[0].Answers[1].Text == nil || !matching.Entries[0].Answers[1].Correct {
+		t.Errorf("Unexpected matching question: %+v", matching)
+	}
+	if video := quiz.Items[1].Media.Video; video.URL != "https://example.com/stream.m3u8" {
+		t.Errorf("Expected the video to fall back to its streaming URL, got %s", video.URL)
+	}
+	if entries := quiz.Items[3].Entries; entries == nil || len(entries) != 0 {
+		t.Errorf("Expected an empty entries list, got %v", entries)
+	}
+
+	text := course.Sections[0].Lessons[0].Items[0].Entries[0].Paragraph
+	if text.Text != "Cells & more" || text.HTML != "<p>Cells &amp; more</p>" {
+		t.Errorf("Unexpected paragraph: %+v", text)
+	}
+	if !bytes.Contains(output, []byte(`"html": "<p>Cells &amp; more</p>"`)) {
+		t.Error("Expected HTML to be written without JSON escapes")
+	}
+}
+
+// TestNormalizedExporter_KeyOrder tests that keys are written in schema order
+// and that repeated exports are identical.
+func TestNormalizedExporter_KeyOrder(t *testing.T) {
+	course := createTestCourseForNormalized()
+	first := exportNormalized(t, course, FormatJSON)
+	if second := exportNormalized(t, course, FormatJSON); !bytes.Equal(first, second) {
+		t.Error("Expected repeated exports to be identical")
+	}
+
+	expectedOrder := []string{`"schemaVersion"`, `"course"`, `"id"`, `"shareId"`, `"title"`, `"author"`, `"description"`,
+		`"color"`, `"navigationMode"`, `"coverImage"`, `"lessonCount"`, `"sections"`}
+	last := -1
+	for _, key := range expectedOrder {
+		idx := bytes.Index(first, []byte(key))
+		if idx < last {
+			t.Errorf("Expected %s after the previous key", key)
+		}
+		last = idx
+	}
+}
+
+// TestNormalizedExporter_Schema tests that the output validates against the
+// published JSON Schema, including every optional field.
+func TestNormalizedExporter_Schema(t *testing.T) {
+	data, err := os.ReadFile(normalizedSchemaPath)
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+
+	courses := map[string]*models.Course{
+		"full":  createTestCourseForNormalized(),
+		"empty": {},
+	}
+	for name, course := range courses {
+		t.Run(name, func(t *testing.T) {
+			var doc any
+			if err := json.Unmarshal(exportNormalized(t, course, FormatJSON), &doc); err != nil {
+				t.Fatalf("Output is not valid JSON: %v", err)
+			}
+			for _, issue := range validateSchema(schema, schema, doc, "$") {
+				t.Error(issue)
+			}
+		})
+	}
+}
+
+// validateSchema checks value against the subset of JSON Schema the published
+// schema uses and returns the violations.
+func validateSchema(root, schema map[string]any, value any, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		defs, _ := root["$defs"].(map[string]any)
+		def, ok := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: unresolved $ref %s", path, ref)}
+		}
+		return validateSchema(root, def, value, path)
+	}
+
+	var issues []string
+	if c, ok := schema["const"]; ok && c != value {
+		issues = append(issues, fmt.Sprintf("%s: expected %v, got %v", path, c, value))
+	}
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, value) {
+		issues = append(issues, fmt.Sprintf("%s: %v is not one of %v", path, value, enum))
+	}
+
+	switch schema["type"] {
+	case "string":
+		if _, ok := value.(string); !ok {
+			issues = append(issues, fmt.Sprintf("%s: expected a string, got %T", path, value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			issues = append(issues, fmt.Sprintf("%s: expected a boolean, got %T", path, value))
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != float64(int(n)) {
+			issues = append(issues, fmt.Sprintf("%s: expected an integer, got %v", path, value))
+		} else if minimum, ok := schema["minimum"].(float64); ok && n < minimum {
+			issues = append(issues, fmt.Sprintf("%s: %v is below %v", path, n, minimum))
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return append(issues, fmt.Sprintf("%s: expected an array, got %T", path, value))
+		}
+		itemSchema, _ := schema["items"].(map[string]any)
+		for i, item := range items {
+			issues = append(issues, validateSchema(root, itemSchema, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return append(issues, fmt.Sprintf("%s: expected an object, got %T", path, value))
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, key := range required {
+			if _, ok := obj[key.(string)]; !ok {
+				issues = append(issues, fmt.Sprintf("%s: missing required %s", path, key))
+			}
+		}
+		for key, v := range obj {
+			propSchema, ok := properties[key].(map[string]any)
+			if !ok {
+				issues = append(issues, fmt.Sprintf("%s: unexpected property %s", path, key))
+				continue
+			}
+			issues = append(issues, validateSchema(root, propSchema, v, path+"."+key)...)
+		}
+	}
+	return issues
+}
+
+// TestNormalizedExporter_YAML tests that the YAML output holds the same
+// document as the JSON output.
+func TestNormalizedExporter_YAML(t *testing.T) {
+	output := string(exportNormalized(t, createTestCourseForNormalized(), FormatYAML))
+
+	expected := []string{
+		"schemaVersion: 1\ncourse:\n  id: course-1\n",
+		"  color: \"#336699\"\n",
+		"  description:\n    html: \"<p>All about <em>cells</em></p>\"\n    text: All about cells\n",
+		"  lessonCount: 2\n  sections:\n    - lessons:\n        - number: 1\n          id: l1\n",
+		"    - id: sec2\n      title: Empty part\n      lessons: []\n",
+		"                  answers:\n                    - id: a1\n",
+		"                      correct: false\n                      match:\n",
+		"                        text: π\n",
+		"          entries: []\n",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(output, exp) {
+			t.Errorf("Expected output to contain %q\ngot:\n%s", exp, output)
+		}
+	}
+}
+
+// TestJSONToYAML tests the conversion of scalars, nesting and empty collections.
+func TestJSONToYAML(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"a": 1, "b": true, "c": null}`, "a: 1\nb: true\nc: null\n"},
+		{`{"z": "last", "a": "first"}`, "z: last\na: first\n"},
+		{`{"list": [1, [2, 3], {"k": "v", "w": []}], "empty": {}}`, "list:\n  - 1\n  - - 2\n    - 3\n  - k: v\n    w: []\nempty: {}\n"},
+		{`[]`, "[]\n"},
+		{`"text"`, "text\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := jsonToYAML(&buf, []byte(tt.input)); err != nil {
+			t.Fatalf("jsonToYAML failed for %s: %v", tt.input, err)
+		}
+		if got := buf.String(); got != tt.expected {
+			t.Errorf("Expected %q for %s, got %q", tt.expected, tt.input, got)
+		}
+	}
+}
+
+// TestYAMLString tests when strings are quoted.
+func TestYAMLString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain text", "plain text"},
+		{"https://example.com/a.png", `"https://example.com/a.png"`},
+		{"", `""`},
+		{"yes", `"yes"`},
+		{"No", `"No"`},
+		{"123", `"123"`},
+		{"a: b", `"a: b"`},
+		{"two\nlines", `"two\nlines"`},
+		{"<p>x & y</p>", `"<p>x & y</p>"`},
+		{"trailing ", `"trailing "`},
+		{"a #comment", `"a #comment"`},
+		{"C#", "C#"},
+		{"Straße Ω", "Straße Ω"},
+	}
+
+	for _, tt := range tests {
+		if got := yamlString(tt.input); got != tt.expected {
+			t.Errorf("Expected %s for %q, got %s", tt.expected, tt.input, got)
+		}
+	}
+}
+
+// TestNormalizedExporter_Export tests that Export writes the file.
+func TestNormalizedExporter_Export(t *testing.T) {
+	exporter := NewNormalizedExporter(services.NewHTMLCleaner(), FormatYAML)
+	outputPath := filepath.Join(t.TempDir(), "course.yaml")
+
+	if err := exporter.Export(createTestCourseForNormalized(), outputPath); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !strings.HasPrefix(string(content), "schemaVersion: 1\n") {
+		t.Errorf("Unexpected file start: %q", string(content[:min(len(content), 40)]))
+	}
+}
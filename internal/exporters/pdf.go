@@ -0,0 +1,414 @@
+package exporters
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/kjanat/articulate-parser/internal/interfaces"
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// Embedded DejaVu Sans Condensed fonts. They cover Latin, Greek, Cyrillic and
+// many other scripts, so courses render the same regardless of the fonts
+// installed on the system. See fonts/LICENSE for the font license.
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	pdfFontRegular []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	pdfFontBold []byte
+	//go:embed fonts/DejaVuSansCondensed-Oblique.ttf
+	pdfFontItalic []byte
+)
+
+// pdfFontFamily is the name the embedded fonts are registered under.
+const pdfFontFamily = "DejaVu"
+
+// Font sizes (in points) and layout measures (in millimetres) for PDF documents.
+const (
+	pdfTitleSize   = 20.0 // Course title
+	pdfSectionSize = 17.0 // Section heading
+	pdfLessonSize  = 15.0 // Lesson heading
+	pdfItemSize    = 12.0 // Item heading
+	pdfBodySize    = 10.5 // Body text
+	pdfFooterSize  = 8.0  // Page numbers
+
+	pdfMargin     = 20.0 // Page margin
+	pdfLineHeight = 5.5  // Height of a body text line
+	pdfIndent     = 6.0  // Indentation of sub-items, list entries and answers
+)
+
+// PDFExporter implements the Exporter interface for PDF format.
+// It renders Articulate Rise course data into a paginated PDF document
+// using the pure-Go gofpdf package, with page numbers and a bookmark outline.
+type PDFExporter struct {
+	// htmlCleaner is used to convert HTML content to plain text
+	htmlCleaner *services.HTMLCleaner
+}
+
+// NewPDFExporter creates a new PDFExporter instance.
+// It takes an HTMLCleaner to handle HTML content conversion.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//
+// Returns:
+//   - An implementation of the Exporter interface for PDF format
+func NewPDFExporter(htmlCleaner *services.HTMLCleaner) interfaces.Exporter {
+	return &PDFExporter{
+		htmlCleaner: htmlCleaner,
+	}
+}
+
+// Export exports the course to a PDF file.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - outputPath: The file path where the PDF content will be written
+//
+// Returns:
+//   - An error if rendering or saving the document fails
+func (e *PDFExporter) Export(course *models.Course, outputPath string) error {
+	// #nosec G304 - Output path is provided by user via CLI argument, which is expected behavior
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	// Close errors are logged but not fatal, see DocxExporter.Export.
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to close output file: %v\n", err)
+		}
+	}()
+
+	return e.ExportTo(course, file)
+}
+
+// ExportTo exports the course as a PDF document written to w.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - w: The writer the PDF content will be written to
+//
+// Returns:
+//   - An error if rendering or writing the document fails
+func (e *PDFExporter) ExportTo(course *models.Course, w io.Writer) error {
+	pdf := e.buildDocument(course)
+
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("failed to write PDF document: %w", err)
+	}
+	return nil
+}
+
+// buildDocument renders the course into a PDF document in memory. Rendering
+// errors are kept on the document by gofpdf and reported by Output.
+//
+// Parameters:
+//   - course: The course data model to export
+//
+// Returns:
+//   - The rendered PDF document
+func (e *PDFExporter) buildDocument(course *models.Course) *gofpdf.Fpdf {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "", pdfFontRegular)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "B", pdfFontBold)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "I", pdfFontItalic)
+
+	pdf.SetTitle(course.Course.Title, true)
+	pdf.SetCreator("articulate-parser", true)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+
+	// Page numbers; {nb} is replaced with the total page count on output
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin / 2)
+		pdf.SetFont(pdfFontFamily, "", pdfFooterSize)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	pdf.AddPage()
+
+	// Add title and description
+	e.writeHeading(pdf, course.Course.Title, pdfTitleSize)
+	if course.Course.Description != "" {
+		e.writeText(pdf, e.htmlCleaner.CleanHTML(course.Course.Description), 0, "")
+	}
+
+	// Add each lesson. Sections are top-level bookmarks and the lessons
+	// following them are nested below; without sections lessons are top-level.
+	lessonCounter := 0
+	lessonLevel := 0
+	for _, lesson := range course.Course.Lessons {
+		if lesson.Type == lessonTypeSection {
+			pdf.Ln(pdfLineHeight)
+			pdf.Bookmark(lesson.Title, 0, -1)
+			e.writeHeading(pdf, lesson.Title, pdfSectionSize)
+			lessonLevel = 1
+			continue
+		}
+
+		lessonCounter++
+		e.exportLesson(pdf, &lesson, lessonCounter, lessonLevel)
+	}
+
+	return pdf
+}
+
+// exportLesson adds a numbered lesson with its description and items to the
+// document and a bookmark pointing to it.
+//
+// Parameters:
+//   - pdf: The PDF document being created
+//   - lesson: The lesson data model to export
+//   - number: The lesson number, not counting sections
+//   - level: The outline level of the lesson bookmark
+func (e *PDFExporter) exportLesson(pdf *gofpdf.Fpdf, lesson *models.Lesson, number, level int) {
+	title := fmt.Sprintf("Lesson %d: %s", number, lesson.Title)
+
+	pdf.Ln(pdfLineHeight / 2)
+	pdf.Bookmark(title, level, -1)
+	e.writeHeading(pdf, title, pdfLessonSize)
+
+	if lesson.Description != "" {
+		e.writeText(pdf, e.htmlCleaner.CleanHTML(lesson.Description), 0, "")
+	}
+
+	for _, item := range lesson.Items {
+		e.exportItem(pdf, &item)
+	}
+}
+
+// exportItem adds an item to the document according to its type.
+//
+// Parameters:
+//   - pdf: The PDF document being created
+//   - item: The item data model to export
+func (e *PDFExporter) exportItem(pdf *gofpdf.Fpdf, item *models.Item) {
+	switch strings.ToLower(item.Type) {
+	case itemTypeText:
+		e.exportTextItem(pdf, item)
+	case itemTypeList:
+		e.exportListItem(pdf, item)
+	case itemTypeMultimedia, itemTypeImage:
+		e.exportMediaItem(pdf, item)
+	case itemTypeKnowledgeCheck:
+		e.exportKnowledgeCheckItem(pdf, item)
+	case itemTypeDivider:
+		e.exportDivider(pdf)
+	default:
+		e.exportGenericItem(pdf, item)
+	}
+}
+
+// exportTextItem adds the headings and paragraphs of a text item.
+//
+// Parameters:
+//   - pdf: The PDF document being created
+//   - item: The text item to export
+func (e *PDFExporter) exportTextItem(pdf *gofpdf.Fpdf, item *models.Item) {
+	for _, subItem := range item.Items {
+		if heading := e.htmlCleaner.CleanHTML(subItem.Heading); heading != "" {
+			e.writeHeading(pdf, heading, pdfItemSize)
+		}
+		if paragraph := e.htmlCleaner.CleanHTML(subItem.Paragraph); paragraph != "" {
+			e.writeText(pdf, paragraph, 0, "")
+		}
+	}
+}
+
+// exportListItem adds the entries of a list item with a marker matching the
+// list style: numbers, bullets or empty checkboxes.
+//
+// Parameters:
+//   - pdf: The PDF document being created
+//   - item: The list item to export
+func (e *PDFExporter) exportListItem(pdf *gofpdf.Fpdf, item *models.Item) {
+	style := services.ItemDetails(item).List.Style
+
+	number := 0
+	for _, subItem := range item.Items {
+		paragraph := e.htmlCleaner.CleanHTML(subItem.Paragraph)
+		if paragraph == "" {
+			continue
+		}
+		number++
+
+		var marker string
+		switch style {
+		case models.ListStyleNumbered:
+			marker = fmt.Sprintf("%d. ", number)
+		case models.ListStyleCheckboxes:
+			marker = "☐ "
+		default:
+			marker = "• "
+		}
+		e.writeText(pdf, marker+paragraph, pdfIndent, "")
+	}
+}
+
+// exportMediaItem adds the media references of a multimedia or image item,
+// each followed by its caption in italics.
+//
+// Parameters:
+//   - pdf: The PDF document being created
+//   - item: The multimedia or image item to export
+func (e *PDFExporter) exportMediaItem(pdf *gofpdf.Fpdf, item *models.Item) {
+	for _, subItem := range item.Items {
+		if media := subItem.Media; media != nil {
+			if media.Video != nil && media.Video.OriginalURL != "" {
+				e.writeText(pdf, "Video: "+media.Video.OriginalURL, 0, "")
+			}
+			if media.Image != nil && media.Image.OriginalURL != "" {
+				e.writeText(pdf, "Image: "+media.Image.OriginalURL, 0, "")
+			}
+		}
+		if caption := e.htmlCleaner.CleanHTML(subItem.Caption); caption != "" {
+			e.writeText(pdf, caption, pdfIndent, "I")
+		}
+	}
+}
+
+// exportKnowledgeCheckItem adds the questions of a knowledge check with their
+// answers and feedback. Answers are presented according to the question type.
+//
+// Parameters:
+//   - pdf: The PDF document being created
+//   - item: The knowledge check item to export
+func (e *PDFExporter) exportKnowledgeCheckItem(pdf *gofpdf.Fpdf, item *models.Item) {
+	e.writeHeading(pdf, "Knowledge Check", pdfItemSize)
+	questionType := services.ItemDetails(item).KnowledgeCheck.QuestionType
+
+	for _, subItem := range item.Items {
+		if title := e.htmlCleaner.CleanHTML(subItem.Title); title != "" {
+			e.writeText(pdf, title, 0, "B")
+		}
+		if len(subItem.Answers) > 0 {
+			e.exportAnswers(pdf, subItem.Answers, questionType)
+		}
+		if feedback := e.htmlCleaner.CleanHTML(subItem.Feedback); feedback != "" {
+			e.writeText(pdf, "Feedback: "+feedback, pdfIndent, "I")
+		}
+	}
+}
+
+// exportAnswers adds the answers of a question. Fill-in-the-blank questions
+// list their accepted answers and matching questions their pairs; other
+// questions list numbered choices with the correct ones marked.
+//
+// Parameters:
+//   - pdf: The PDF document being created
+//   - answers: The answers of the question
+//   - questionType: The decoded question type
+func (e *PDFExporter) exportAnswers(pdf *gofpdf.Fpdf, answers []models.Answer, questionType string) {
+	switch questionType {
+	case models.QuestionFillIn:
+		e.writeText(pdf, "Accepted answers:", pdfIndent, "I")
+	case models.QuestionMatching:
+		e.writeText(pdf, "Matches:", pdfIndent, "I")
+	case models.QuestionMultipleChoice:
+		e.writeText(pdf, "Select all that apply.", pdfIndent, "I")
+	}
+
+	for i, answer := range answers {
+		cleanAnswer := e.htmlCleaner.CleanHTML(answer.Title)
+
+		switch questionType {
+		case models.QuestionFillIn:
+			e.writeText(pdf, "• "+cleanAnswer, pdfIndent*2, "")
+		case models.QuestionMatching:
+			match := e.htmlCleaner.CleanHTML(answer.MatchTitle)
+			e.writeText(pdf, fmt.Sprintf("%d. %s → %s", i+1, cleanAnswer, match), pdfIndent, "")
+		default:
+			text := fmt.Sprintf("%d. %s", i+1, cleanAnswer)
+			if answer.Correct {
+				e.writeText(pdf, text+"  ✓", pdfIndent, "B")
+			} else {
+				e.writeText(pdf, text, pdfIndent, "")
+			}
+		}
+	}
+}
+
+// exportDivider adds a horizontal rule across the text width.
+//
+// Parameters:
+//   - pdf: The PDF document being created
+func (e *PDFExporter) exportDivider(pdf *gofpdf.Fpdf) {
+	pageWidth, _ := pdf.GetPageSize()
+	y := pdf.GetY() + pdfLineHeight/2
+
+	pdf.SetDrawColor(180, 180, 180)
+	pdf.Line(pdfMargin, y, pageWidth-pdfMargin, y)
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.Ln(pdfLineHeight)
+}
+
+// exportGenericItem adds the titles and paragraphs of interactive and
+// unknown items under a heading naming the item type.
+//
+// Parameters:
+//   - pdf: The PDF document being created
+//   - item: The item to export
+func (e *PDFExporter) exportGenericItem(pdf *gofpdf.Fpdf, item *models.Item) {
+	if len(item.Items) == 0 {
+		return
+	}
+
+	if item.Type != "" {
+		caser := cases.Title(language.English)
+		e.writeHeading(pdf, caser.String(item.Type), pdfItemSize)
+	}
+	for _, subItem := range item.Items {
+		if title := e.htmlCleaner.CleanHTML(subItem.Title); title != "" {
+			e.writeText(pdf, title, 0, "B")
+		}
+		if paragraph := e.htmlCleaner.CleanHTML(subItem.Paragraph); paragraph != "" {
+			e.writeText(pdf, paragraph, 0, "")
+		}
+	}
+}
+
+// writeHeading adds a bold heading of the given size, followed by a small gap.
+//
+// Parameters:
+//   - pdf: The PDF document being created
+//   - text: The heading text
+//   - size: The font size in points
+func (e *PDFExporter) writeHeading(pdf *gofpdf.Fpdf, text string, size float64) {
+	pdf.SetFont(pdfFontFamily, "B", size)
+	pdf.MultiCell(0, size*0.5, text, "", "L", false)
+	pdf.Ln(pdfLineHeight / 2)
+}
+
+// writeText adds a block of body text that wraps within the text width.
+//
+// Parameters:
+//   - pdf: The PDF document being created
+//   - text: The text to write; newlines start new lines
+//   - indent: The indentation from the left margin in millimetres
+//   - style: The font style, "" for regular, "B" for bold or "I" for italic
+func (e *PDFExporter) writeText(pdf *gofpdf.Fpdf, text string, indent float64, style string) {
+	pdf.SetFont(pdfFontFamily, style, pdfBodySize)
+	pdf.SetX(pdfMargin + indent)
+	pdf.MultiCell(0, pdfLineHeight, text, "", "L", false)
+	pdf.Ln(1)
+}
+
+// SupportedFormat returns the format name this exporter supports.
+//
+// Returns:
+//   - A string representing the supported format ("pdf")
+func (e *PDFExporter) SupportedFormat() string {
+	return FormatPDF
+}
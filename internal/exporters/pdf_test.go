@@ -0,0 +1,235 @@
+package exporters
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// TestNewPDFExporter tests the NewPDFExporter constructor.
+func TestNewPDFExporter(t *testing.T) {
+	exporter := NewPDFExporter(services.NewHTMLCleaner())
+
+	pdfExporter, ok := exporter.(*PDFExporter)
+	if !ok {
+		t.Fatal("NewPDFExporter() returned wrong type")
+	}
+	if pdfExporter.htmlCleaner == nil {
+		t.Error("htmlCleaner should not be nil")
+	}
+}
+
+// TestPDFExporter_SupportedFormat tests the SupportedFormat method.
+func TestPDFExporter_SupportedFormat(t *testing.T) {
+	exporter := NewPDFExporter(services.NewHTMLCleaner())
+
+	if result := exporter.SupportedFormat(); result != "pdf" {
+		t.Errorf("Expected format 'pdf', got '%s'", result)
+	}
+}
+
+// TestPDFExporter_Export tests that Export writes a PDF file with embedded
+// fonts and a bookmark outline.
+func TestPDFExporter_Export(t *testing.T) {
+	exporter := NewPDFExporter(services.NewHTMLCleaner())
+	outputPath := filepath.Join(t.TempDir(), "course.pdf")
+
+	if err := exporter.Export(createTestCourseForDocx(), outputPath); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte("%PDF-")) {
+		t.Fatalf("Expected output to start with a PDF header, got %q", content[:min(len(content), 8)])
+	}
+	for _, expected := range []string{"/Outlines", "/FontFile2"} {
+		if !bytes.Contains(content, []byte(expected)) {
+			t.Errorf("Expected PDF to contain '%s'", expected)
+		}
+	}
+}
+
+// TestPDFExporter_Export_InvalidPath tests Export with a path that cannot be created.
+func TestPDFExporter_Export_InvalidPath(t *testing.T) {
+	exporter := NewPDFExporter(services.NewHTMLCleaner())
+	outputPath := filepath.Join(t.TempDir(), "missing", "course.pdf")
+
+	if err := exporter.Export(createTestCourseForDocx(), outputPath); err == nil {
+		t.Error("Expected error for invalid output path")
+	}
+}
+
+// TestPDFExporter_ExportTo tests that ExportTo writes a complete PDF document.
+func TestPDFExporter_ExportTo(t *testing.T) {
+	exporter := NewPDFExporter(services.NewHTMLCleaner())
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(createTestCourseForDocx(), &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) || !bytes.HasSuffix(bytes.TrimSpace(buf.Bytes()), []byte("%%EOF")) {
+		t.Error("Expected a complete PDF document")
+	}
+}
+
+// TestPDFExporter_Content tests the rendered text, page numbers and outline.
+func TestPDFExporter_Content(t *testing.T) {
+	course := &models.Course{
+		Course: models.CourseInfo{
+			Title:       "PDF Course",
+			Description: "<p>About this course</p>",
+			Lessons: []models.Lesson{
+				{Title: "Getting Started", Type: "section"},
+				{
+					Title: "First Steps",
+					Items: []models.Item{
+						{Type: "text", Items: []models.SubItem{{Heading: "<h2>Welcome</h2>", Paragraph: "<p>Hello there</p>"}}},
+						{Type: "list", Variant: "numbered", Items: []models.SubItem{{Paragraph: "<p>Open the app</p>"}}},
+						{Type: "list", Variant: "checkboxes", Items: []models.SubItem{{Paragraph: "<p>Install</p>"}}},
+						{
+							Type: "image",
+							Items: []models.SubItem{{
+								Caption: "<p>A diagram</p>",
+								Media:   &models.Media{Image: &models.ImageMedia{OriginalURL: "diagram.png"}},
+							}},
+						},
+					},
+				},
+				{Title: "Wrap Up", Type: "section"},
+				{
+					Title: "Quiz",
+					Items: []models.Item{{
+						Type:    "knowledgeCheck",
+						Variant: "multipleChoice",
+						Items: []models.SubItem{{
+							Title: "<p>Pick one</p>",
+							Answers: []models.Answer{
+								{Title: "Wrong"},
+								{Title: "Right", Correct: true},
+							},
+							Feedback: "<p>Well done</p>",
+						}},
+					}},
+				},
+			},
+		},
+	}
+
+	content := renderUncompressedPDF(t, course)
+
+	for _, expected := range []string{
+		"PDF Course",
+		"About this course",
+		"Getting Started",
+		"Lesson 1: First Steps",
+		"Lesson 2: Quiz",
+		"Welcome",
+		"1. Open the app",
+		"☐ Install",
+		"Image: diagram.png",
+		"A diagram",
+		"Pick one",
+		"2. Right  ✓",
+		"1. Wrong",
+		"Feedback: Well done",
+		"Page 1 of 1",
+	} {
+		if !bytes.Contains(content, pdfText(expected)) {
+			t.Errorf("Expected PDF to contain '%s'", expected)
+		}
+	}
+
+	// Two sections and two lessons make four bookmarks
+	if count := bytes.Count(content, []byte("/Dest [")); count != 4 {
+		t.Errorf("Expected 4 bookmarks, got %d", count)
+	}
+}
+
+// TestPDFExporter_NonLatinScripts tests that text in non-Latin scripts is
+// rendered with the embedded fonts.
+func TestPDFExporter_NonLatinScripts(t *testing.T) {
+	course := &models.Course{
+		Course: models.CourseInfo{
+			Title: "Курс по безопасности",
+			Lessons: []models.Lesson{{
+				Title: "Εισαγωγή",
+				Items: []models.Item{{
+					Type:  "text",
+					Items: []models.SubItem{{Paragraph: "<p>Zażółć gęślą jaźń — Ελληνικά — Русский</p>"}},
+				}},
+			}},
+		},
+	}
+
+	content := renderUncompressedPDF(t, course)
+
+	for _, expected := range []string{"Курс по безопасности", "Lesson 1: Εισαγωγή", "Zażółć gęślą jaźń"} {
+		if !bytes.Contains(content, pdfText(expected)) {
+			t.Errorf("Expected PDF to contain '%s'", expected)
+		}
+	}
+}
+
+// TestPDFExporter_PageBreaks tests that long courses span several numbered pages.
+func TestPDFExporter_PageBreaks(t *testing.T) {
+	lessons := make([]models.Lesson, 30)
+	for i := range lessons {
+		lessons[i] = models.Lesson{
+			Title: "Lesson",
+			Items: []models.Item{{
+				Type:  "text",
+				Items: []models.SubItem{{Paragraph: "<p>Some text that takes up a line or two on the page.</p>"}},
+			}},
+		}
+	}
+	course := &models.Course{Course: models.CourseInfo{Title: "Long Course", Lessons: lessons}}
+
+	exporter := &PDFExporter{htmlCleaner: services.NewHTMLCleaner()}
+	pdf := exporter.buildDocument(course)
+	pages := pdf.PageCount()
+	if pages < 2 {
+		t.Fatalf("Expected several pages, got %d", pages)
+	}
+
+	content := renderUncompressedPDF(t, course)
+	if !bytes.Contains(content, pdfText("Page 2 of ")) {
+		t.Error("Expected page numbers on the second page")
+	}
+	if count := bytes.Count(content, []byte("/Dest [")); count != len(lessons) {
+		t.Errorf("Expected %d bookmarks, got %d", len(lessons), count)
+	}
+}
+
+// renderUncompressedPDF renders a course with uncompressed page content so
+// that tests can search the text.
+func renderUncompressedPDF(t *testing.T, course *models.Course) []byte {
+	t.Helper()
+
+	exporter := &PDFExporter{htmlCleaner: services.NewHTMLCleaner()}
+	pdf := exporter.buildDocument(course)
+	pdf.SetCompression(false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		t.Fatalf("Failed to render PDF: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// pdfText returns text as it appears in uncompressed PDF content written with
+// UTF-8 fonts: UTF-16BE code units.
+func pdfText(s string) []byte {
+	var b []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = append(b, byte(u>>8), byte(u))
+	}
+	return b
+}
@@ -0,0 +1,258 @@
+package exporters
+
+import (
+	"archive/zip"
+	_ "embed"
+	"fmt"
+	stdhtml "html"
+	"io"
+	"os"
+	"text/template"
+
+	"github.com/kjanat/articulate-parser/internal/interfaces"
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+//go:embed qti_templates.gotmpl
+var qtiTemplates string
+
+// Files of a QTI content package besides the items.
+const (
+	qtiManifest = "imsmanifest.xml"
+	qtiTest     = "assessment.xml"
+	qtiItemsDir = "items"
+)
+
+// QTI interactions the question types are mapped to.
+const (
+	qtiChoiceInteraction    = "choiceInteraction"
+	qtiMatchInteraction     = "matchInteraction"
+	qtiTextEntryInteraction = "textEntryInteraction"
+)
+
+// QTIExporter implements the Exporter interface for IMS QTI 2.1.
+// It writes every knowledge check question as an assessment item in a
+// content package, along with an assessment test that groups the items by
+// lesson.
+type QTIExporter struct {
+	// htmlCleaner is used to derive item titles from question text
+	htmlCleaner *services.HTMLCleaner
+	// tmpl renders the manifest, the assessment test and the items
+	tmpl *template.Template
+}
+
+// qtiItem is a question converted to an assessment item. Which of the
+// interaction fields are set depends on Interaction.
+type qtiItem struct {
+	ID          string
+	Href        string
+	Title       string
+	Interaction string
+	// Question is the question text as XHTML
+	Question string
+	// Feedback is the general feedback as XHTML, shown after answering
+	Feedback string
+	// Choices and MaxChoices describe a choice interaction; MaxChoices is
+	// 1 for single choice and 0 for any number of choices
+	Choices    []qtiChoice
+	MaxChoices int
+	// Sources and Targets are the two sets of a match interaction
+	Sources []qtiChoice
+	Targets []qtiChoice
+	// Correct holds the identifiers of the correct choices or, for match
+	// interactions, the correct "source target" pairs
+	Correct []string
+	// Accepted holds the accepted answers of a text entry interaction as
+	// plain text, which responses are compared with
+	Accepted []string
+}
+
+// qtiChoice is a choice of a choice interaction or a match set.
+type qtiChoice struct {
+	ID string
+	// Text is the choice content as XHTML
+	Text     string
+	MatchMax int
+}
+
+// qtiSection groups the items of a lesson in the assessment test.
+type qtiSection struct {
+	ID    string
+	Title string
+	Items []qtiItem
+}
+
+// qtiPackageData is the data passed to the manifest and test templates.
+type qtiPackageData struct {
+	Identifier string
+	Title      string
+	Sections   []qtiSection
+	Items      []qtiItem
+}
+
+// NewQTIExporter creates a new QTIExporter instance.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//
+// Returns:
+//   - An implementation of the Exporter interface for QTI 2.1 packages
+func NewQTIExporter(htmlCleaner *services.HTMLCleaner) interfaces.Exporter {
+	tmpl := template.Must(template.New("qti").Funcs(template.FuncMap{
+		"esc": stdhtml.EscapeString,
+	}).Parse(qtiTemplates))
+
+	return &QTIExporter{
+		htmlCleaner: htmlCleaner,
+		tmpl:        tmpl,
+	}
+}
+
+// Export exports the course's questions to a QTI content package file.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - outputPath: The file path where the zip package will be written
+//
+// Returns:
+//   - An error if building or saving the package fails
+func (e *QTIExporter) Export(course *models.Course, outputPath string) error {
+	// #nosec G304 - Output path is provided by user via CLI argument, which is expected behavior
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	// Close errors are logged but not fatal, see DocxExporter.Export.
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to close output file: %v\n", err)
+		}
+	}()
+
+	return e.ExportTo(course, file)
+}
+
+// ExportTo exports the course's questions as a QTI content package written to w.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - w: The writer the zip package will be written to
+//
+// Returns:
+//   - An error if the course has no questions or writing the package fails
+func (e *QTIExporter) ExportTo(course *models.Course, w io.Writer) error {
+	data := qtiPackageData{
+		Identifier: "course-" + xmlID(scormCourseID(course)),
+		Title:      course.Course.Title,
+	}
+
+	for _, lesson := range quizLessons(course) {
+		section := qtiSection{
+			ID:    fmt.Sprintf("lesson-%03d", lesson.Number),
+			Title: fmt.Sprintf("Lesson %d: %s", lesson.Number, e.htmlCleaner.CleanHTML(lesson.Title)),
+		}
+		for _, question := range lesson.Questions {
+			qi := e.buildItem(len(data.Items)+1, question.Type, &question.SubItem)
+			section.Items = append(section.Items, qi)
+			data.Items = append(data.Items, qi)
+		}
+		data.Sections = append(data.Sections, section)
+	}
+	if len(data.Items) == 0 {
+		return errNoQuestions
+	}
+
+	// The manifest goes first, followed by the test and the items in order
+	zw := zip.NewWriter(w)
+	write := func(name, tmpl string, value any) error {
+		fw, err := zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if err := e.tmpl.ExecuteTemplate(fw, tmpl, value); err != nil {
+			return fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		return nil
+	}
+	if err := write(qtiManifest, "manifest", data); err != nil {
+		return err
+	}
+	if err := write(qtiTest, "test", data); err != nil {
+		return err
+	}
+	for _, qi := range data.Items {
+		if err := write(qi.Href, "item", qi); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write QTI package: %w", err)
+	}
+	return nil
+}
+
+// SupportedFormat returns the format name this exporter supports.
+//
+// Returns:
+//   - A string representing the supported format ("qti")
+func (e *QTIExporter) SupportedFormat() string {
+	return FormatQTI
+}
+
+// buildItem converts the question in subItem to the nth assessment item.
+// Single-choice and multiple-response questions become choice interactions,
+// matching questions match interactions and fill-in questions text entry
+// interactions. Choice questions with more than one correct answer are
+// multiple response, whatever their type says.
+func (e *QTIExporter) buildItem(n int, questionType string, subItem *models.SubItem) qtiItem {
+	qi := qtiItem{
+		ID:       fmt.Sprintf("item-%03d", n),
+		Title:    e.htmlCleaner.CleanHTML(subItem.Title),
+		Question: xhtmlFragment(subItem.Title),
+		Feedback: xhtmlFragment(subItem.Feedback),
+	}
+	qi.Href = qtiItemsDir + "/" + qi.ID + ".xml"
+	if qi.Title == "" {
+		qi.Title = fmt.Sprintf("Question %d", n)
+	}
+
+	switch questionType {
+	case models.QuestionFillIn:
+		qi.Interaction = qtiTextEntryInteraction
+		for _, answer := range subItem.Answers {
+			qi.Accepted = append(qi.Accepted, e.htmlCleaner.CleanHTML(answer.Title))
+		}
+	case models.QuestionMatching:
+		qi.Interaction = qtiMatchInteraction
+		// Answers sharing a match become one target that takes several sources
+		targets := make(map[string]int)
+		for i, answer := range subItem.Answers {
+			source := fmt.Sprintf("SOURCE_%d", i+1)
+			qi.Sources = append(qi.Sources, qtiChoice{ID: source, Text: xhtmlFragment(answer.Title), MatchMax: 1})
+
+			t, ok := targets[answer.MatchTitle]
+			if !ok {
+				t = len(qi.Targets)
+				targets[answer.MatchTitle] = t
+				qi.Targets = append(qi.Targets, qtiChoice{ID: fmt.Sprintf("TARGET_%d", t+1), Text: xhtmlFragment(answer.MatchTitle)})
+			}
+			qi.Targets[t].MatchMax++
+			qi.Correct = append(qi.Correct, source+" "+qi.Targets[t].ID)
+		}
+	default:
+		qi.Interaction = qtiChoiceInteraction
+		for i, answer := range subItem.Answers {
+			choice := qtiChoice{ID: fmt.Sprintf("CHOICE_%d", i+1), Text: xhtmlFragment(answer.Title)}
+			qi.Choices = append(qi.Choices, choice)
+			if answer.Correct {
+				qi.Correct = append(qi.Correct, choice.ID)
+			}
+		}
+		if questionType != models.QuestionMultipleChoice && len(qi.Correct) <= 1 {
+			qi.MaxChoices = 1
+		}
+	}
+
+	return qi
+}
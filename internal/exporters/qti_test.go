@@ -0,0 +1,324 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// qtiManifestDoc is the subset of imsmanifest.xml checked by the tests.
+type qtiManifestDoc struct {
+	Resources []struct {
+		Identifier   string `xml:"identifier,attr"`
+		Type         string `xml:"type,attr"`
+		Href         string `xml:"href,attr"`
+		Dependencies []struct {
+			Ref string `xml:"identifierref,attr"`
+		} `xml:"dependency"`
+	} `xml:"resources>resource"`
+}
+
+// qtiTestDoc is the subset of the assessment test checked by the tests.
+type qtiTestDoc struct {
+	Title    string `xml:"title,attr"`
+	Sections []struct {
+		Title string `xml:"title,attr"`
+		Refs  []struct {
+			Href string `xml:"href,attr"`
+		} `xml:"assessmentItemRef"`
+	} `xml:"testPart>assessmentSection"`
+}
+
+// qtiItemDoc is the subset of an assessment item checked by the tests.
+type qtiItemDoc struct {
+	XMLName  xml.Name
+	Title    string `xml:"title,attr"`
+	Response struct {
+		Cardinality string   `xml:"cardinality,attr"`
+		BaseType    string   `xml:"baseType,attr"`
+		Correct     []string `xml:"correctResponse>value"`
+		Mapping     []struct {
+			Key           string `xml:"mapKey,attr"`
+			CaseSensitive string `xml:"caseSensitive,attr"`
+		} `xml:"mapping>mapEntry"`
+	} `xml:"responseDeclaration"`
+	Body struct {
+		Choice *struct {
+			MaxChoices int `xml:"maxChoices,attr"`
+			Choices    []struct {
+				Content string `xml:",innerxml"`
+			} `xml:"simpleChoice"`
+		} `xml:"choiceInteraction"`
+		Match *struct {
+			Sets []struct {
+				Choices []struct {
+					ID       string `xml:"identifier,attr"`
+					MatchMax int    `xml:"matchMax,attr"`
+					Content  string `xml:",innerxml"`
+				} `xml:"simpleAssociableChoice"`
+			} `xml:"simpleMatchSet"`
+		} `xml:"matchInteraction"`
+		TextEntry *struct {
+			Response string `xml:"responseIdentifier,attr"`
+		} `xml:"p>textEntryInteraction"`
+	} `xml:"itemBody"`
+	Feedback *struct {
+		Outcome string `xml:"outcomeIdentifier,attr"`
+		Content string `xml:",innerxml"`
+	} `xml:"modalFeedback"`
+}
+
+// createTestCourseForQTI returns a course with one question of each type
+// spread over two lessons, and a lesson without questions.
+func createTestCourseForQTI() *models.Course {
+	return &models.Course{
+		Course: models.CourseInfo{
+			ID:    "quiz-course",
+			Title: "Capitals",
+			Lessons: []models.Lesson{
+				{
+					Title: "Europe",
+					Items: []models.Item{
+						{Type: "knowledgeCheck", Variant: "multipleChoice", Items: []models.SubItem{{
+							Title:    "<p>Capital of France?</p>",
+							Answers:  []models.Answer{{Title: "Lyon"}, {Title: "<p><b>Paris</b></p>", Correct: true}},
+							Feedback: "<p>Paris&nbsp;<b>is</b> right<br></p>",
+						}}},
+						{Type: "knowledgeCheck", Variant: "multipleResponse", Items: []models.SubItem{{
+							Title:   "<p>Which are in the EU?</p>",
+							Answers: []models.Answer{{Title: "Spain", Correct: true}, {Title: "Norway"}, {Title: "Italy", Correct: true}},
+						}}},
+					},
+				},
+				{Title: "Reading", Items: []models.Item{{Type: "text", Items: []models.SubItem{{Paragraph: "<p>Text</p>"}}}}},
+				{Title: "More", Type: "section"},
+				{
+					Title: "<p>World</p>",
+					Items: []models.Item{
+						{Type: "knowledgeCheck", Variant: "fillIn", Items: []models.SubItem{{
+							Title:   "<p>Capital of Peru?</p>",
+							Answers: []models.Answer{{Title: "Lima"}, {Title: "<p>Ciudad de los <em>Reyes</em></p>"}},
+						}}},
+						{Type: "knowledgeCheck", Variant: "matching", Items: []models.SubItem{{
+							Title:   "<p>Match the capitals</p>",
+							Answers: []models.Answer{{Title: "Japan", MatchTitle: "Tokyo"}, {Title: "Chile", MatchTitle: "<i>Santiago</i>"}},
+						}}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// TestQTIExporter_SupportedFormat tests the SupportedFormat method.
+func TestQTIExporter_SupportedFormat(t *testing.T) {
+	exporter := NewQTIExporter(services.NewHTMLCleaner())
+	if got := exporter.SupportedFormat(); got != "qti" {
+		t.Errorf("Expected format 'qti', got '%s'", got)
+	}
+}
+
+// TestQTIExporter_Package tests the manifest, the assessment test and the
+// mapping of each question type to its interaction.
+func TestQTIExporter_Package(t *testing.T) {
+	exporter := NewQTIExporter(services.NewHTMLCleaner())
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(createTestCourseForQTI(), &buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+	entries := readZipFiles(t, buf.Bytes())
+	files := make(map[string]string)
+	for _, entry := range entries {
+		files[entry.name] = entry.data
+	}
+	if entries[0].name != "imsmanifest.xml" {
+		t.Errorf("Expected imsmanifest.xml as first entry, got %s", entries[0].name)
+	}
+
+	var manifest qtiManifestDoc
+	if err := xml.Unmarshal([]byte(files["imsmanifest.xml"]), &manifest); err != nil {
+		t.Fatalf("Manifest is not well-formed: %v", err)
+	}
+	if len(manifest.Resources) != 5 {
+		t.Fatalf("Expected a test and 4 item resources, got %d", len(manifest.Resources))
+	}
+	if test := manifest.Resources[0]; test.Type != "imsqti_test_xmlv2p1" || test.Href != "assessment.xml" || len(test.Dependencies) != 4 {
+		t.Errorf("Unexpected test resource: %+v", test)
+	}
+	for _, res := range manifest.Resources[1:] {
+		if res.Type != "imsqti_item_xmlv2p1" {
+			t.Errorf("Expected item resource type, got %s", res.Type)
+		}
+		if _, ok := files[res.Href]; !ok {
+			t.Errorf("Resource %s refers to missing file %s", res.Identifier, res.Href)
+		}
+	}
+
+	var test qtiTestDoc
+	if err := xml.Unmarshal([]byte(files["assessment.xml"]), &test); err != nil {
+		t.Fatalf("Assessment test is not well-formed: %v", err)
+	}
+	if test.Title != "Capitals" || len(test.Sections) != 2 {
+		t.Fatalf("Expected 2 sections, lessons without questions left out, got %+v", test)
+	}
+	if test.Sections[1].Title != "Lesson 3: World" || len(test.Sections[1].Refs) != 2 ||
+		test.Sections[1].Refs[0].Href != "items/item-003.xml" {
+		t.Errorf("Unexpected section: %+v", test.Sections[1])
+	}
+
+	items := make([]qtiItemDoc, 4)
+	for i := range items {
+		name := fmt.Sprintf("items/item-%03d.xml", i+1)
+		if err := xml.Unmarshal([]byte(files[name]), &items[i]); err != nil {
+			t.Fatalf("%s is not well-formed: %v", name, err)
+		}
+		if items[i].XMLName.Space != "http://www.imsglobal.org/xsd/imsqti_v2p1" {
+			t.Errorf("%s: unexpected namespace %s", name, items[i].XMLName.Space)
+		}
+	}
+
+	// Single choice
+	single := items[0]
+	if single.Title != "Capital of France?" || single.Response.Cardinality != "single" || single.Response.BaseType != "identifier" ||
+		!slices.Equal(single.Response.Correct, []string{"CHOICE_2"}) {
+		t.Errorf("Unexpected single choice response: %+v", single.Response)
+	}
+	if single.Body.Choice == nil || single.Body.Choice.MaxChoices != 1 || len(single.Body.Choice.Choices) != 2 ||
+		single.Body.Choice.Choices[0].Content != "Lyon" || single.Body.Choice.Choices[1].Content != "<p><b>Paris</b></p>" {
+		t.Errorf("Unexpected single choice interaction: %+v", single.Body.Choice)
+	}
+	if single.Feedback == nil || single.Feedback.Outcome != "FEEDBACK" ||
+		!strings.Contains(single.Feedback.Content, "<p>Paris\u00a0<b>is</b> right<br/></p>") {
+		t.Errorf("Expected the feedback as XHTML, got %+v", single.Feedback)
+	}
+
+	// Multiple response
+	multiple := items[1]
+	if multiple.Response.Cardinality != "multiple" || !slices.Equal(multiple.Response.Correct, []string{"CHOICE_1", "CHOICE_3"}) {
+		t.Errorf("Unexpected multiple response: %+v", multiple.Response)
+	}
+	if multiple.Body.Choice == nil || multiple.Body.Choice.MaxChoices != 0 {
+		t.Errorf("Expected unlimited choices, got %+v", multiple.Body.Choice)
+	}
+	if multiple.Feedback != nil {
+		t.Error("Expected no feedback without feedback text")
+	}
+
+	// Fill in the blank
+	fillIn := items[2]
+	if fillIn.Response.BaseType != "string" || !slices.Equal(fillIn.Response.Correct, []string{"Lima"}) ||
+		len(fillIn.Response.Mapping) != 2 || fillIn.Response.Mapping[1].Key != "Ciudad de los Reyes" ||
+		fillIn.Response.Mapping[1].CaseSensitive != "false" {
+		t.Errorf("Unexpected fill-in response: %+v", fillIn.Response)
+	}
+	if fillIn.Body.TextEntry == nil || fillIn.Body.TextEntry.Response != "RESPONSE" {
+		t.Error("Expected a text entry interaction")
+	}
+
+	// Matching
+	matching := items[3]
+	if matching.Response.BaseType != "directedPair" ||
+		!slices.Equal(matching.Response.Correct, []string{"SOURCE_1 TARGET_1", "SOURCE_2 TARGET_2"}) {
+		t.Errorf("Unexpected matching response: %+v", matching.Response)
+	}
+	if matching.Body.Match == nil || len(matching.Body.Match.Sets) != 2 || matching.Body.Match.Sets[1].Choices[1].Content != "<i>Santiago</i>" {
+		t.Errorf("Unexpected match interaction: %+v", matching.Body.Match)
+	}
+}
+
+// TestQTIExporter_BuildItem tests how answers map to responses in edge cases.
+func TestQTIExporter_BuildItem(t *testing.T) {
+	exporter := NewQTIExporter(services.NewHTMLCleaner()).(*QTIExporter)
+
+	tests := []struct {
+		name         string
+		questionType string
+		answers      []models.Answer
+		maxChoices   int
+		correct      []string
+		targets      []qtiChoice
+	}{
+		{
+			name:         "unknown type with one correct answer",
+			questionType: "",
+			answers:      []models.Answer{{Title: "A", Correct: true}, {Title: "B"}},
+			maxChoices:   1,
+			correct:      []string{"CHOICE_1"},
+		},
+		{
+			name:         "single choice with two correct answers",
+			questionType: models.QuestionSingleChoice,
+			answers:      []models.Answer{{Title: "A", Correct: true}, {Title: "B", Correct: true}},
+			maxChoices:   0,
+			correct:      []string{"CHOICE_1", "CHOICE_2"},
+		},
+		{
+			name:         "matching with a shared target",
+			questionType: models.QuestionMatching,
+			answers:      []models.Answer{{Title: "Cat", MatchTitle: "Mammal"}, {Title: "Trout", MatchTitle: "Fish"}, {Title: "Dog", MatchTitle: "Mammal"}},
+			correct:      []string{"SOURCE_1 TARGET_1", "SOURCE_2 TARGET_2", "SOURCE_3 TARGET_1"},
+			targets:      []qtiChoice{{ID: "TARGET_1", Text: "Mammal", MatchMax: 2}, {ID: "TARGET_2", Text: "Fish", MatchMax: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qi := exporter.buildItem(7, tt.questionType, &models.SubItem{Answers: tt.answers})
+			if qi.ID != "item-007" || qi.Title != "Question 7" {
+				t.Errorf("Unexpected item identity: %s %q", qi.ID, qi.Title)
+			}
+			if qi.MaxChoices != tt.maxChoices {
+				t.Errorf("Expected maxChoices %d, got %d", tt.maxChoices, qi.MaxChoices)
+			}
+			if !slices.Equal(qi.Correct, tt.correct) {
+				t.Errorf("Expected correct response %v, got %v", tt.correct, qi.Correct)
+			}
+			if tt.targets != nil && !slices.Equal(qi.Targets, tt.targets) {
+				t.Errorf("Expected targets %v, got %v", tt.targets, qi.Targets)
+			}
+		})
+	}
+}
+
+// TestQTIExporter_NoQuestions tests that a course without questions is rejected.
+func TestQTIExporter_NoQuestions(t *testing.T) {
+	exporter := NewQTIExporter(services.NewHTMLCleaner())
+	course := &models.Course{Course: models.CourseInfo{
+		Title: "Reading only",
+		Lessons: []models.Lesson{{Title: "Text", Items: []models.Item{
+			{Type: "knowledgeCheck", Items: []models.SubItem{{Title: "<p>No answers</p>"}}},
+		}}},
+	}}
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); !errors.Is(err, errNoQuestions) {
+		t.Errorf("Expected errNoQuestions, got %v", err)
+	}
+}
+
+// TestQTIExporter_Export tests that Export writes the package to a file.
+func TestQTIExporter_Export(t *testing.T) {
+	exporter := NewQTIExporter(services.NewHTMLCleaner())
+	outputPath := filepath.Join(t.TempDir(), "questions.zip")
+
+	if err := exporter.Export(createTestCourseForQTI(), outputPath); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte("PK")) {
+		t.Error("Expected a zip archive")
+	}
+}
@@ -0,0 +1,74 @@
+package exporters
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// errNoQuestions is returned when a course without knowledge check questions
+// is exported to a quiz format, since there would be nothing to import.
+var errNoQuestions = errors.New("course has no knowledge check questions to export")
+
+// quizLesson holds the knowledge check questions of a lesson, for the
+// exporters that write questions only.
+type quizLesson struct {
+	// Number is the lesson number, counted like the other exporters do
+	Number    int
+	Title     string
+	Questions []quizQuestion
+}
+
+// quizQuestion is a knowledge check question with its answers and feedback.
+type quizQuestion struct {
+	// Type is one of the models.Question* constants, or empty when unknown
+	Type    string
+	SubItem models.SubItem
+}
+
+// quizLessons returns the lessons of course that have knowledge check
+// questions, in course order. Questions without answers are left out, since
+// no quiz format can represent them.
+func quizLessons(course *models.Course) []quizLesson {
+	var lessons []quizLesson
+	lessonCounter := 0
+	for _, lesson := range course.Course.Lessons {
+		if lesson.Type == lessonTypeSection {
+			continue
+		}
+		lessonCounter++
+
+		quiz := quizLesson{Number: lessonCounter, Title: lesson.Title}
+		for _, item := range lesson.Items {
+			if strings.ToLower(item.Type) != itemTypeKnowledgeCheck {
+				continue
+			}
+			questionType := ""
+			if details := services.ItemDetails(&item); details.KnowledgeCheck != nil {
+				questionType = details.KnowledgeCheck.QuestionType
+			}
+			for _, subItem := range item.Items {
+				if len(subItem.Answers) > 0 {
+					quiz.Questions = append(quiz.Questions, quizQuestion{Type: questionType, SubItem: subItem})
+				}
+			}
+		}
+		if len(quiz.Questions) > 0 {
+			lessons = append(lessons, quiz)
+		}
+	}
+	return lessons
+}
+
+// correctAnswers returns how many answers are marked correct.
+func correctAnswers(answers []models.Answer) int {
+	n := 0
+	for _, answer := range answers {
+		if answer.Correct {
+			n++
+		}
+	}
+	return n
+}
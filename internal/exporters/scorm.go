@@ -0,0 +1,267 @@
+package exporters
+
+import (
+	"archive/zip"
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	stdhtml "html"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/kjanat/articulate-parser/internal/interfaces"
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+//go:embed scorm_templates.gotmpl
+var scormTemplates string
+
+//go:embed scorm_page.gohtml
+var scormPageTemplate string
+
+//go:embed scorm_runtime.js
+var scormRuntime string
+
+// SCORM versions supported by the SCORM exporter.
+const (
+	SCORMVersion12   = "1.2"
+	SCORMVersion2004 = "2004"
+)
+
+// Files shared by all SCOs of a SCORM package.
+const (
+	scormManifest   = "imsmanifest.xml"
+	scormStylesheet = "styles.css"
+	scormScript     = "scorm.js"
+)
+
+// errNoLessons is returned when a course without lessons is packaged, since
+// SCORM and cmi5 packages need at least one SCO or AU.
+var errNoLessons = errors.New("course has no lessons to package")
+
+// SCORMExporter implements the Exporter interface for SCORM packages.
+// It writes a self-contained zip with one SCO per lesson. Lesson pages are
+// rendered with the HTML exporter's templates and include a runtime script
+// that reports completion to the LMS.
+type SCORMExporter struct {
+	// htmlCleaner is passed to the HTML templates
+	htmlCleaner *services.HTMLCleaner
+	// page renders lesson pages; it extends the HTML exporter's templates
+	page *htmltemplate.Template
+	// manifest renders imsmanifest.xml
+	manifest *template.Template
+	// version is SCORMVersion12 or SCORMVersion2004
+	version string
+}
+
+// scormSCO is a lesson packaged as a sharable content object. Sections have
+// no page of their own and group the SCOs of their lessons as Children.
+type scormSCO struct {
+	ID       string
+	Title    string
+	Href     string
+	Children []scormSCO
+}
+
+// scormManifestData is the data passed to the manifest templates.
+type scormManifestData struct {
+	Identifier  string
+	Title       string
+	Items       []scormSCO
+	SCOs        []scormSCO
+	SharedFiles []string
+}
+
+// scormPageData is the data passed to the lesson page template.
+type scormPageData struct {
+	CourseTitle string
+	Lesson      templateSection
+}
+
+// NewSCORMExporter creates a new SCORMExporter instance for a SCORM version.
+// Versions other than SCORMVersion2004 produce SCORM 1.2 packages, the
+// version most LMSes accept.
+//
+// Parameters:
+//   - htmlCleaner: Service for cleaning HTML content in course data
+//   - version: The SCORM version, SCORMVersion12 or SCORMVersion2004
+//
+// Returns:
+//   - An implementation of the Exporter interface for SCORM packages
+func NewSCORMExporter(htmlCleaner *services.HTMLCleaner, version string) interfaces.Exporter {
+	if version != SCORMVersion2004 {
+		version = SCORMVersion12
+	}
+
+	html := NewHTMLExporter(htmlCleaner).(*HTMLExporter)
+	page := htmltemplate.Must(htmltemplate.Must(html.tmpl.Clone()).Parse(scormPageTemplate))
+	manifest := template.Must(template.New("scorm").Funcs(template.FuncMap{
+		"esc": stdhtml.EscapeString,
+	}).Parse(scormTemplates))
+
+	return &SCORMExporter{
+		htmlCleaner: htmlCleaner,
+		page:        page,
+		manifest:    manifest,
+		version:     version,
+	}
+}
+
+// Export exports the course to a SCORM package file.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - outputPath: The file path where the zip package will be written
+//
+// Returns:
+//   - An error if building or saving the package fails
+func (e *SCORMExporter) Export(course *models.Course, outputPath string) error {
+	// #nosec G304 - Output path is provided by user via CLI argument, which is expected behavior
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	// Close errors are logged but not fatal, see DocxExporter.Export.
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to close output file: %v\n", err)
+		}
+	}()
+
+	return e.ExportTo(course, file)
+}
+
+// ExportTo exports the course as a SCORM package written to w.
+//
+// Parameters:
+//   - course: The course data model to export
+//   - w: The writer the zip package will be written to
+//
+// Returns:
+//   - An error if the course has no lessons or writing the package fails
+func (e *SCORMExporter) ExportTo(course *models.Course, w io.Writer) error {
+	data := prepareTemplateData(course, e.htmlCleaner)
+	manifest := scormManifestData{
+		Identifier:  "course-" + xmlID(scormCourseID(course)),
+		Title:       course.Course.Title,
+		SharedFiles: []string{scormStylesheet, scormScript},
+	}
+
+	files := map[string][]byte{
+		scormStylesheet: []byte(data.CSS),
+		scormScript:     []byte(strings.Replace(scormRuntime, "__SCORM_VERSION__", e.version, 1)),
+	}
+
+	// One page per lesson; lessons following a section are grouped below it
+	part := -1 // index of the current section in manifest.Items
+	sectionCounter := 0
+	for _, lesson := range data.Sections {
+		if lesson.Type == lessonTypeSection {
+			sectionCounter++
+			manifest.Items = append(manifest.Items, scormSCO{
+				ID:    fmt.Sprintf("section-%03d", sectionCounter),
+				Title: lesson.Title,
+			})
+			part = len(manifest.Items) - 1
+			continue
+		}
+
+		sco := scormSCO{
+			ID:    fmt.Sprintf("lesson-%03d", lesson.Number),
+			Title: fmt.Sprintf("Lesson %d: %s", lesson.Number, lesson.Title),
+		}
+		sco.Href = sco.ID + ".html"
+
+		var page bytes.Buffer
+		if err := e.page.ExecuteTemplate(&page, "scormPage", scormPageData{CourseTitle: course.Course.Title, Lesson: lesson}); err != nil {
+			return fmt.Errorf("failed to render %s: %w", sco.Href, err)
+		}
+		files[sco.Href] = page.Bytes()
+
+		manifest.SCOs = append(manifest.SCOs, sco)
+		if part >= 0 {
+			manifest.Items[part].Children = append(manifest.Items[part].Children, sco)
+		} else {
+			manifest.Items = append(manifest.Items, sco)
+		}
+	}
+	if len(manifest.SCOs) == 0 {
+		return errNoLessons
+	}
+
+	// Sections without lessons have nothing to launch
+	items := manifest.Items[:0]
+	for _, item := range manifest.Items {
+		if item.Href != "" || len(item.Children) > 0 {
+			items = append(items, item)
+		}
+	}
+	manifest.Items = items
+
+	var manifestXML bytes.Buffer
+	if err := e.manifest.ExecuteTemplate(&manifestXML, "manifest-"+e.version, manifest); err != nil {
+		return fmt.Errorf("failed to render %s: %w", scormManifest, err)
+	}
+
+	// The manifest goes first, followed by the shared files and the SCOs in order
+	zw := zip.NewWriter(w)
+	names := []string{scormManifest}
+	names = append(names, manifest.SharedFiles...)
+	for _, sco := range manifest.SCOs {
+		names = append(names, sco.Href)
+	}
+	files[scormManifest] = manifestXML.Bytes()
+
+	for _, name := range names {
+		fw, err := zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if _, err := fw.Write(files[name]); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write SCORM package: %w", err)
+	}
+	return nil
+}
+
+// SupportedFormat returns the format name this exporter supports.
+//
+// Returns:
+//   - A string representing the supported format ("scorm12" or "scorm2004")
+func (e *SCORMExporter) SupportedFormat() string {
+	if e.version == SCORMVersion2004 {
+		return FormatSCORM2004
+	}
+	return FormatSCORM12
+}
+
+// scormCourseID returns the ID the package identifier is derived from.
+func scormCourseID(course *models.Course) string {
+	if course.Course.ID != "" {
+		return course.Course.ID
+	}
+	if course.ShareID != "" {
+		return course.ShareID
+	}
+	return course.Course.Title
+}
+
+// xmlID turns s into a valid XML ID suffix by replacing every character
+// other than ASCII letters, digits, '-', '_' and '.' with '-'.
+func xmlID(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '-'
+	}, s)
+}
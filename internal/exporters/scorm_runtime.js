@@ -0,0 +1,99 @@
+// Reports lesson completion to the LMS through the SCORM runtime API.
+// The lesson is completed once its end has been scrolled into view.
+(function () {
+  "use strict";
+
+  var version = "__SCORM_VERSION__";
+  var calls =
+    version === "1.2"
+      ? {
+          api: "API",
+          initialize: "LMSInitialize",
+          getValue: "LMSGetValue",
+          setValue: "LMSSetValue",
+          commit: "LMSCommit",
+          terminate: "LMSFinish",
+          status: "cmi.core.lesson_status",
+          exit: "cmi.core.exit",
+          exitNormal: "",
+        }
+      : {
+          api: "API_1484_11",
+          initialize: "Initialize",
+          getValue: "GetValue",
+          setValue: "SetValue",
+          commit: "Commit",
+          terminate: "Terminate",
+          status: "cmi.completion_status",
+          exit: "cmi.exit",
+          exitNormal: "normal",
+        };
+
+  // findAPI looks for the LMS API in the parent frames of win.
+  function findAPI(win) {
+    for (var depth = 0; win && depth < 10; depth++) {
+      if (win[calls.api]) {
+        return win[calls.api];
+      }
+      if (win.parent === win) {
+        break;
+      }
+      win = win.parent;
+    }
+    return null;
+  }
+
+  var api = findAPI(window) || (window.opener && findAPI(window.opener));
+  var completed = false;
+  var terminated = false;
+
+  function complete() {
+    if (!api || completed || terminated) {
+      return;
+    }
+    completed = true;
+    api[calls.setValue](calls.status, "completed");
+    api[calls.commit]("");
+  }
+
+  function terminate() {
+    if (!api || terminated) {
+      return;
+    }
+    terminated = true;
+    api[calls.setValue](calls.exit, completed ? calls.exitNormal : "suspend");
+    api[calls.commit]("");
+    api[calls.terminate]("");
+  }
+
+  if (api && String(api[calls.initialize]("")) === "true") {
+    var status = api[calls.getValue](calls.status);
+    if (status === "completed" || status === "passed") {
+      completed = true;
+    } else {
+      api[calls.setValue](calls.status, "incomplete");
+    }
+  } else {
+    api = null;
+  }
+
+  window.addEventListener("load", function () {
+    var end = document.getElementById("scorm-end");
+    if (!end || !("IntersectionObserver" in window)) {
+      complete();
+      return;
+    }
+    var observer = new IntersectionObserver(function (entries) {
+      for (var i = 0; i < entries.length; i++) {
+        if (entries[i].isIntersecting) {
+          complete();
+          observer.disconnect();
+          return;
+        }
+      }
+    });
+    observer.observe(end);
+  });
+  window.addEventListener("pagehide", terminate);
+  window.addEventListener("beforeunload", terminate);
+})();
@@ -0,0 +1,251 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+	"github.com/kjanat/articulate-parser/internal/services"
+)
+
+// scormManifestDoc is the subset of imsmanifest.xml checked by the tests.
+type scormManifestDoc struct {
+	XMLName    xml.Name
+	Identifier string `xml:"identifier,attr"`
+	Metadata   struct {
+		Schema        string `xml:"schema"`
+		SchemaVersion string `xml:"schemaversion"`
+	} `xml:"metadata"`
+	Organizations struct {
+		Default      string `xml:"default,attr"`
+		Organization struct {
+			Identifier string          `xml:"identifier,attr"`
+			Title      string          `xml:"title"`
+			Items      []scormItemNode `xml:"item"`
+		} `xml:"organization"`
+	} `xml:"organizations"`
+	Resources []struct {
+		Identifier string     `xml:"identifier,attr"`
+		Href       string     `xml:"href,attr"`
+		Attrs      []xml.Attr `xml:",any,attr"`
+		Files      []struct {
+			Href string `xml:"href,attr"`
+		} `xml:"file"`
+	} `xml:"resources>resource"`
+}
+
+// scormItemNode is an organization item of the manifest.
+type scormItemNode struct {
+	Identifier    string          `xml:"identifier,attr"`
+	IdentifierRef string          `xml:"identifierref,attr"`
+	Title         string          `xml:"title"`
+	Items         []scormItemNode `xml:"item"`
+}
+
+// createTestCourseForSCORM returns a course with a lesson before the first
+// section, a section with two lessons and an empty section.
+func createTestCourseForSCORM() *models.Course {
+	return &models.Course{
+		ShareID: "share-id",
+		Course: models.CourseInfo{
+			ID:    "7f3a:course",
+			Title: "Fire Safety",
+			Lessons: []models.Lesson{
+				{Title: "Introduction", Description: "<p>Why it matters</p>"},
+				{Title: "Prevention", Type: "section"},
+				{
+					Title: "Hazards",
+					Items: []models.Item{
+						{Type: "text", Items: []models.SubItem{{Heading: "<h2>Heat sources</h2>", Paragraph: "<p>Keep clear</p>"}}},
+						{Type: "knowledgeCheck", Items: []models.SubItem{{
+							Title:   "<p>Which one?</p>",
+							Answers: []models.Answer{{Title: "Water", Correct: true}, {Title: "Oil"}},
+						}}},
+					},
+				},
+				{Title: "Alarms"},
+				{Title: "Appendix", Type: "section"},
+			},
+		},
+	}
+}
+
+// TestSCORMExporter_SupportedFormat tests the format names for each version.
+func TestSCORMExporter_SupportedFormat(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{SCORMVersion12, "scorm12"},
+		{SCORMVersion2004, "scorm2004"},
+		{"", "scorm12"},
+		{"3.0", "scorm12"},
+	}
+
+	for _, tt := range tests {
+		exporter := NewSCORMExporter(services.NewHTMLCleaner(), tt.version)
+		if got := exporter.SupportedFormat(); got != tt.expected {
+			t.Errorf("Expected format '%s' for version '%s', got '%s'", tt.expected, tt.version, got)
+		}
+	}
+}
+
+// TestSCORMExporter_Package tests the manifest, pages and runtime script of
+// SCORM 1.2 and SCORM 2004 packages.
+func TestSCORMExporter_Package(t *testing.T) {
+	tests := []struct {
+		version       string
+		namespace     string
+		schemaVersion string
+		scormTypeAttr string
+		apiCalls      []string
+	}{
+		{
+			version:       SCORMVersion12,
+			namespace:     "http://www.imsproject.org/xsd/imscp_rootv1p1p2",
+			schemaVersion: "1.2",
+			scormTypeAttr: "scormtype",
+			apiCalls:      []string{`version = "1.2"`, "LMSInitialize", "cmi.core.lesson_status"},
+		},
+		{
+			version:       SCORMVersion2004,
+			namespace:     "http://www.imsglobal.org/xsd/imscp_v1p1",
+			schemaVersion: "2004 4th Edition",
+			scormTypeAttr: "scormType",
+			apiCalls:      []string{`version = "2004"`, "API_1484_11", "cmi.completion_status"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			exporter := NewSCORMExporter(services.NewHTMLCleaner(), tt.version)
+
+			var buf bytes.Buffer
+			if err := exporter.ExportTo(createTestCourseForSCORM(), &buf); err != nil {
+				t.Fatalf("ExportTo failed: %v", err)
+			}
+			entries := readZipFiles(t, buf.Bytes())
+			files := make(map[string]string)
+			for _, entry := range entries {
+				files[entry.name] = entry.data
+			}
+			if entries[0].name != "imsmanifest.xml" {
+				t.Errorf("Expected imsmanifest.xml as first entry, got %s", entries[0].name)
+			}
+
+			var manifest scormManifestDoc
+			if err := xml.Unmarshal([]byte(files["imsmanifest.xml"]), &manifest); err != nil {
+				t.Fatalf("Manifest is not well-formed: %v", err)
+			}
+			if manifest.XMLName.Space != tt.namespace {
+				t.Errorf("Expected manifest namespace %s, got %s", tt.namespace, manifest.XMLName.Space)
+			}
+			if manifest.Identifier != "course-7f3a-course" {
+				t.Errorf("Expected sanitized identifier, got '%s'", manifest.Identifier)
+			}
+			if manifest.Metadata.Schema != "ADL SCORM" || manifest.Metadata.SchemaVersion != tt.schemaVersion {
+				t.Errorf("Unexpected metadata: %+v", manifest.Metadata)
+			}
+			org := manifest.Organizations.Organization
+			if manifest.Organizations.Default != org.Identifier || org.Title != "Fire Safety" {
+				t.Errorf("Unexpected organization: %+v", manifest.Organizations)
+			}
+
+			// The empty section is left out; the others group their lessons
+			if len(org.Items) != 2 {
+				t.Fatalf("Expected 2 top-level items, got %+v", org.Items)
+			}
+			if org.Items[0].IdentifierRef != "res-lesson-001" || org.Items[0].Title != "Lesson 1: Introduction" {
+				t.Errorf("Unexpected first item: %+v", org.Items[0])
+			}
+			section := org.Items[1]
+			if section.IdentifierRef != "" || section.Title != "Prevention" || len(section.Items) != 2 ||
+				section.Items[1].IdentifierRef != "res-lesson-003" {
+				t.Errorf("Unexpected section item: %+v", section)
+			}
+
+			// One SCO per lesson, each with its page in the package
+			scos := 0
+			for _, res := range manifest.Resources {
+				scormType := ""
+				for _, attr := range res.Attrs {
+					if attr.Name.Local == tt.scormTypeAttr {
+						scormType = attr.Value
+					}
+				}
+				for _, file := range res.Files {
+					if _, ok := files[file.Href]; !ok {
+						t.Errorf("Resource %s refers to missing file %s", res.Identifier, file.Href)
+					}
+				}
+				if scormType == "sco" {
+					scos++
+					if _, ok := files[res.Href]; !ok {
+						t.Errorf("SCO %s launches missing file %s", res.Identifier, res.Href)
+					}
+				}
+			}
+			if scos != 3 {
+				t.Errorf("Expected 3 SCOs, got %d", scos)
+			}
+
+			page := files["lesson-002.html"]
+			for _, expected := range []string{
+				"Lesson 2: Hazards",
+				`<script src="scorm.js"></script>`,
+				`<link rel="stylesheet" href="styles.css">`,
+				"Heat sources",
+				`<li class="correct-answer">Water</li>`,
+				`id="scorm-end"`,
+			} {
+				if !strings.Contains(page, expected) {
+					t.Errorf("Expected lesson page to contain '%s'", expected)
+				}
+			}
+			for _, expected := range tt.apiCalls {
+				if !strings.Contains(files["scorm.js"], expected) {
+					t.Errorf("Expected runtime script to contain '%s'", expected)
+				}
+			}
+			if files["styles.css"] != defaultCSS {
+				t.Error("Expected the HTML exporter's stylesheet")
+			}
+		})
+	}
+}
+
+// TestSCORMExporter_NoLessons tests that a course without lessons is rejected.
+func TestSCORMExporter_NoLessons(t *testing.T) {
+	exporter := NewSCORMExporter(services.NewHTMLCleaner(), SCORMVersion12)
+	course := &models.Course{Course: models.CourseInfo{
+		Title:   "Empty",
+		Lessons: []models.Lesson{{Title: "Only a section", Type: "section"}},
+	}}
+
+	var buf bytes.Buffer
+	if err := exporter.ExportTo(course, &buf); !errors.Is(err, errNoLessons) {
+		t.Errorf("Expected errNoLessons, got %v", err)
+	}
+}
+
+// TestSCORMExporter_Export tests that Export writes the package to a file.
+func TestSCORMExporter_Export(t *testing.T) {
+	exporter := NewSCORMExporter(services.NewHTMLCleaner(), SCORMVersion2004)
+	outputPath := filepath.Join(t.TempDir(), "course.zip")
+
+	if err := exporter.Export(createTestCourseForSCORM(), outputPath); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte("PK")) {
+		t.Error("Expected a zip archive")
+	}
+}
@@ -0,0 +1,182 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// yamlPlainScalar matches strings that can be written without quotes and
+// still read back as the same string.
+var yamlPlainScalar = regexp.MustCompile(`^[\p{L}_/][\p{L}\p{N} _./#()+-]*$`)
+
+// yamlReserved are plain scalars YAML 1.1 or 1.2 readers resolve to
+// something other than a string.
+var yamlReserved = map[string]bool{
+	"y": true, "yes": true, "n": true, "no": true, "on": true, "off": true,
+	"true": true, "false": true, "null": true, "~": true,
+}
+
+// yamlNode is a decoded JSON value that keeps the key order of objects.
+type yamlNode struct {
+	// keys and values are set for objects, in document order
+	keys   []string
+	values []*yamlNode
+	// items is set for arrays
+	items []*yamlNode
+	// scalar is the YAML form of strings, numbers, booleans and null
+	scalar string
+	// object and array tell empty collections apart from scalars
+	object, array bool
+}
+
+// jsonToYAML converts a JSON document to block-style YAML with the same keys
+// in the same order. Strings that could be misread are double-quoted using
+// JSON escapes, which YAML double-quoted scalars share.
+func jsonToYAML(w io.Writer, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := decodeYAMLNode(dec)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	switch {
+	case root.object && len(root.keys) > 0:
+		writeYAMLMapping(&buf, root, 0)
+	case root.array && len(root.items) > 0:
+		writeYAMLSequence(&buf, root, 0)
+	default:
+		buf.WriteString(yamlInline(root) + "\n")
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// decodeYAMLNode reads the next JSON value from dec.
+func decodeYAMLNode(dec *json.Decoder) (*yamlNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		node := &yamlNode{object: v == '{', array: v == '['}
+		for dec.More() {
+			if node.object {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, ok := keyTok.(string)
+				if !ok {
+					return nil, errors.New("invalid object key")
+				}
+				node.keys = append(node.keys, key)
+			}
+			child, err := decodeYAMLNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			if node.object {
+				node.values = append(node.values, child)
+			} else {
+				node.items = append(node.items, child)
+			}
+		}
+		if _, err := dec.Token(); err != nil { // Closing delimiter
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &yamlNode{scalar: yamlString(v)}, nil
+	case json.Number:
+		return &yamlNode{scalar: v.String()}, nil
+	case bool:
+		return &yamlNode{scalar: fmt.Sprint(v)}, nil
+	case nil:
+		return &yamlNode{scalar: "null"}, nil
+	default:
+		return nil, fmt.Errorf("unexpected JSON token %v", tok)
+	}
+}
+
+// writeYAMLMapping writes the keys of a non-empty object, one per line.
+func writeYAMLMapping(buf *bytes.Buffer, node *yamlNode, indent int) {
+	for i, key := range node.keys {
+		if i > 0 {
+			buf.WriteString(strings.Repeat(" ", indent))
+		}
+		buf.WriteString(yamlString(key) + ":")
+		writeYAMLValue(buf, node.values[i], indent)
+	}
+}
+
+// writeYAMLSequence writes the items of a non-empty array, one per "- ".
+func writeYAMLSequence(buf *bytes.Buffer, node *yamlNode, indent int) {
+	for i, item := range node.items {
+		if i > 0 {
+			buf.WriteString(strings.Repeat(" ", indent))
+		}
+		buf.WriteString("-")
+		switch {
+		case item.object && len(item.keys) > 0:
+			// The first key shares the line with the dash
+			buf.WriteString(" ")
+			writeYAMLMapping(buf, item, indent+2)
+		case item.array && len(item.items) > 0:
+			buf.WriteString(" ")
+			writeYAMLSequence(buf, item, indent+2)
+		default:
+			buf.WriteString(" " + yamlInline(item) + "\n")
+		}
+	}
+}
+
+// writeYAMLValue writes the value of a mapping key, either on the key's line
+// or as a nested block below it.
+func writeYAMLValue(buf *bytes.Buffer, node *yamlNode, indent int) {
+	switch {
+	case node.object && len(node.keys) > 0:
+		buf.WriteString("\n" + strings.Repeat(" ", indent+2))
+		writeYAMLMapping(buf, node, indent+2)
+	case node.array && len(node.items) > 0:
+		buf.WriteString("\n" + strings.Repeat(" ", indent+2))
+		writeYAMLSequence(buf, node, indent+2)
+	default:
+		buf.WriteString(" " + yamlInline(node) + "\n")
+	}
+}
+
+// yamlInline returns the single-line form of a scalar or empty collection.
+func yamlInline(node *yamlNode) string {
+	switch {
+	case node.object:
+		return "{}"
+	case node.array:
+		return "[]"
+	default:
+		return node.scalar
+	}
+}
+
+// yamlString returns s as a plain scalar when that is unambiguous, and
+// double-quoted otherwise.
+func yamlString(s string) string {
+	if yamlPlainScalar.MatchString(s) && !yamlReserved[strings.ToLower(s)] &&
+		!strings.HasSuffix(s, " ") && !strings.Contains(s, " #") {
+		return s
+	}
+
+	var quoted bytes.Buffer
+	enc := json.NewEncoder(&quoted)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s) // Encoding a string cannot fail
+	return strings.TrimSuffix(quoted.String(), "\n")
+}
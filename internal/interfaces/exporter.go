@@ -1,6 +1,10 @@
 package interfaces
 
-import "github.com/kjanat/articulate-parser/internal/models"
+import (
+	"io"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
 
 // Exporter defines the interface for exporting courses to different formats.
 // Implementations of this interface handle the conversion of course data to
@@ -10,6 +14,11 @@ type Exporter interface {
 	// specified output path. It returns an error if the export operation fails.
 	Export(course *models.Course, outputPath string) error
 
+	// ExportTo converts a course to the supported format and writes it to w,
+	// allowing exports to be streamed, e.g. to standard output.
+	// It returns an error if the export operation fails.
+	ExportTo(course *models.Course, w io.Writer) error
+
 	// SupportedFormat returns the name of the format this exporter supports.
 	// This is used to identify which exporter to use for a given format.
 	SupportedFormat() string
@@ -4,6 +4,7 @@ package interfaces
 
 import (
 	"context"
+	"io"
 
 	"github.com/kjanat/articulate-parser/internal/models"
 )
@@ -19,7 +20,14 @@ type CourseParser interface {
 	FetchCourse(ctx context.Context, uri string) (*models.Course, error)
 
 	// LoadCourseFromFile loads a course from a local file.
-	// It reads and parses the course data from the specified file path.
+	// It reads and parses the course data from the specified file path, which
+	// may be a JSON file, a course package such as a Rise SCORM zip, or a
+	// saved share page.
 	// Returns an error if the file cannot be read or if the data cannot be parsed.
 	LoadCourseFromFile(filePath string) (*models.Course, error)
+
+	// LoadCourseFromReader loads a course from JSON data read from r,
+	// such as standard input in a shell pipeline.
+	// Returns an error if the data cannot be read or parsed.
+	LoadCourseFromReader(r io.Reader) (*models.Course, error)
 }
@@ -13,6 +13,12 @@ type Course struct {
 	Course CourseInfo `json:"course"`
 	// LabelSet contains customized labels used in the course
 	LabelSet LabelSet `json:"labelSet"`
+	// Package describes the archive the course was loaded from, if any.
+	// It is not part of the Rise JSON payload and is never serialized.
+	Package *SourcePackage `json:"-"`
+	// LinkedLessonID is the lesson a share URL deep link pointed to, such as
+	// /share/<id>#/lessons/<lessonId>. Like Package it is never serialized.
+	LinkedLessonID string `json:"-"`
 }
 
 // CourseInfo contains the main details and content of an Articulate Rise course.
@@ -53,3 +59,12 @@ type LabelSet struct {
 	// Labels is a mapping of label keys to their customized values
 	Labels map[string]string `json:"labels"`
 }
+
+// SourcePackage describes an archive that bundles a course together with its
+// media files, such as the SCORM zip produced by Rise's "Export for LMS".
+type SourcePackage struct {
+	// Path is the location of the archive on disk
+	Path string
+	// Media maps media keys and file names to their paths inside the archive
+	Media map[string]string
+}
@@ -0,0 +1,87 @@
+package models
+
+import "encoding/json"
+
+// List styles of list items, derived from the item variant.
+const (
+	ListStyleBulleted   = "bulleted"
+	ListStyleNumbered   = "numbered"
+	ListStyleCheckboxes = "checkboxes"
+)
+
+// Question types of knowledge check items, derived from the item variant.
+const (
+	// QuestionSingleChoice has exactly one correct answer ("multiple choice" in Rise)
+	QuestionSingleChoice = "single"
+	// QuestionMultipleChoice has one or more correct answers ("multiple response" in Rise)
+	QuestionMultipleChoice = "multiple"
+	// QuestionFillIn accepts any of the listed answers as free text
+	QuestionFillIn = "fillin"
+	// QuestionMatching pairs each answer with its MatchTitle
+	QuestionMatching = "matching"
+)
+
+// ItemDetails is the typed form of an item's Settings and Data, decoded after
+// the course JSON has been unmarshaled. Only the details matching the item
+// family are set. The raw JSON is always kept so that settings of unknown
+// families or variants are not lost.
+type ItemDetails struct {
+	// Layout holds the block layout settings shared by all item families
+	Layout BlockLayout `json:"layout"`
+	// List is set for list items
+	List *ListDetails `json:"list,omitempty"`
+	// KnowledgeCheck is set for knowledge check items
+	KnowledgeCheck *KnowledgeCheckDetails `json:"knowledgeCheck,omitempty"`
+	// Media is set for multimedia and image items
+	Media *MediaDetails `json:"media,omitempty"`
+	// Known reports whether the item family and variant were recognized
+	Known bool `json:"known"`
+	// RawSettings is the original Settings JSON, if any
+	RawSettings json.RawMessage `json:"rawSettings,omitempty"`
+	// RawData is the original Data JSON, if any
+	RawData json.RawMessage `json:"rawData,omitempty"`
+}
+
+// BlockLayout contains the layout settings Rise stores for every block.
+type BlockLayout struct {
+	// PaddingTop is the space above the block in Rise spacing units
+	PaddingTop int `json:"paddingTop,omitempty"`
+	// PaddingBottom is the space below the block in Rise spacing units
+	PaddingBottom int `json:"paddingBottom,omitempty"`
+	// BackgroundColor is the block background as a CSS color
+	BackgroundColor string `json:"backgroundColor,omitempty"`
+	// ContentWidth is the width of the block content, e.g. "standard" or "wide"
+	ContentWidth string `json:"contentWidth,omitempty"`
+}
+
+// ListDetails describes how a list item is rendered.
+type ListDetails struct {
+	// Style is one of ListStyleBulleted, ListStyleNumbered or ListStyleCheckboxes
+	Style string `json:"style"`
+}
+
+// KnowledgeCheckDetails describes the behaviour of a knowledge check item.
+type KnowledgeCheckDetails struct {
+	// QuestionType is one of the Question* constants
+	QuestionType string `json:"questionType"`
+	// Shuffle indicates that answers are presented in random order
+	Shuffle bool `json:"shuffle,omitempty"`
+	// AllowRetry indicates that learners may answer again
+	AllowRetry bool `json:"allowRetry,omitempty"`
+	// ShowAnswer indicates that the correct answer is revealed after submitting
+	ShowAnswer bool `json:"showAnswer,omitempty"`
+	// Points is the score awarded for a correct answer
+	Points float64 `json:"points,omitempty"`
+}
+
+// MediaDetails describes the playback and display options of a media item.
+type MediaDetails struct {
+	// Layout is the display variant, e.g. "video", "audio", "hero" or "full"
+	Layout string `json:"layout,omitempty"`
+	// Autoplay indicates that video or audio starts playing automatically
+	Autoplay bool `json:"autoplay,omitempty"`
+	// Loop indicates that video or audio restarts when it ends
+	Loop bool `json:"loop,omitempty"`
+	// ZoomOnClick indicates that images can be enlarged by clicking them
+	ZoomOnClick bool `json:"zoomOnClick,omitempty"`
+}
@@ -44,6 +44,9 @@ type Item struct {
 	Data any `json:"data"`
 	// Media contains any associated media for the item
 	Media *Media `json:"media,omitempty"`
+	// Details is the typed form of Settings and Data. It is filled in after
+	// unmarshaling by the parser and is never serialized.
+	Details *ItemDetails `json:"-"`
 }
 
 // SubItem represents a specific content element within an Item.
@@ -0,0 +1,25 @@
+package models
+
+// Kinds of schema issues reported when course JSON drifts from the models.
+const (
+	// SchemaIssueUnknownField is a JSON key that no model field decodes
+	SchemaIssueUnknownField = "unknown-field"
+	// SchemaIssueUnknownItem is an item type or variant that the exporters do not recognize
+	SchemaIssueUnknownItem = "unknown-item"
+)
+
+// SchemaIssue describes a difference between course JSON and the structure
+// the models and exporters understand, such as a key added by a newer
+// version of Rise.
+type SchemaIssue struct {
+	// Kind is one of the SchemaIssue* constants
+	Kind string `json:"kind"`
+	// Path locates the JSON value, e.g. "course.lessons[0].items[2].media"
+	Path string `json:"path"`
+	// LessonID is the ID of the enclosing lesson, if any
+	LessonID string `json:"lessonId,omitempty"`
+	// ItemID is the ID of the enclosing item, if any
+	ItemID string `json:"itemId,omitempty"`
+	// Message describes the issue, e.g. `unknown field "transcript"`
+	Message string `json:"message"`
+}
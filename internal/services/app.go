@@ -5,11 +5,17 @@ package services
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/kjanat/articulate-parser/internal/interfaces"
 	"github.com/kjanat/articulate-parser/internal/models"
 )
 
+// StdioPath is the source or output path that refers to standard input or
+// standard output, allowing the application to be used in shell pipelines.
+const StdioPath = "-"
+
 // App represents the main application service that coordinates the parsing
 // and exporting of Articulate Rise courses. It serves as the primary entry
 // point for the application's functionality.
@@ -18,6 +24,10 @@ type App struct {
 	parser interfaces.CourseParser
 	// exporterFactory creates the appropriate exporter for a given format
 	exporterFactory interfaces.ExporterFactory
+	// stdin is read when the source path is StdioPath
+	stdin io.Reader
+	// stdout is written to when the output path is StdioPath
+	stdout io.Writer
 }
 
 // NewApp creates a new application instance with dependency injection.
@@ -27,16 +37,19 @@ func NewApp(parser interfaces.CourseParser, exporterFactory interfaces.ExporterF
 	return &App{
 		parser:          parser,
 		exporterFactory: exporterFactory,
+		stdin:           os.Stdin,
+		stdout:          os.Stdout,
 	}
 }
 
 // ProcessCourseFromFile loads a course from a local file and exports it to the specified format.
 // It takes the path to the course file, the desired export format, and the output file path.
+// A file path of StdioPath reads the course JSON from standard input.
 // Returns an error if loading or exporting fails.
 func (a *App) ProcessCourseFromFile(filePath, format, outputPath string) error {
-	course, err := a.parser.LoadCourseFromFile(filePath)
+	course, err := a.loadCourseFromFile(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to load course from file: %w", err)
+		return err
 	}
 
 	return a.exportCourse(course, format, outputPath)
@@ -46,16 +59,60 @@ func (a *App) ProcessCourseFromFile(filePath, format, outputPath string) error {
 // It takes the URI to fetch the course from, the desired export format, and the output file path.
 // Returns an error if fetching or exporting fails.
 func (a *App) ProcessCourseFromURI(ctx context.Context, uri, format, outputPath string) error {
-	course, err := a.parser.FetchCourse(ctx, uri)
+	course, err := a.fetchCourse(ctx, uri)
 	if err != nil {
-		return fmt.Errorf("failed to fetch course: %w", err)
+		return err
 	}
 
 	return a.exportCourse(course, format, outputPath)
 }
 
+// ValidateCourseFromFile loads a course from a local file, or from standard
+// input for StdioPath, without exporting it. With a strict parser, schema
+// drift is returned as a *SchemaDriftError.
+func (a *App) ValidateCourseFromFile(filePath string) error {
+	_, err := a.loadCourseFromFile(filePath)
+	return err
+}
+
+// ValidateCourseFromURI fetches a course from the provided URI without
+// exporting it. With a strict parser, schema drift is returned as a
+// *SchemaDriftError.
+func (a *App) ValidateCourseFromURI(ctx context.Context, uri string) error {
+	_, err := a.fetchCourse(ctx, uri)
+	return err
+}
+
+// loadCourseFromFile loads a course from a local file, or from standard input
+// if the file path is StdioPath.
+func (a *App) loadCourseFromFile(filePath string) (*models.Course, error) {
+	if filePath == StdioPath {
+		course, err := a.parser.LoadCourseFromReader(a.stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load course from stdin: %w", err)
+		}
+		return course, nil
+	}
+
+	course, err := a.parser.LoadCourseFromFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load course from file: %w", err)
+	}
+	return course, nil
+}
+
+// fetchCourse fetches a course from the provided URI.
+func (a *App) fetchCourse(ctx context.Context, uri string) (*models.Course, error) {
+	course, err := a.parser.FetchCourse(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch course: %w", err)
+	}
+	return course, nil
+}
+
 // exportCourse exports a course to the specified format and output path.
 // It's a helper method that creates the appropriate exporter and performs the export.
+// An output path of StdioPath writes the export to standard output.
 // Returns an error if creating the exporter or exporting the course fails.
 func (a *App) exportCourse(course *models.Course, format, outputPath string) error {
 	exporter, err := a.exporterFactory.CreateExporter(format)
@@ -63,6 +120,13 @@ func (a *App) exportCourse(course *models.Course, format, outputPath string) err
 		return fmt.Errorf("failed to create exporter: %w", err)
 	}
 
+	if outputPath == StdioPath {
+		if err := exporter.ExportTo(course, a.stdout); err != nil {
+			return fmt.Errorf("failed to export course: %w", err)
+		}
+		return nil
+	}
+
 	if err := exporter.Export(course, outputPath); err != nil {
 		return fmt.Errorf("failed to export course: %w", err)
 	}
@@ -1,8 +1,11 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/kjanat/articulate-parser/internal/interfaces"
@@ -11,8 +14,9 @@ import (
 
 // MockCourseParser is a mock implementation of interfaces.CourseParser for testing.
 type MockCourseParser struct {
-	mockFetchCourse        func(ctx context.Context, uri string) (*models.Course, error)
-	mockLoadCourseFromFile func(filePath string) (*models.Course, error)
+	mockFetchCourse          func(ctx context.Context, uri string) (*models.Course, error)
+	mockLoadCourseFromFile   func(filePath string) (*models.Course, error)
+	mockLoadCourseFromReader func(r io.Reader) (*models.Course, error)
 }
 
 func (m *MockCourseParser) FetchCourse(ctx context.Context, uri string) (*models.Course, error) {
@@ -29,9 +33,17 @@ func (m *MockCourseParser) LoadCourseFromFile(filePath string) (*models.Course,
 	return nil, errors.New("not implemented")
 }
 
+func (m *MockCourseParser) LoadCourseFromReader(r io.Reader) (*models.Course, error) {
+	if m.mockLoadCourseFromReader != nil {
+		return m.mockLoadCourseFromReader(r)
+	}
+	return nil, errors.New("not implemented")
+}
+
 // MockExporter is a mock implementation of interfaces.Exporter for testing.
 type MockExporter struct {
 	mockExport          func(course *models.Course, outputPath string) error
+	mockExportTo        func(course *models.Course, w io.Writer) error
 	mockSupportedFormat func() string
 }
 
@@ -42,6 +54,13 @@ func (m *MockExporter) Export(course *models.Course, outputPath string) error {
 	return nil
 }
 
+func (m *MockExporter) ExportTo(course *models.Course, w io.Writer) error {
+	if m.mockExportTo != nil {
+		return m.mockExportTo(course, w)
+	}
+	return nil
+}
+
 func (m *MockExporter) SupportedFormat() string {
 	if m.mockSupportedFormat != nil {
 		return m.mockSupportedFormat()
@@ -302,6 +321,74 @@ func TestApp_ProcessCourseFromURI(t *testing.T) {
 	}
 }
 
+// TestApp_ProcessCourseFromFile_Stdio tests reading from stdin and writing to stdout.
+func TestApp_ProcessCourseFromFile_Stdio(t *testing.T) {
+	testCourse := createTestCourse()
+
+	parser := &MockCourseParser{
+		mockLoadCourseFromFile: func(filePath string) (*models.Course, error) {
+			t.Errorf("LoadCourseFromFile should not be called for stdin, got '%s'", filePath)
+			return testCourse, nil
+		},
+		mockLoadCourseFromReader: func(r io.Reader) (*models.Course, error) {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("Failed to read stdin: %v", err)
+			}
+			if string(data) != `{"course":{}}` {
+				t.Errorf("Expected stdin content to be passed through, got '%s'", string(data))
+			}
+			return testCourse, nil
+		},
+	}
+	exporter := &MockExporter{
+		mockExport: func(course *models.Course, outputPath string) error {
+			t.Errorf("Export should not be called for stdout, got '%s'", outputPath)
+			return nil
+		},
+		mockExportTo: func(course *models.Course, w io.Writer) error {
+			_, err := io.WriteString(w, "exported "+course.Course.Title)
+			return err
+		},
+	}
+	factory := &MockExporterFactory{
+		mockCreateExporter: func(format string) (*MockExporter, error) {
+			return exporter, nil
+		},
+	}
+
+	var stdout bytes.Buffer
+	app := NewApp(parser, factory)
+	app.stdin = strings.NewReader(`{"course":{}}`)
+	app.stdout = &stdout
+
+	if err := app.ProcessCourseFromFile(StdioPath, "markdown", StdioPath); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if stdout.String() != "exported Test Course" {
+		t.Errorf("Expected export on stdout, got '%s'", stdout.String())
+	}
+
+	parser.mockLoadCourseFromReader = func(r io.Reader) (*models.Course, error) {
+		return nil, errors.New("unexpected EOF")
+	}
+	err := app.ProcessCourseFromFile(StdioPath, "markdown", StdioPath)
+	if err == nil || !strings.Contains(err.Error(), "failed to load course from stdin") {
+		t.Errorf("Expected stdin load error, got %v", err)
+	}
+
+	exporter.mockExportTo = func(course *models.Course, w io.Writer) error {
+		return errors.New("broken pipe")
+	}
+	parser.mockFetchCourse = func(ctx context.Context, uri string) (*models.Course, error) {
+		return testCourse, nil
+	}
+	err = app.ProcessCourseFromURI(context.Background(), "https://rise.articulate.com/share/x", "markdown", StdioPath)
+	if err == nil || !strings.Contains(err.Error(), "failed to export course") {
+		t.Errorf("Expected export error, got %v", err)
+	}
+}
+
 // TestApp_SupportedFormats tests the SupportedFormats method.
 func TestApp_SupportedFormats(t *testing.T) {
 	expectedFormats := []string{"markdown", "docx", "pdf"}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kjanat/articulate-parser/internal/config"
+)
+
+// httpOnlyPrefix marks HttpOnly cookies in Netscape cookie files, which would
+// otherwise look like comments.
+const httpOnlyPrefix = "#HttpOnly_"
+
+// Credentials holds the authentication sent with course requests, for share
+// links that are private, password-protected or restricted to reviewers.
+// The zero value sends anonymous requests.
+type Credentials struct {
+	// BearerToken is sent as "Authorization: Bearer <token>" if set
+	BearerToken string
+	// Headers are added to every request, e.g. custom session headers
+	Headers http.Header
+	// Cookies are sent with every request regardless of the host
+	Cookies []*http.Cookie
+	// FileCookies are loaded from a cookie file and only sent to matching domains
+	FileCookies []*http.Cookie
+}
+
+// LoadCredentials builds the credentials described by the configuration,
+// reading the Netscape cookie file if one is configured.
+func LoadCredentials(cfg *config.Config) (*Credentials, error) {
+	creds := &Credentials{BearerToken: cfg.AuthToken}
+
+	headers, err := parseHeaders(cfg.Headers)
+	if err != nil {
+		return nil, err
+	}
+	creds.Headers = headers
+
+	if cfg.Cookies != "" {
+		cookies, err := http.ParseCookie(cfg.Cookies)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cookies: %w", err)
+		}
+		creds.Cookies = cookies
+	}
+
+	if cfg.CookieFile != "" {
+		// #nosec G304 - Cookie file path is provided by the user, which is expected behavior
+		f, err := os.Open(cfg.CookieFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open cookie file: %w", err)
+		}
+		// Close errors are ignored: the file is only read and has been fully parsed
+		defer func() { _ = f.Close() }()
+
+		if creds.FileCookies, err = ParseCookieFile(f); err != nil {
+			return nil, fmt.Errorf("failed to parse cookie file %s: %w", cfg.CookieFile, err)
+		}
+	}
+
+	return creds, nil
+}
+
+// Secrets returns the credential values that must never appear in logs.
+func (c *Credentials) Secrets() []string {
+	if c == nil {
+		return nil
+	}
+
+	var secrets []string
+	if c.BearerToken != "" {
+		secrets = append(secrets, c.BearerToken)
+	}
+	for _, values := range c.Headers {
+		secrets = append(secrets, values...)
+	}
+	for _, cookie := range c.Cookies {
+		secrets = append(secrets, cookie.Value)
+	}
+	for _, cookie := range c.FileCookies {
+		secrets = append(secrets, cookie.Value)
+	}
+	return secrets
+}
+
+// apply adds the bearer token, headers and host-independent cookies to a request.
+// Cookies from a cookie file are sent by the client's cookie jar instead.
+func (c *Credentials) apply(req *http.Request) {
+	if c == nil {
+		return
+	}
+	for key, values := range c.Headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if c.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.BearerToken)
+	}
+	for _, cookie := range c.Cookies {
+		req.AddCookie(cookie)
+	}
+}
+
+// cookieJar returns a jar holding the cookie file cookies, so that each
+// request only carries the cookies matching its domain, or nil if there are none.
+func (c *Credentials) cookieJar() http.CookieJar {
+	if c == nil || len(c.FileCookies) == 0 {
+		return nil
+	}
+
+	// cookiejar.New only fails for invalid options, and nil options are valid
+	jar, _ := cookiejar.New(nil)
+	for _, cookie := range c.FileCookies {
+		u := &url.URL{Scheme: "https", Host: strings.TrimPrefix(cookie.Domain, "."), Path: cookie.Path}
+		if !strings.HasPrefix(cookie.Domain, ".") {
+			// An empty Domain makes the jar bind the cookie to the exact host
+			hostOnly := *cookie
+			hostOnly.Domain = ""
+			cookie = &hostOnly
+		}
+		jar.SetCookies(u, []*http.Cookie{cookie})
+	}
+	return jar
+}
+
+// ParseCookieFile parses cookies in the Netscape cookie file format used by
+// curl, wget and browser export extensions. Each line holds seven tab-separated
+// fields: domain, include subdomains, path, secure, expiry, name and value.
+// Expired cookies are skipped.
+func ParseCookieFile(r io.Reader) ([]*http.Cookie, error) {
+	var cookies []*http.Cookie
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := strings.HasPrefix(line, httpOnlyPrefix)
+		line = strings.TrimPrefix(line, httpOnlyPrefix)
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("line %d: expected 7 tab-separated fields, got %d", lineNo, len(fields))
+		}
+
+		expiry, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid expiry %q", lineNo, fields[4])
+		}
+
+		cookie := &http.Cookie{
+			Name:     fields[5],
+			Value:    fields[6],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			HttpOnly: httpOnly,
+		}
+		// Domain cookies keep a leading dot; host-only cookies are stored without one
+		cookie.Domain = strings.TrimPrefix(fields[0], ".")
+		if strings.EqualFold(fields[1], "TRUE") {
+			cookie.Domain = "." + cookie.Domain
+		}
+		if expiry > 0 {
+			cookie.Expires = time.Unix(expiry, 0)
+			if cookie.Expires.Before(time.Now()) {
+				continue
+			}
+		}
+		cookies = append(cookies, cookie)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cookie file: %w", err)
+	}
+	return cookies, nil
+}
+
+// parseHeaders parses "Name: value" lines into an http.Header.
+func parseHeaders(lines []string) (http.Header, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	headers := make(http.Header)
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid header %q: expected \"Name: value\"", redactHeaderLine(line))
+		}
+		headers.Add(name, strings.TrimSpace(value))
+	}
+	return headers, nil
+}
+
+// redactHeaderLine hides the value of a header line so that it can be
+// included in an error message.
+func redactHeaderLine(line string) string {
+	if name, _, ok := strings.Cut(line, ":"); ok {
+		return name + ": " + redactedValue
+	}
+	if len(line) > 8 {
+		return line[:4] + "..."
+	}
+	return line
+}
+
+// statusError describes an unsuccessful API response. Authentication failures
+// get a dedicated message instead of the response body, which is rarely useful
+// and may echo request details.
+func statusError(code int, body []byte) error {
+	switch code {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("authentication required: API returned status %d; "+
+			"the share link may be private or password-protected, provide a bearer token, cookies or headers", code)
+	default:
+		return fmt.Errorf("API returned status %d: %s", code, string(body))
+	}
+}
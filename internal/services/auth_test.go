@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kjanat/articulate-parser/internal/config"
+	"github.com/kjanat/articulate-parser/internal/interfaces"
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// recordingLogger captures log entries for assertions.
+type recordingLogger struct {
+	entries *[]string
+}
+
+func (l recordingLogger) record(level, msg string, keysAndValues []any) {
+	*l.entries = append(*l.entries, fmt.Sprint(level, " ", msg, " ", keysAndValues))
+}
+
+func (l recordingLogger) Debug(msg string, kv ...any) { l.record("DEBUG", msg, kv) }
+func (l recordingLogger) Info(msg string, kv ...any)  { l.record("INFO", msg, kv) }
+func (l recordingLogger) Warn(msg string, kv ...any)  { l.record("WARN", msg, kv) }
+func (l recordingLogger) Error(msg string, kv ...any) { l.record("ERROR", msg, kv) }
+
+func (l recordingLogger) With(kv ...any) interfaces.Logger {
+	l.record("WITH", "", kv)
+	return l
+}
+
+func (l recordingLogger) WithContext(ctx context.Context) interfaces.Logger { return l }
+
+// TestParseCookieFile tests parsing Netscape cookie files.
+func TestParseCookieFile(t *testing.T) {
+	future := time.Now().Add(time.Hour).Unix()
+	file := strings.Join([]string{
+		"# Netscape HTTP Cookie File",
+		"",
+		fmt.Sprintf(".articulate.com\tTRUE\t/\tTRUE\t%d\tsession\tabc123", future),
+		"#HttpOnly_rise.articulate.com\tFALSE\t/api\tFALSE\t0\treview\txyz789",
+		".articulate.com\tTRUE\t/\tFALSE\t1000\texpired\told",
+	}, "\n")
+
+	cookies, err := ParseCookieFile(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(cookies) != 2 {
+		t.Fatalf("Expected 2 unexpired cookies, got %d", len(cookies))
+	}
+
+	session := cookies[0]
+	if session.Name != "session" || session.Value != "abc123" || session.Domain != ".articulate.com" || !session.Secure {
+		t.Errorf("Unexpected domain cookie: %+v", session)
+	}
+	if session.Expires.Unix() != future {
+		t.Errorf("Expected expiry %d, got %d", future, session.Expires.Unix())
+	}
+
+	review := cookies[1]
+	if review.Name != "review" || review.Domain != "rise.articulate.com" || review.Path != "/api" || !review.HttpOnly {
+		t.Errorf("Unexpected host-only cookie: %+v", review)
+	}
+	if !review.Expires.IsZero() {
+		t.Errorf("Expected session cookie without expiry, got %v", review.Expires)
+	}
+
+	_, err = ParseCookieFile(strings.NewReader("rise.articulate.com\tFALSE\t/\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 1: expected 7 tab-separated fields") {
+		t.Errorf("Expected field count error, got %v", err)
+	}
+	_, err = ParseCookieFile(strings.NewReader("a\tFALSE\t/\tFALSE\tnever\tn\tv\n"))
+	if err == nil || !strings.Contains(err.Error(), "invalid expiry") {
+		t.Errorf("Expected expiry error, got %v", err)
+	}
+}
+
+// TestLoadCredentials tests building credentials from the configuration.
+func TestLoadCredentials(t *testing.T) {
+	cookieFile := filepath.Join(t.TempDir(), "cookies.txt")
+	if err := os.WriteFile(cookieFile, []byte("rise.articulate.com\tFALSE\t/\tFALSE\t0\tfilecookie\tfile-secret\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write cookie file: %v", err)
+	}
+
+	creds, err := LoadCredentials(&config.Config{
+		AuthToken:  "bearer-secret",
+		Headers:    []string{"X-Review-Session: header-secret"},
+		Cookies:    "a=cookie-secret; b=other-secret",
+		CookieFile: cookieFile,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if got := creds.Headers.Get("X-Review-Session"); got != "header-secret" {
+		t.Errorf("Expected header value 'header-secret', got '%s'", got)
+	}
+	if len(creds.Cookies) != 2 || len(creds.FileCookies) != 1 {
+		t.Errorf("Expected 2 cookies and 1 file cookie, got %d and %d", len(creds.Cookies), len(creds.FileCookies))
+	}
+
+	secrets := strings.Join(creds.Secrets(), ",")
+	for _, expected := range []string{"bearer-secret", "header-secret", "cookie-secret", "other-secret", "file-secret"} {
+		if !strings.Contains(secrets, expected) {
+			t.Errorf("Expected secrets to contain '%s', got '%s'", expected, secrets)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		cfg           config.Config
+		expectedError string
+	}{
+		{"invalid header", config.Config{Headers: []string{"no-colon-secret"}}, "invalid header"},
+		{"header name with space", config.Config{Headers: []string{"Bad Name: v"}}, "invalid header"},
+		{"invalid cookies", config.Config{Cookies: "=bad"}, "failed to parse cookies"},
+		{"missing cookie file", config.Config{CookieFile: filepath.Join(t.TempDir(), "missing.txt")}, "failed to open cookie file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadCredentials(&tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Fatalf("Expected error containing '%s', got %v", tt.expectedError, err)
+			}
+			if strings.Contains(err.Error(), "secret") {
+				t.Errorf("Expected error not to echo header values, got '%s'", err)
+			}
+		})
+	}
+}
+
+// TestArticulateParser_FetchCourse_Auth tests sending credentials with course requests.
+func TestArticulateParser_FetchCourse_Auth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checks := map[string]string{
+			"Authorization":    "Bearer review-token",
+			"X-Review-Session": "session-42",
+		}
+		for header, expected := range checks {
+			if got := r.Header.Get(header); got != expected {
+				t.Errorf("Expected %s '%s', got '%s'", header, expected, got)
+			}
+		}
+		for name, expected := range map[string]string{"inline": "cookie-1", "jarred": "cookie-2"} {
+			if c, err := r.Cookie(name); err != nil || c.Value != expected {
+				t.Errorf("Expected cookie %s=%s, got %v (%v)", name, expected, c, err)
+			}
+		}
+		if _, err := r.Cookie("elsewhere"); err == nil {
+			t.Error("Expected cookie for another domain not to be sent")
+		}
+		_ = json.NewEncoder(w).Encode(&models.Course{ShareID: "private"})
+	}))
+	defer server.Close()
+
+	creds := &Credentials{
+		BearerToken: "review-token",
+		Headers:     http.Header{"X-Review-Session": {"session-42"}},
+		Cookies:     []*http.Cookie{{Name: "inline", Value: "cookie-1"}},
+		FileCookies: []*http.Cookie{
+			{Name: "jarred", Value: "cookie-2", Domain: "127.0.0.1", Path: "/"},
+			{Name: "elsewhere", Value: "cookie-3", Domain: ".example.com", Path: "/"},
+		},
+	}
+	parser := NewArticulateParserFromConfig(nil, &config.Config{BaseURL: server.URL}, creds).(*ArticulateParser)
+
+	if _, err := parser.FetchCourse(context.Background(), "https://rise.articulate.com/share/private"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+}
+
+// TestArticulateParser_FetchCourse_AuthRequired tests the error for rejected credentials.
+func TestArticulateParser_FetchCourse_AuthRequired(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "<html>login page echoing token</html>", status)
+			}))
+			defer server.Close()
+
+			parser := newRetryTestParser(server.URL, 3)
+			_, err := parser.FetchCourse(context.Background(), "https://rise.articulate.com/share/private")
+
+			if err == nil || !strings.Contains(err.Error(), "authentication required") {
+				t.Fatalf("Expected authentication required error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), fmt.Sprintf("status %d", status)) {
+				t.Errorf("Expected status %d in error, got '%s'", status, err)
+			}
+			if strings.Contains(err.Error(), "login page") {
+				t.Errorf("Expected response body to be omitted, got '%s'", err)
+			}
+		})
+	}
+}
+
+// TestRedactingLogger tests that secrets never reach the wrapped logger.
+func TestRedactingLogger(t *testing.T) {
+	var entries []string
+	logger := NewRedactingLogger(recordingLogger{entries: &entries}, "super-secret-token", "abc", "")
+
+	logger.Info("using token super-secret-token", "url", "https://x/?t=super-secret-token")
+	logger.Warn("request failed", "error", errors.New("bad super-secret-token"), "Authorization", "Bearer anything", "attempt", 2)
+	logger.With("session_cookie", "raw-value").Debug("done", "short", "abc")
+
+	output := strings.Join(entries, "\n")
+	for _, leaked := range []string{"super-secret-token", "Bearer anything", "raw-value"} {
+		if strings.Contains(output, leaked) {
+			t.Errorf("Expected '%s' to be redacted, got:\n%s", leaked, output)
+		}
+	}
+	for _, kept := range []string{redactedValue, "attempt 2", "short abc"} {
+		if !strings.Contains(output, kept) {
+			t.Errorf("Expected output to contain '%s', got:\n%s", kept, output)
+		}
+	}
+}
+
+// TestIsSensitiveKey tests that only keys naming a credential are redacted.
+func TestIsSensitiveKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected bool
+	}{
+		{"Authorization", true},
+		{"cookie", true},
+		{"session_cookie", true},
+		{"X-Auth-Token", true},
+		{"apiToken", true},
+		{"ACCESS_TOKEN", true},
+		{"client.secret", true},
+		{"password", true},
+		{"tokens", false},
+		{"token_count", false},
+		{"max_tokens", false},
+		{"cookie_jar_size", false},
+		{"secretary", false},
+		{"url", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := isSensitiveKey(tt.key); got != tt.expected {
+				t.Errorf("Expected isSensitiveKey(%q) to be %v, got %v", tt.key, tt.expected, got)
+			}
+		})
+	}
+}
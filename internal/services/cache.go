@@ -0,0 +1,266 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Cache file naming. Each cached course has a body file with the raw boot API
+// response and a metadata file with the validators used for revalidation.
+// Both are named after the share ID and a hash of the API base URL, as the
+// same share ID on different hosts identifies different courses.
+const (
+	cacheBodySuffix = ".json"
+	cacheMetaSuffix = ".meta.json"
+	// cacheHostHashLen is the number of hex digits of the base URL hash in file names
+	cacheHostHashLen = 16
+)
+
+// cacheKeyRegex restricts cache keys to the characters allowed in share IDs,
+// which keeps them safe to use as file names.
+var cacheKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// CacheEntry describes a cached boot API response.
+type CacheEntry struct {
+	// ShareID is the share ID the response was fetched for
+	ShareID string `json:"shareId"`
+	// BaseURL is the API base URL the share ID was resolved against
+	BaseURL string `json:"baseUrl"`
+	// ETag is the entity tag returned by the API, used for If-None-Match
+	ETag string `json:"etag,omitempty"`
+	// LastModified is the Last-Modified header returned by the API, used for If-Modified-Since
+	LastModified string `json:"lastModified,omitempty"`
+	// FetchedAt is when the response was last downloaded or revalidated
+	FetchedAt time.Time `json:"fetchedAt"`
+	// Size is the size of the cached response body in bytes
+	Size int64 `json:"size"`
+}
+
+// CourseCache stores raw course responses on disk, keyed by API base URL and
+// share ID, so that unchanged courses are not downloaded again and can be
+// served offline.
+type CourseCache struct {
+	// Dir is the directory the cache files are stored in
+	Dir string
+}
+
+// NewCourseCache creates a cache backed by the given directory.
+// The directory is created on the first write.
+func NewCourseCache(dir string) *CourseCache {
+	return &CourseCache{Dir: dir}
+}
+
+// Load returns the cached entry and response body for a share ID fetched
+// from the API at baseURL.
+// It returns an error wrapping fs.ErrNotExist if the course is not cached.
+func (c *CourseCache) Load(baseURL, shareID string) (*CacheEntry, []byte, error) {
+	if err := validateCacheKey(shareID); err != nil {
+		return nil, nil, err
+	}
+	return c.load(cacheFileKey(baseURL, shareID))
+}
+
+// load reads the cache files with the given key.
+func (c *CourseCache) load(key string) (*CacheEntry, []byte, error) {
+	meta, err := os.ReadFile(c.metaPath(key))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(meta, &entry); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse cache entry: %w", err)
+	}
+
+	body, err := os.ReadFile(c.bodyPath(key))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read cached response: %w", err)
+	}
+
+	return &entry, body, nil
+}
+
+// Store writes a response body and its entry to the cache, replacing any
+// previous entry for the same base URL and share ID.
+func (c *CourseCache) Store(entry *CacheEntry, body []byte) error {
+	if err := validateCacheKey(entry.ShareID); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.Dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	entry.Size = int64(len(body))
+	meta, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	// Write the body before the metadata so that a reader never finds an
+	// entry whose body is missing.
+	key := cacheFileKey(entry.BaseURL, entry.ShareID)
+	if err := writeFileAtomic(c.bodyPath(key), body); err != nil {
+		return fmt.Errorf("failed to write cached response: %w", err)
+	}
+	if err := writeFileAtomic(c.metaPath(key), meta); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// List returns all cached entries sorted by share ID and base URL.
+// A missing cache directory is reported as an empty cache.
+func (c *CourseCache) List() ([]CacheEntry, error) {
+	files, err := os.ReadDir(c.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	var entries []CacheEntry
+	for _, f := range files {
+		key, ok := strings.CutSuffix(f.Name(), cacheMetaSuffix)
+		if !ok || f.IsDir() || !isCacheFileKey(key) {
+			continue
+		}
+
+		entry, _, err := c.load(key)
+		if err != nil {
+			continue // Skip incomplete or corrupt entries; Purge can remove them
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].ShareID != entries[j].ShareID {
+			return entries[i].ShareID < entries[j].ShareID
+		}
+		return entries[i].BaseURL < entries[j].BaseURL
+	})
+	return entries, nil
+}
+
+// Purge removes the cache files for a share ID on every host, or all cached
+// courses if shareID is empty. It returns the number of courses removed.
+func (c *CourseCache) Purge(shareID string) (int, error) {
+	if shareID != "" {
+		if err := validateCacheKey(shareID); err != nil {
+			return 0, err
+		}
+	}
+
+	files, err := os.ReadDir(c.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	removed := 0
+	for _, f := range files {
+		key, ok := strings.CutSuffix(f.Name(), cacheMetaSuffix)
+		if !ok || f.IsDir() || !isCacheFileKey(key) {
+			continue
+		}
+		if id, _, _ := strings.Cut(key, "."); shareID != "" && id != shareID {
+			continue
+		}
+		n, err := c.remove(key)
+		if err != nil {
+			return removed, err
+		}
+		removed += n
+	}
+	return removed, nil
+}
+
+// remove deletes both cache files with the given key. It returns 1 if an
+// entry existed and 0 otherwise.
+func (c *CourseCache) remove(key string) (int, error) {
+	removed := 0
+	for _, path := range []string{c.metaPath(key), c.bodyPath(key)} {
+		err := os.Remove(path)
+		switch {
+		case err == nil:
+			removed = 1
+		case !errors.Is(err, fs.ErrNotExist):
+			return 0, fmt.Errorf("failed to remove cache file: %w", err)
+		}
+	}
+	return removed, nil
+}
+
+// bodyPath returns the path of the cached response body with the given key.
+func (c *CourseCache) bodyPath(key string) string {
+	return filepath.Join(c.Dir, key+cacheBodySuffix)
+}
+
+// metaPath returns the path of the cache metadata with the given key.
+func (c *CourseCache) metaPath(key string) string {
+	return filepath.Join(c.Dir, key+cacheMetaSuffix)
+}
+
+// cacheFileKey returns the file name key of a cached course: the share ID
+// and a hash of the API base URL, separated by a dot, which share IDs cannot
+// contain. The share ID must have been validated with validateCacheKey.
+func cacheFileKey(baseURL, shareID string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSuffix(baseURL, "/")))
+	return shareID + "." + hex.EncodeToString(sum[:])[:cacheHostHashLen]
+}
+
+// isCacheFileKey reports whether key has the form built by cacheFileKey.
+func isCacheFileKey(key string) bool {
+	shareID, hash, ok := strings.Cut(key, ".")
+	if !ok || validateCacheKey(shareID) != nil || len(hash) != cacheHostHashLen {
+		return false
+	}
+	_, err := hex.DecodeString(hash)
+	return err == nil
+}
+
+// validateCacheKey ensures a share ID can safely be used as a cache file name.
+func validateCacheKey(shareID string) error {
+	if !cacheKeyRegex.MatchString(shareID) {
+		return fmt.Errorf("invalid share ID for cache: %q", shareID)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file in the target directory and
+// renames it into place, so readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// testCacheBaseURL is the API base URL of the entries stored by the cache tests.
+const testCacheBaseURL = "https://rise.articulate.com"
+
+// TestCourseCache_StoreLoad tests storing and loading cache entries.
+func TestCourseCache_StoreLoad(t *testing.T) {
+	cache := NewCourseCache(filepath.Join(t.TempDir(), "nested", "cache"))
+
+	if _, _, err := cache.Load(testCacheBaseURL, "missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Expected fs.ErrNotExist for missing entry, got %v", err)
+	}
+
+	fetchedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	entry := &CacheEntry{ShareID: "abc_123", BaseURL: testCacheBaseURL, ETag: `"v1"`, LastModified: "Wed, 01 Jan 2025 12:00:00 GMT", FetchedAt: fetchedAt}
+	if err := cache.Store(entry, []byte(`{"shareId":"abc_123"}`)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	loaded, body, err := cache.Load(testCacheBaseURL, "abc_123")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if string(body) != `{"shareId":"abc_123"}` {
+		t.Errorf("Expected cached body, got '%s'", body)
+	}
+	if loaded.ETag != `"v1"` || loaded.LastModified != entry.LastModified {
+		t.Errorf("Expected validators to round-trip, got %+v", loaded)
+	}
+	if !loaded.FetchedAt.Equal(fetchedAt) {
+		t.Errorf("Expected FetchedAt %v, got %v", fetchedAt, loaded.FetchedAt)
+	}
+	if loaded.Size != int64(len(body)) {
+		t.Errorf("Expected size %d, got %d", len(body), loaded.Size)
+	}
+}
+
+// TestCourseCache_InvalidKey tests that share IDs cannot escape the cache directory.
+func TestCourseCache_InvalidKey(t *testing.T) {
+	cache := NewCourseCache(t.TempDir())
+
+	for _, key := range []string{"", "../escape", "a/b", "a.b"} {
+		if err := cache.Store(&CacheEntry{ShareID: key}, []byte("{}")); err == nil {
+			t.Errorf("Expected error storing key %q", key)
+		}
+		if _, _, err := cache.Load(testCacheBaseURL, key); err == nil || !strings.Contains(err.Error(), "invalid share ID") {
+			t.Errorf("Expected invalid share ID error loading key %q, got %v", key, err)
+		}
+		if _, err := cache.Purge(key); key != "" && err == nil {
+			t.Errorf("Expected error purging key %q", key)
+		}
+	}
+}
+
+// TestCourseCache_ListPurge tests listing and purging cached courses.
+func TestCourseCache_ListPurge(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewCourseCache(dir)
+
+	entries, err := NewCourseCache(filepath.Join(dir, "missing")).List()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Expected empty list for missing directory, got %v, %v", entries, err)
+	}
+
+	for _, id := range []string{"zeta", "alpha", "mid"} {
+		if err := cache.Store(&CacheEntry{ShareID: id, BaseURL: testCacheBaseURL}, []byte("{}")); err != nil {
+			t.Fatalf("Failed to store %s: %v", id, err)
+		}
+	}
+	if err := cache.Store(&CacheEntry{ShareID: "mid", BaseURL: "https://rise.example.com"}, []byte("{}")); err != nil {
+		t.Fatalf("Failed to store mid on a second host: %v", err)
+	}
+	// Unrelated files in the cache directory are ignored
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	entries, err = cache.List()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.ShareID)
+	}
+	if strings.Join(ids, ",") != "alpha,mid,mid,zeta" {
+		t.Errorf("Expected sorted entries alpha,mid,mid,zeta, got %v", ids)
+	}
+
+	// Purging a share ID removes it on every host
+	removed, err := cache.Purge("mid")
+	if err != nil || removed != 2 {
+		t.Errorf("Expected 2 entries removed, got %d, %v", removed, err)
+	}
+	removed, err = cache.Purge("mid")
+	if err != nil || removed != 0 {
+		t.Errorf("Expected 0 entries removed for missing entry, got %d, %v", removed, err)
+	}
+
+	removed, err = cache.Purge("")
+	if err != nil || removed != 2 {
+		t.Errorf("Expected 2 entries removed, got %d, %v", removed, err)
+	}
+	if entries, _ := cache.List(); len(entries) != 0 {
+		t.Errorf("Expected empty cache after purge, got %v", entries)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Errorf("Expected unrelated file to survive purge, got %v", err)
+	}
+}
+
+// TestCourseCache_Hosts tests that the same share ID on different API base
+// URLs is cached separately.
+func TestCourseCache_Hosts(t *testing.T) {
+	cache := NewCourseCache(t.TempDir())
+
+	for _, baseURL := range []string{"https://rise.articulate.com", "https://rise.example.com"} {
+		if err := cache.Store(&CacheEntry{ShareID: "shared", BaseURL: baseURL}, []byte(baseURL)); err != nil {
+			t.Fatalf("Failed to store entry for %s: %v", baseURL, err)
+		}
+	}
+
+	for _, baseURL := range []string{"https://rise.articulate.com", "https://rise.example.com"} {
+		entry, body, err := cache.Load(baseURL, "shared")
+		if err != nil {
+			t.Fatalf("Expected entry for %s, got: %v", baseURL, err)
+		}
+		if string(body) != baseURL || entry.BaseURL != baseURL {
+			t.Errorf("Expected the response cached for %s, got '%s' from %s", baseURL, body, entry.BaseURL)
+		}
+	}
+
+	if _, _, err := cache.Load("https://other.example.com", "shared"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected fs.ErrNotExist for another host, got %v", err)
+	}
+}
+
+// TestArticulateParser_FetchCourse_Cache tests conditional revalidation of cached courses.
+func TestArticulateParser_FetchCourse_Cache(t *testing.T) {
+	const etag = `"rev-1"`
+	var calls, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Wed, 01 Jan 2025 12:00:00 GMT")
+		_ = json.NewEncoder(w).Encode(&models.Course{ShareID: "cached", Course: models.CourseInfo{Title: "Cached Course"}})
+	}))
+	defer server.Close()
+
+	parser := newRetryTestParser(server.URL, 0)
+	parser.Cache = NewCourseCache(t.TempDir())
+	uri := "https://rise.articulate.com/share/cached"
+
+	for i := range 2 {
+		course, err := parser.FetchCourse(context.Background(), uri)
+		if err != nil {
+			t.Fatalf("Fetch %d: expected no error, got: %v", i+1, err)
+		}
+		if course.Course.Title != "Cached Course" {
+			t.Errorf("Fetch %d: expected title 'Cached Course', got '%s'", i+1, course.Course.Title)
+		}
+	}
+
+	if calls.Load() != 2 || notModified.Load() != 1 {
+		t.Errorf("Expected 2 requests with 1 revalidated, got %d requests and %d revalidated", calls.Load(), notModified.Load())
+	}
+
+	entry, _, err := parser.Cache.Load(server.URL, "cached")
+	if err != nil {
+		t.Fatalf("Expected cache entry, got: %v", err)
+	}
+	if entry.ETag != etag || entry.LastModified != "Wed, 01 Jan 2025 12:00:00 GMT" {
+		t.Errorf("Expected validators to be cached, got %+v", entry)
+	}
+
+	// Offline mode serves the cached course without a request
+	parser.Offline = true
+	course, err := parser.FetchCourse(context.Background(), uri)
+	if err != nil {
+		t.Fatalf("Expected no error in offline mode, got: %v", err)
+	}
+	if course.Course.Title != "Cached Course" {
+		t.Errorf("Expected cached title in offline mode, got '%s'", course.Course.Title)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected no request in offline mode, got %d requests", calls.Load())
+	}
+}
+
+// TestArticulateParser_FetchCourse_Offline tests offline mode without cached data.
+func TestArticulateParser_FetchCourse_Offline(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	parser := newRetryTestParser(server.URL, 0)
+	parser.Offline = true
+	uri := "https://rise.articulate.com/share/uncached"
+
+	_, err := parser.FetchCourse(context.Background(), uri)
+	if err == nil || !strings.Contains(err.Error(), "offline mode requires a cache directory") {
+		t.Errorf("Expected missing cache directory error, got %v", err)
+	}
+
+	parser.Cache = NewCourseCache(t.TempDir())
+	_, err = parser.FetchCourse(context.Background(), uri)
+	if err == nil || !strings.Contains(err.Error(), "not in the cache") {
+		t.Errorf("Expected cache miss error, got %v", err)
+	}
+
+	if calls.Load() != 0 {
+		t.Errorf("Expected no requests in offline mode, got %d", calls.Load())
+	}
+}
+
+// TestArticulateParser_FetchCourse_UnconditionalNotModified tests that a 304
+// response to an unconditional request is treated as an error.
+func TestArticulateParser_FetchCourse_UnconditionalNotModified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	parser := newRetryTestParser(server.URL, 0)
+	_, err := parser.FetchCourse(context.Background(), "https://rise.articulate.com/share/stale")
+	if err == nil || !strings.Contains(err.Error(), "API returned status 304") {
+		t.Errorf("Expected 304 error, got %v", err)
+	}
+}
@@ -0,0 +1,639 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+)
+
+// Markdown flavors produced by ToMarkdown and ToMarkdownInline.
+const (
+	// MarkdownGFM is GitHub Flavored Markdown, with tables and strikethrough
+	MarkdownGFM = "gfm"
+	// MarkdownCommonMark is strict CommonMark; tables are kept as HTML
+	MarkdownCommonMark = "commonmark"
+	// MarkdownPlain is plain text that keeps paragraphs, lists and link
+	// targets but no Markdown markup
+	MarkdownPlain = "plain"
+)
+
+// hardBreak marks a <br> in rendered inline content until the lines are
+// joined, so that it survives whitespace collapsing.
+const hardBreak = "\x00"
+
+// markdownBlockElements are the elements rendered as blocks of their own.
+var markdownBlockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "figcaption": true,
+	"figure": true, "footer": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "header": true, "hr": true,
+	"li": true, "main": true, "nav": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "ul": true,
+}
+
+var (
+	// markdownEscaper escapes the characters that start inline markup
+	markdownEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`)
+	// gfmEscaper also escapes strikethrough
+	gfmEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, "~", `\~`)
+	// lineStartPattern matches text at the start of a line that would be read
+	// as a heading, list item, block quote or setext underline
+	lineStartPattern = regexp.MustCompile(`^(#{1,6}(\s|$)|[-+](\s|$)|>|=+\s*$|-+\s*$)`)
+	// orderedStartPattern matches text at the start of a line that would be
+	// read as an ordered list item
+	orderedStartPattern = regexp.MustCompile(`^(\d{1,9})([.)])(\s|$)`)
+)
+
+// ValidateMarkdownFlavor checks that flavor is one of the Markdown flavors.
+// The empty string is accepted and means MarkdownGFM.
+//
+// Parameters:
+//   - flavor: The flavor name
+//
+// Returns:
+//   - An error if the flavor is unknown
+func ValidateMarkdownFlavor(flavor string) error {
+	switch flavor {
+	case "", MarkdownGFM, MarkdownCommonMark, MarkdownPlain:
+		return nil
+	}
+	return fmt.Errorf("unknown markdown flavor %q (expected %s, %s or %s)",
+		flavor, MarkdownGFM, MarkdownCommonMark, MarkdownPlain)
+}
+
+// ToMarkdown converts HTML to Markdown, keeping paragraphs, headings, bold
+// and italic text, links, images, inline code, code blocks, block quotes,
+// nested lists, line breaks and tables. Script and style tags are skipped.
+//
+// Parameters:
+//   - htmlStr: The HTML to convert
+//   - flavor: MarkdownGFM, MarkdownCommonMark or MarkdownPlain; empty means MarkdownGFM
+//
+// Returns:
+//   - The Markdown text, with blocks separated by blank lines
+func (h *HTMLCleaner) ToMarkdown(htmlStr, flavor string) string {
+	body := parseHTMLBody(htmlStr)
+	if body == nil {
+		return ""
+	}
+	w := &markdownWriter{flavor: flavor}
+	return joinMarkdownBlocks(w.blocks(body))
+}
+
+// ToMarkdownInline converts HTML to a single line of Markdown, for headings,
+// titles and other places where blocks cannot be used. Blocks and line
+// breaks become spaces; inline formatting and links are kept.
+//
+// Parameters:
+//   - htmlStr: The HTML to convert
+//   - flavor: MarkdownGFM, MarkdownCommonMark or MarkdownPlain; empty means MarkdownGFM
+//
+// Returns:
+//   - The Markdown text on a single line
+func (h *HTMLCleaner) ToMarkdownInline(htmlStr, flavor string) string {
+	body := parseHTMLBody(htmlStr)
+	if body == nil {
+		return ""
+	}
+	w := &markdownWriter{flavor: flavor}
+	return w.line(w.inlineChildren(body), " ")
+}
+
+// parseHTMLBody parses an HTML fragment and returns its body element.
+func parseHTMLBody(htmlStr string) *html.Node {
+	doc, err := html.Parse(strings.NewReader(htmlStr))
+	if err != nil {
+		return nil
+	}
+	return findElement(doc, "body")
+}
+
+// findElement returns the first element named name in the tree below n.
+func findElement(n *html.Node, name string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == name {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// markdownBlock is a rendered block of Markdown.
+type markdownBlock struct {
+	text string
+	// list marks lists, which may follow a list item's text without a blank line
+	list bool
+}
+
+// joinMarkdownBlocks separates blocks with blank lines.
+func joinMarkdownBlocks(blocks []markdownBlock) string {
+	texts := make([]string, len(blocks))
+	for i, block := range blocks {
+		texts[i] = block.text
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// markdownWriter renders an HTML node tree as Markdown of one flavor.
+type markdownWriter struct {
+	flavor string
+}
+
+// markup reports whether the flavor uses Markdown markup.
+func (w *markdownWriter) markup() bool {
+	return w.flavor != MarkdownPlain
+}
+
+// blocks renders the children of n as blocks. Runs of inline content between
+// block elements become paragraphs.
+func (w *markdownWriter) blocks(n *html.Node) []markdownBlock {
+	var blocks []markdownBlock
+	var inline strings.Builder
+	flush := func() {
+		if text := w.paragraph(inline.String()); text != "" {
+			blocks = append(blocks, markdownBlock{text: text})
+		}
+		inline.Reset()
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && markdownBlockElements[c.Data] {
+			flush()
+			blocks = append(blocks, w.block(c)...)
+			continue
+		}
+		inline.WriteString(w.inline(c))
+	}
+	flush()
+	return blocks
+}
+
+// block renders a block element.
+func (w *markdownWriter) block(n *html.Node) []markdownBlock {
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := w.line(w.inlineChildren(n), " ")
+		if text == "" {
+			return nil
+		}
+		if w.markup() {
+			text = strings.Repeat("#", int(n.Data[1]-'0')) + " " + text
+		}
+		return []markdownBlock{{text: text}}
+	case "ul", "ol":
+		if text := w.list(n); text != "" {
+			return []markdownBlock{{text: text, list: true}}
+		}
+		return nil
+	case "blockquote":
+		text := joinMarkdownBlocks(w.blocks(n))
+		if text == "" {
+			return nil
+		}
+		if w.markup() {
+			text = prefixLines(text, "> ", ">")
+		}
+		return []markdownBlock{{text: text}}
+	case "pre":
+		return w.codeBlock(n)
+	case "hr":
+		if w.markup() {
+			return []markdownBlock{{text: "---"}}
+		}
+		return nil
+	case "table":
+		if text := w.table(n); text != "" {
+			return []markdownBlock{{text: text}}
+		}
+		return nil
+	default:
+		return w.blocks(n)
+	}
+}
+
+// list renders a bulleted or numbered list. Items holding more than one
+// paragraph make the list loose, separating its items with blank lines.
+func (w *markdownWriter) list(n *html.Node) string {
+	number := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil && n.Data == "ol" {
+		number = start
+	}
+
+	var items []string
+	loose := false
+	indent := ""
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode {
+			continue
+		}
+		var blocks []markdownBlock
+		if li.Data == "li" {
+			blocks = w.blocks(li)
+		} else {
+			// Lists and other elements misplaced directly inside the list
+			// belong to the item before them
+			blocks = w.block(li)
+			if len(blocks) == 0 {
+				continue
+			}
+			if len(items) > 0 {
+				items[len(items)-1] += "\n" + indentLines(joinMarkdownBlocks(blocks), indent, true)
+				continue
+			}
+		}
+
+		marker := "- "
+		if n.Data == "ol" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		if checkbox := findElement(li, "input"); checkbox != nil && attr(checkbox, "type") == "checkbox" {
+			if hasAttr(checkbox, "checked") {
+				marker += "[x] "
+			} else {
+				marker += "[ ] "
+			}
+		}
+
+		indent = strings.Repeat(" ", len(marker))
+
+		var text strings.Builder
+		for i, block := range blocks {
+			if i > 0 {
+				if block.list {
+					text.WriteString("\n")
+				} else {
+					text.WriteString("\n\n")
+					loose = true
+				}
+			}
+			text.WriteString(block.text)
+		}
+		items = append(items, strings.TrimRight(marker+indentLines(text.String(), indent, false), " "))
+	}
+
+	if loose {
+		return strings.Join(items, "\n\n")
+	}
+	return strings.Join(items, "\n")
+}
+
+// codeBlock renders a preformatted block as a fenced code block.
+func (w *markdownWriter) codeBlock(n *html.Node) []markdownBlock {
+	code := strings.TrimRight(textContent(n), "\n")
+	if strings.TrimSpace(code) == "" {
+		return nil
+	}
+	if !w.markup() {
+		return []markdownBlock{{text: code}}
+	}
+
+	language := ""
+	if el := findElement(n, "code"); el != nil {
+		for _, class := range strings.Fields(attr(el, "class")) {
+			if name, ok := strings.CutPrefix(class, "language-"); ok {
+				language = name
+				break
+			}
+		}
+	}
+	fence := strings.Repeat("`", max(3, longestRun(code, '`')+1))
+	return []markdownBlock{{text: fence + language + "\n" + code + "\n" + fence}}
+}
+
+// table renders a table. GFM uses a pipe table whose first row is the
+// header, CommonMark keeps the table as HTML, and plain text separates the
+// cells with vertical bars.
+func (w *markdownWriter) table(n *html.Node) string {
+	if w.flavor == MarkdownCommonMark {
+		var buf bytes.Buffer
+		if err := html.Render(&buf, n); err != nil {
+			return ""
+		}
+		// A blank line would end the HTML block
+		var lines []string
+		for line := range strings.SplitSeq(buf.String(), "\n") {
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, line)
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	var rows [][]string
+	columns := 0
+	var collect func(*html.Node)
+	collect = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if c.Data != "tr" {
+				collect(c)
+				continue
+			}
+			var row []string
+			for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") {
+					row = append(row, w.cell(cell))
+				}
+			}
+			rows = append(rows, row)
+			columns = max(columns, len(row))
+		}
+	}
+	collect(n)
+	if columns == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(rows)+1)
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		if !w.markup() {
+			lines = append(lines, strings.TrimSpace(strings.Join(row, " | ")))
+			continue
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", columns))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cell renders a table cell on a single line.
+func (w *markdownWriter) cell(n *html.Node) string {
+	if !w.markup() {
+		return w.line(w.inlineChildren(n), " ")
+	}
+	return strings.ReplaceAll(w.line(w.inlineChildren(n), "<br>"), "|", `\|`)
+}
+
+// paragraph finishes inline content as a paragraph, escaping the start of
+// each line where it would otherwise be read as block markup.
+func (w *markdownWriter) paragraph(inline string) string {
+	if !w.markup() {
+		return w.line(inline, "\n")
+	}
+	lines := strings.Split(w.line(inline, hardBreak), hardBreak)
+	for i, line := range lines {
+		if lineStartPattern.MatchString(line) {
+			line = `\` + line
+		}
+		lines[i] = orderedStartPattern.ReplaceAllString(line, `$1\$2$3`)
+	}
+	return strings.Join(lines, "\\\n")
+}
+
+// line collapses the whitespace of inline content and joins its lines with
+// sep.
+func (w *markdownWriter) line(inline, sep string) string {
+	lines := strings.Split(inline, hardBreak)
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, sep)
+}
+
+// inlineChildren renders the children of n as inline content.
+func (w *markdownWriter) inlineChildren(n *html.Node) string {
+	var buf strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		buf.WriteString(w.inline(c))
+	}
+	return buf.String()
+}
+
+// inline renders a node as inline content. Whitespace is collapsed later, so
+// text keeps its spaces here. Blocks and table cells inside inline content
+// are separated by spaces.
+func (w *markdownWriter) inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return w.escape(n.Data)
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.Data {
+	case "script", "style", "head", "title", "input":
+		return ""
+	case "br":
+		return hardBreak
+	case "strong", "b":
+		return w.emphasis(n, "**")
+	case "em", "i":
+		return w.emphasis(n, "*")
+	case "del", "s", "strike":
+		if w.flavor == MarkdownGFM || w.flavor == "" {
+			return w.emphasis(n, "~~")
+		}
+		return w.inlineChildren(n)
+	case "code", "kbd", "samp", "tt":
+		return w.code(n)
+	case "a":
+		return w.link(n)
+	case "img":
+		return w.image(n)
+	}
+
+	if markdownBlockElements[n.Data] || n.Data == "tr" || n.Data == "td" || n.Data == "th" {
+		return " " + w.inlineChildren(n) + " "
+	}
+	return w.inlineChildren(n)
+}
+
+// emphasis wraps the content of n in delimiters. Spaces at the edges are
+// moved outside, since delimiters next to spaces are not emphasis.
+func (w *markdownWriter) emphasis(n *html.Node, delimiter string) string {
+	content := w.inlineChildren(n)
+	if !w.markup() {
+		return content
+	}
+	trimmed := strings.TrimLeftFunc(content, unicode.IsSpace)
+	inner := strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	if inner == "" {
+		return content
+	}
+	leading := content[:len(content)-len(trimmed)]
+	trailing := trimmed[len(inner):]
+	return leading + delimiter + inner + delimiter + trailing
+}
+
+// code renders inline code as a code span.
+func (w *markdownWriter) code(n *html.Node) string {
+	code := strings.Join(strings.Fields(textContent(n)), " ")
+	if !w.markup() || code == "" {
+		return code
+	}
+	fence := strings.Repeat("`", longestRun(code, '`')+1)
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		code = " " + code + " "
+	}
+	return fence + code + fence
+}
+
+// link renders a link. Links whose text is their address become autolinks;
+// plain text shows the address after the text.
+func (w *markdownWriter) link(n *html.Node) string {
+	text := w.inlineChildren(n)
+	href := strings.TrimSpace(attr(n, "href"))
+	if href == "" || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+		return text
+	}
+
+	plain := strings.Join(strings.Fields(textContent(n)), " ")
+	if !w.markup() {
+		switch {
+		case strings.HasPrefix(href, "#"):
+			return text
+		case plain == "" || plain == href:
+			return href
+		default:
+			return text + " (" + href + ")"
+		}
+	}
+
+	if (plain == "" || plain == href) && strings.Contains(href, ":") && !strings.ContainsAny(href, " <>") {
+		return "<" + href + ">"
+	}
+	if strings.TrimSpace(text) == "" {
+		text = w.escape(href)
+	}
+	return "[" + strings.TrimSpace(text) + "](" + linkDestination(href) + linkTitle(attr(n, "title")) + ")"
+}
+
+// image renders an image; plain text keeps only its alternative text.
+func (w *markdownWriter) image(n *html.Node) string {
+	src := strings.TrimSpace(attr(n, "src"))
+	alt := attr(n, "alt")
+	if !w.markup() || src == "" {
+		return alt
+	}
+	return "![" + w.escape(alt) + "](" + linkDestination(src) + linkTitle(attr(n, "title")) + ")"
+}
+
+// escape escapes text so that it is not read as Markdown markup.
+func (w *markdownWriter) escape(text string) string {
+	switch w.flavor {
+	case MarkdownPlain:
+		return text
+	case MarkdownCommonMark:
+		return markdownEscaper.Replace(text)
+	default:
+		return gfmEscaper.Replace(text)
+	}
+}
+
+// linkDestination formats a URL as a link destination, enclosing it in angle
+// brackets if it contains spaces or parentheses.
+func linkDestination(href string) string {
+	if strings.ContainsAny(href, " ()<>") {
+		return "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(href) + ">"
+	}
+	return href
+}
+
+// linkTitle formats a link title, or returns "" if there is none.
+func linkTitle(title string) string {
+	if title = strings.Join(strings.Fields(title), " "); title == "" {
+		return ""
+	}
+	return ` "` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(title) + `"`
+}
+
+// textContent returns the text below n without any markup; <br> becomes a
+// line break.
+func textContent(n *html.Node) string {
+	var buf strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			buf.WriteString(n.Data)
+		case n.Type == html.ElementNode && n.Data == "br":
+			buf.WriteString("\n")
+		case n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style"):
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return buf.String()
+}
+
+// attr returns the value of an attribute of n, or "" if it is not set.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// hasAttr reports whether n has an attribute, whatever its value.
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// longestRun returns the length of the longest run of r in s.
+func longestRun(s string, r rune) int {
+	longest, run := 0, 0
+	for _, c := range s {
+		if c == r {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
+
+// prefixLines prefixes each line of text; empty lines get emptyPrefix.
+func prefixLines(text, prefix, emptyPrefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = emptyPrefix
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentLines indents the lines of text, except empty lines and, unless
+// first is set, the first line.
+func indentLines(text, indent string, first bool) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" && (i > 0 || first) {
+			lines[i] = indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestHTMLCleaner_ToMarkdown tests converting HTML to GitHub Flavored Markdown.
+func TestHTMLCleaner_ToMarkdown(t *testing.T) {
+	cleaner := NewHTMLCleaner()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "empty string",
+			input:    "",
+			expected: "",
+		},
+		{
+			name:     "plain text",
+			input:    "Just text",
+			expected: "Just text",
+		},
+		{
+			name:     "paragraphs",
+			input:    "<p>First</p>\n<p>Second</p><p>&nbsp;</p>",
+			expected: "First\n\nSecond",
+		},
+		{
+			name:     "bold and italics",
+			input:    "<p><strong>bold</strong>, <b>b</b>, <em>italic</em> and <i> spaced </i>text</p>",
+			expected: "**bold**, **b**, *italic* and *spaced* text",
+		},
+		{
+			name:     "links",
+			input:    `<p>See <a href="https://example.com/docs" title="The docs">the docs</a> or <a href="https://example.com">https://example.com</a></p>`,
+			expected: `See [the docs](https://example.com/docs "The docs") or <https://example.com>`,
+		},
+		{
+			name:     "link with spaces and parentheses",
+			input:    `<a href="files/a (1).pdf">PDF</a>`,
+			expected: "[PDF](<files/a (1).pdf>)",
+		},
+		{
+			name:     "script link",
+			input:    `<a href="javascript:alert(1)">Click</a>`,
+			expected: "Click",
+		},
+		{
+			name:     "inline code",
+			input:    "<p>Run <code>go  test</code> or <code>a`b</code></p>",
+			expected: "Run `go test` or ``a`b``",
+		},
+		{
+			name:     "line breaks",
+			input:    "<p>Line 1<br>Line 2<br/></p>",
+			expected: "Line 1\\\nLine 2",
+		},
+		{
+			name:     "headings",
+			input:    "<h2>Section <em>one</em></h2><p>Text</p>",
+			expected: "## Section *one*\n\nText",
+		},
+		{
+			name:     "nested lists",
+			input:    "<ul><li>One<ul><li>Nested</li></ul></li><li>Two</li></ul>",
+			expected: "- One\n  - Nested\n- Two",
+		},
+		{
+			name:     "ordered list with start",
+			input:    `<ol start="9"><li>Nine</li><li>Ten<ol><li>Sub</li></ol></li></ol>`,
+			expected: "9. Nine\n10. Ten\n    1. Sub",
+		},
+		{
+			name:     "loose list",
+			input:    "<ul><li><p>One</p><p>More</p></li><li>Two</li></ul>",
+			expected: "- One\n\n  More\n\n- Two",
+		},
+		{
+			name:     "task list",
+			input:    `<ul><li><input type="checkbox" checked> Done</li><li><input type="checkbox"> Todo</li></ul>`,
+			expected: "- [x] Done\n- [ ] Todo",
+		},
+		{
+			name:     "table",
+			input:    "<table><thead><tr><th>Term</th><th>Meaning</th></tr></thead><tbody><tr><td><b>A|B</b></td><td>One<br>Two</td></tr><tr><td>C</td></tr></tbody></table>",
+			expected: "| Term | Meaning |\n| --- | --- |\n| **A\\|B** | One<br>Two |\n| C |  |",
+		},
+		{
+			name:     "block quote",
+			input:    "<blockquote><p>Quoted</p><p>Twice</p></blockquote>",
+			expected: "> Quoted\n>\n> Twice",
+		},
+		{
+			name:     "code block",
+			input:    "<pre><code class=\"language-go\">x := 1\nfmt.Println(x)</code></pre>",
+			expected: "```go\nx := 1\nfmt.Println(x)\n```",
+		},
+		{
+			name:     "image",
+			input:    `<img src="https://example.com/a.png" alt="A chart">`,
+			expected: "![A chart](https://example.com/a.png)",
+		},
+		{
+			name:     "strikethrough",
+			input:    "<p><s>old</s> new</p>",
+			expected: "~~old~~ new",
+		},
+		{
+			name:     "escaped markup",
+			input:    "<p>2 * 3 = [six] with under_score and ~tilde</p>",
+			expected: `2 \* 3 = \[six\] with under\_score and \~tilde`,
+		},
+		{
+			name:     "escaped line starts",
+			input:    "<p>1. Not a list</p><p># Not a heading</p><p>- Not a bullet</p>",
+			expected: "1\\. Not a list\n\n\\# Not a heading\n\n\\- Not a bullet",
+		},
+		{
+			name:     "script and style skipped",
+			input:    "<p>Text<script>alert(1)</script><style>p{}</style></p>",
+			expected: "Text",
+		},
+		{
+			name:     "entities",
+			input:    "<p>AT&amp;T &lt;company&gt;</p>",
+			expected: `AT&T \<company>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := cleaner.ToMarkdown(tt.input, MarkdownGFM)
+			if result != tt.expected {
+				t.Errorf("Expected:\n%q\nGot:\n%q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestHTMLCleaner_ToMarkdown_Flavors tests the differences between the
+// Markdown flavors.
+func TestHTMLCleaner_ToMarkdown_Flavors(t *testing.T) {
+	cleaner := NewHTMLCleaner()
+
+	tests := []struct {
+		name     string
+		input    string
+		flavor   string
+		expected string
+	}{
+		{"default is gfm", "<p><del>old</del></p>", "", "~~old~~"},
+		{"commonmark strikethrough", "<p><del>old</del> ~x~</p>", MarkdownCommonMark, "old ~x~"},
+		{"commonmark table", "<table>\n<tr><td>A</td></tr>\n\n</table>", MarkdownCommonMark, "<table>\n<tbody><tr><td>A</td></tr>\n</tbody></table>"},
+		{"plain formatting", "<p><b>bold</b> *star* <code>x</code></p>", MarkdownPlain, "bold *star* x"},
+		{"plain link", `<p><a href="https://example.com">Example</a></p>`, MarkdownPlain, "Example (https://example.com)"},
+		{"plain bare link", `<a href="https://example.com">https://example.com</a>`, MarkdownPlain, "https://example.com"},
+		{"plain anchor", `<a href="#top">Top</a>`, MarkdownPlain, "Top"},
+		{"plain image", `<img src="a.png" alt="Chart">`, MarkdownPlain, "Chart"},
+		{"plain structure", "<h3>Title</h3><ol><li>One</li></ol><p>A<br>B</p>", MarkdownPlain, "Title\n\n1. One\n\nA\nB"},
+		{"plain table", "<table><tr><th>A</th><th>B</th></tr><tr><td>1</td><td>2</td></tr></table>", MarkdownPlain, "A | B\n1 | 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := cleaner.ToMarkdown(tt.input, tt.flavor)
+			if result != tt.expected {
+				t.Errorf("Expected:\n%q\nGot:\n%q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestHTMLCleaner_ToMarkdownInline tests converting HTML to a single line of
+// Markdown.
+func TestHTMLCleaner_ToMarkdownInline(t *testing.T) {
+	cleaner := NewHTMLCleaner()
+
+	tests := []struct {
+		name     string
+		input    string
+		flavor   string
+		expected string
+	}{
+		{"heading", "<h1>Course <em>title</em></h1>", MarkdownGFM, "Course *title*"},
+		{"paragraphs and breaks", "<p>One<br>two</p><p>three</p>", MarkdownGFM, "One two three"},
+		{"link", `<p>Read <a href="https://example.com">this</a></p>`, MarkdownGFM, "Read [this](https://example.com)"},
+		{"list", "<ul><li>A</li><li>B</li></ul>", MarkdownGFM, "A B"},
+		{"line start not escaped", "<p>1. Step</p>", MarkdownGFM, "1. Step"},
+		{"plain", `<p><b>Read</b> <a href="https://example.com">this</a></p>`, MarkdownPlain, "Read this (https://example.com)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := cleaner.ToMarkdownInline(tt.input, tt.flavor)
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestValidateMarkdownFlavor tests validating Markdown flavor names.
+func TestValidateMarkdownFlavor(t *testing.T) {
+	for _, flavor := range []string{"", MarkdownGFM, MarkdownCommonMark, MarkdownPlain} {
+		if err := ValidateMarkdownFlavor(flavor); err != nil {
+			t.Errorf("Expected flavor %q to be valid, got %v", flavor, err)
+		}
+	}
+
+	err := ValidateMarkdownFlavor("markdown")
+	if err == nil || !strings.Contains(err.Error(), `unknown markdown flavor "markdown"`) {
+		t.Errorf("Expected an unknown flavor error, got %v", err)
+	}
+}
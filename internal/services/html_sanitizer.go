@@ -0,0 +1,202 @@
+package services
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// sanitizerGlobalAttrs are the attributes kept on every allowed element.
+var sanitizerGlobalAttrs = map[string]bool{
+	"class": true, "dir": true, "lang": true, "style": true, "title": true,
+}
+
+// sanitizerElements are the elements SanitizeHTML keeps, with the attributes
+// they may have besides sanitizerGlobalAttrs. They cover the formatting the
+// Rise editor produces.
+var sanitizerElements = map[atom.Atom]map[string]bool{
+	atom.A: {"href": true, "target": true, "rel": true}, atom.Abbr: nil,
+	atom.Article: nil, atom.B: nil, atom.Blockquote: {"cite": true},
+	atom.Br: nil, atom.Caption: nil, atom.Cite: nil, atom.Code: nil,
+	atom.Col: {"span": true}, atom.Colgroup: {"span": true}, atom.Dd: nil,
+	atom.Del: nil, atom.Div: nil, atom.Dl: nil, atom.Dt: nil, atom.Em: nil,
+	atom.Figcaption: nil, atom.Figure: nil, atom.H1: nil, atom.H2: nil,
+	atom.H3: nil, atom.H4: nil, atom.H5: nil, atom.H6: nil, atom.Hr: nil,
+	atom.I: nil, atom.Img: {"src": true, "alt": true, "width": true, "height": true},
+	atom.Input: {"type": true, "checked": true}, atom.Ins: nil, atom.Kbd: nil,
+	atom.Li: {"value": true}, atom.Mark: nil, atom.Ol: {"start": true, "type": true, "reversed": true},
+	atom.P: nil, atom.Pre: nil, atom.Q: {"cite": true}, atom.S: nil,
+	atom.Section: nil, atom.Small: nil, atom.Span: nil, atom.Strike: nil,
+	atom.Strong: nil, atom.Sub: nil, atom.Sup: nil, atom.Table: nil,
+	atom.Tbody: nil, atom.Td: {"colspan": true, "rowspan": true},
+	atom.Tfoot: nil, atom.Th: {"colspan": true, "rowspan": true, "scope": true},
+	atom.Thead: nil, atom.Tr: nil, atom.U: nil, atom.Ul: nil,
+}
+
+// sanitizerDroppedElements are removed together with their content. Other
+// elements that are not allowed are replaced by their content.
+var sanitizerDroppedElements = map[atom.Atom]bool{
+	atom.Base: true, atom.Button: true, atom.Embed: true, atom.Form: true,
+	atom.Frame: true, atom.Frameset: true, atom.Iframe: true, atom.Link: true,
+	atom.Math: true, atom.Meta: true, atom.Noembed: true, atom.Noframes: true,
+	atom.Noscript: true, atom.Object: true, atom.Script: true, atom.Select: true,
+	atom.Style: true, atom.Svg: true, atom.Template: true, atom.Textarea: true,
+	atom.Title: true,
+}
+
+// sanitizerURLAttrs are the attributes holding URLs.
+var sanitizerURLAttrs = map[string]bool{"href": true, "src": true, "cite": true}
+
+// sanitizerSchemes are the URL schemes kept in links and images; URLs
+// without a scheme are relative and always kept.
+var sanitizerSchemes = map[string]bool{"http": true, "https": true, "mailto": true, "tel": true}
+
+// sanitizerStyleProperties are the CSS properties kept in style attributes.
+var sanitizerStyleProperties = map[string]bool{
+	"background-color": true, "color": true, "font-family": true, "font-size": true,
+	"font-style": true, "font-weight": true, "line-height": true, "margin-left": true,
+	"padding-left": true, "text-align": true, "text-decoration": true, "text-indent": true,
+	"vertical-align": true,
+}
+
+// styleValuePattern matches CSS values made of keywords, lengths, colours
+// and colour functions, which cannot load resources or run script.
+var styleValuePattern = regexp.MustCompile(`(?i)^(?:[\w\s#%.,'"+-]|(?:rgba?|hsla?)\([\d\s.,%]*\))*$`)
+
+// SanitizeHTML removes everything from HTML that could run script or load
+// content when the HTML is embedded in a page, keeping the formatting Rise
+// produces. Elements and attributes that are not on an allowlist are
+// dropped: scripts, styles, frames and forms with their content, other
+// elements keeping their text. Event handlers, javascript: and other
+// non-web URLs and CSS other than simple text styling are removed, and
+// links that open a new window get rel="noopener noreferrer".
+//
+// Parameters:
+//   - htmlStr: The untrusted HTML
+//
+// Returns:
+//   - The sanitized HTML
+func (h *HTMLCleaner) SanitizeHTML(htmlStr string) string {
+	body := parseHTMLBody(htmlStr)
+	if body == nil {
+		return html.EscapeString(htmlStr)
+	}
+
+	var buf bytes.Buffer
+	for c := body.FirstChild; c != nil; c = c.NextSibling {
+		for _, n := range sanitizeNode(c) {
+			// Rendering to a buffer only fails for malformed trees, which
+			// sanitizeNode does not build
+			_ = html.Render(&buf, n)
+		}
+	}
+	return buf.String()
+}
+
+// sanitizeNode returns the sanitized copies of a node: the node itself,
+// its sanitized children when the element is not allowed, or nothing.
+func sanitizeNode(n *html.Node) []*html.Node {
+	switch n.Type {
+	case html.TextNode:
+		return []*html.Node{{Type: html.TextNode, Data: n.Data}}
+	case html.ElementNode:
+	default:
+		// Comments and doctypes are dropped
+		return nil
+	}
+
+	if sanitizerDroppedElements[n.DataAtom] {
+		return nil
+	}
+	var children []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		children = append(children, sanitizeNode(c)...)
+	}
+	allowed, ok := sanitizerElements[n.DataAtom]
+	if !ok || (n.DataAtom == atom.Input && !isCheckbox(n)) {
+		return children
+	}
+
+	clean := &html.Node{Type: html.ElementNode, Data: n.Data, DataAtom: n.DataAtom}
+	for _, a := range n.Attr {
+		if a.Namespace != "" || !(sanitizerGlobalAttrs[a.Key] || allowed[a.Key]) {
+			continue
+		}
+		switch {
+		case sanitizerURLAttrs[a.Key]:
+			if !isSafeURL(a.Val) {
+				continue
+			}
+		case a.Key == "style":
+			a.Val = sanitizeStyle(a.Val)
+			if a.Val == "" {
+				continue
+			}
+		case a.Key == "target" || a.Key == "rel":
+			// Replaced below
+			continue
+		}
+		clean.Attr = append(clean.Attr, a)
+	}
+
+	switch n.DataAtom {
+	case atom.A:
+		if target := attr(n, "target"); target != "" {
+			clean.Attr = append(clean.Attr,
+				html.Attribute{Key: "target", Val: target},
+				html.Attribute{Key: "rel", Val: "noopener noreferrer"})
+		}
+	case atom.Input:
+		// Checkboxes are shown, never filled in
+		clean.Attr = append(clean.Attr, html.Attribute{Key: "disabled"})
+	}
+
+	for _, c := range children {
+		clean.AppendChild(c)
+	}
+	return []*html.Node{clean}
+}
+
+// isCheckbox reports whether an input element is a checkbox.
+func isCheckbox(n *html.Node) bool {
+	return strings.EqualFold(strings.TrimSpace(attr(n, "type")), "checkbox")
+}
+
+// isSafeURL reports whether a URL is relative or uses one of the
+// sanitizerSchemes. Whitespace and control characters are ignored, as
+// browsers ignore them in schemes such as "java\tscript:".
+func isSafeURL(rawURL string) bool {
+	u := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, rawURL)
+
+	colon := strings.IndexByte(u, ':')
+	if colon < 0 || strings.ContainsAny(u[:colon], "/?#") {
+		return true
+	}
+	return sanitizerSchemes[strings.ToLower(u[:colon])]
+}
+
+// sanitizeStyle returns the declarations of a style attribute that set one of
+// the sanitizerStyleProperties to a simple value.
+func sanitizeStyle(style string) string {
+	var kept []string
+	for _, decl := range strings.Split(style, ";") {
+		name, value, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if sanitizerStyleProperties[name] && value != "" && styleValuePattern.MatchString(value) {
+			kept = append(kept, name+": "+value)
+		}
+	}
+	return strings.Join(kept, "; ")
+}
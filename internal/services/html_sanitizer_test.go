@@ -0,0 +1,105 @@
+package services
+
+import "testing"
+
+// TestHTMLCleaner_SanitizeHTML tests removing script and other unsafe content
+// from HTML while keeping its formatting.
+func TestHTMLCleaner_SanitizeHTML(t *testing.T) {
+	cleaner := NewHTMLCleaner()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "empty string",
+			input:    "",
+			expected: "",
+		},
+		{
+			name:     "rise formatting kept",
+			input:    `<p class="rise-text">Some <strong>bold</strong>, <em>italic</em>, <u>underlined</u> and <s>struck</s> text<br>x<sup>2</sup></p>`,
+			expected: `<p class="rise-text">Some <strong>bold</strong>, <em>italic</em>, <u>underlined</u> and <s>struck</s> text<br/>x<sup>2</sup></p>`,
+		},
+		{
+			name:     "lists and tables kept",
+			input:    `<ol start="3"><li>One</li></ol><table><tbody><tr><th scope="col">A</th><td colspan="2">B</td></tr></tbody></table>`,
+			expected: `<ol start="3"><li>One</li></ol><table><tbody><tr><th scope="col">A</th><td colspan="2">B</td></tr></tbody></table>`,
+		},
+		{
+			name:     "script removed with content",
+			input:    `<p>Text<script>alert(1)</script></p><script src="https://evil.example/x.js"></script>`,
+			expected: `<p>Text</p>`,
+		},
+		{
+			name:     "event handlers removed",
+			input:    `<img src="https://example.com/a.png" alt="A" onerror="alert(1)"><p onclick="alert(1)" onmouseover="x()">Hi</p>`,
+			expected: `<img src="https://example.com/a.png" alt="A"/><p>Hi</p>`,
+		},
+		{
+			name:     "javascript urls removed",
+			input:    `<a href="javascript:alert(1)">A</a> <a href=" JaVa&#x09;Script:alert(1)">B</a> <img src="vbscript:x">`,
+			expected: `<a>A</a> <a>B</a> <img/>`,
+		},
+		{
+			name:     "data urls removed",
+			input:    `<a href="data:text/html;base64,PHNjcmlwdD4=">A</a>`,
+			expected: `<a>A</a>`,
+		},
+		{
+			name:     "safe urls kept",
+			input:    `<a href="https://example.com/a?b=1#c">A</a><a href="mailto:a@example.com">M</a><a href="../page.html">R</a><a href="#top">T</a>`,
+			expected: `<a href="https://example.com/a?b=1#c">A</a><a href="mailto:a@example.com">M</a><a href="../page.html">R</a><a href="#top">T</a>`,
+		},
+		{
+			name:     "new window links get noopener",
+			input:    `<a href="https://example.com" target="_blank" rel="opener">Go</a>`,
+			expected: `<a href="https://example.com" target="_blank" rel="noopener noreferrer">Go</a>`,
+		},
+		{
+			name:     "unknown elements unwrapped",
+			input:    `<p><font face="Arial">Old</font> <custom-tag>tag</custom-tag></p>`,
+			expected: `<p>Old tag</p>`,
+		},
+		{
+			name:     "frames, forms and styles removed",
+			input:    `<iframe src="https://evil.example"></iframe><form><button>Go</button></form><style>p{}</style><svg><script>x</script></svg>Done`,
+			expected: `Done`,
+		},
+		{
+			name:     "styles filtered",
+			input:    `<span style="color: rgb(255, 0, 0); background-image: url(https://evil.example/x); font-weight: bold; width: expression(alert(1))">Red</span>`,
+			expected: `<span style="color: rgb(255, 0, 0); font-weight: bold">Red</span>`,
+		},
+		{
+			name:     "unsafe style removed",
+			input:    `<span style="color: url(javascript:alert(1))">X</span>`,
+			expected: `<span>X</span>`,
+		},
+		{
+			name:     "checkboxes disabled",
+			input:    `<input type="checkbox" checked onchange="x()"> Done <input type="text" value="a">`,
+			expected: `<input type="checkbox" checked="" disabled=""/> Done `,
+		},
+		{
+			name:     "comments removed",
+			input:    `<p>A<!-- <script>alert(1)</script> -->B</p>`,
+			expected: `<p>AB</p>`,
+		},
+		{
+			name:     "text escaped",
+			input:    `<p>a &lt;script&gt; &amp; b</p>`,
+			expected: `<p>a &lt;script&gt; &amp; b</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := cleaner.SanitizeHTML(tt.input)
+			if result != tt.expected {
+				t.Errorf("Expected:\n%q\nGot:\n%q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// Item variants as they appear in Rise data, normalized by normalizeVariant.
+var (
+	listStyleVariants = map[string]string{
+		"":           models.ListStyleBulleted,
+		"bullet":     models.ListStyleBulleted,
+		"bulleted":   models.ListStyleBulleted,
+		"bullets":    models.ListStyleBulleted,
+		"numbered":   models.ListStyleNumbered,
+		"number":     models.ListStyleNumbered,
+		"ordered":    models.ListStyleNumbered,
+		"checkbox":   models.ListStyleCheckboxes,
+		"checkboxes": models.ListStyleCheckboxes,
+		"checklist":  models.ListStyleCheckboxes,
+	}
+	questionTypeVariants = map[string]string{
+		"multiplechoice":   models.QuestionSingleChoice,
+		"single":           models.QuestionSingleChoice,
+		"singlechoice":     models.QuestionSingleChoice,
+		"multipleresponse": models.QuestionMultipleChoice,
+		"multiple":         models.QuestionMultipleChoice,
+		"multipleselect":   models.QuestionMultipleChoice,
+		"fillin":           models.QuestionFillIn,
+		"fillintheblank":   models.QuestionFillIn,
+		"fillintheblanks":  models.QuestionFillIn,
+		"matching":         models.QuestionMatching,
+	}
+	// knownItemTypes are the item types that carry no variant-specific details.
+	knownItemTypes = map[string]bool{
+		"text":        true,
+		"divider":     true,
+		"interactive": true,
+		"quote":       true,
+		"flashcard":   true,
+	}
+)
+
+// DecodeCourseDetails decodes the Settings and Data of every item in the
+// course into typed ItemDetails. It is called by the parser after the course
+// JSON has been unmarshaled.
+func DecodeCourseDetails(course *models.Course) {
+	for i := range course.Course.Lessons {
+		items := course.Course.Lessons[i].Items
+		for j := range items {
+			items[j].Details = DecodeItemDetails(&items[j])
+		}
+	}
+}
+
+// ItemDetails returns the decoded details of an item. Items that were not
+// loaded through the parser, such as items built in code, are decoded on demand.
+func ItemDetails(item *models.Item) *models.ItemDetails {
+	if item.Details != nil {
+		return item.Details
+	}
+	return DecodeItemDetails(item)
+}
+
+// DecodeItemDetails decodes an item's Settings and Data according to its type
+// and variant. Fields with unexpected types are left at their zero value; the
+// raw JSON of both is kept on the result in any case.
+func DecodeItemDetails(item *models.Item) *models.ItemDetails {
+	details := &models.ItemDetails{
+		RawSettings: rawJSON(item.Settings),
+		RawData:     rawJSON(item.Data),
+	}
+	decodeInto(details.RawSettings, &details.Layout)
+
+	itemType := strings.ToLower(item.Type)
+	variant := normalizeVariant(item.Variant)
+
+	switch itemType {
+	case "list":
+		style, ok := listStyleVariants[variant]
+		if !ok {
+			style = models.ListStyleBulleted
+		}
+		details.List = &models.ListDetails{Style: style}
+		details.Known = ok
+	case "knowledgecheck":
+		kc := &models.KnowledgeCheckDetails{}
+		decodeInto(details.RawSettings, kc)
+		decodeInto(details.RawData, kc)
+		questionType, ok := questionTypeVariants[variant]
+		if !ok {
+			questionType = inferQuestionType(item)
+		}
+		kc.QuestionType = questionType
+		details.KnowledgeCheck = kc
+		details.Known = ok
+	case "multimedia", "image":
+		media := &models.MediaDetails{}
+		decodeInto(details.RawSettings, media)
+		media.Layout = item.Variant
+		details.Media = media
+		details.Known = true
+	default:
+		details.Known = knownItemTypes[itemType]
+	}
+
+	return details
+}
+
+// inferQuestionType guesses the question type of a knowledge check with an
+// unknown variant from its answers.
+func inferQuestionType(item *models.Item) string {
+	correct := 0
+	for _, subItem := range item.Items {
+		for _, answer := range subItem.Answers {
+			if answer.MatchTitle != "" {
+				return models.QuestionMatching
+			}
+			if answer.Correct {
+				correct++
+			}
+		}
+	}
+	if correct > 1 {
+		return models.QuestionMultipleChoice
+	}
+	return models.QuestionSingleChoice
+}
+
+// normalizeVariant lowercases a variant and removes separators, so that
+// "Multiple Response", "multiple-response" and "multipleResponse" all match.
+func normalizeVariant(variant string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return r
+	}, strings.ToLower(variant))
+}
+
+// rawJSON returns the JSON encoding of an unmarshaled Settings or Data value.
+func rawJSON(v any) json.RawMessage {
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case json.RawMessage:
+		return value
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// decodeInto decodes raw JSON objects into target. Type mismatches in single
+// fields are ignored so that the remaining fields are still decoded.
+func decodeInto(raw json.RawMessage, target any) {
+	if len(raw) == 0 || raw[0] != '{' {
+		return
+	}
+	_ = json.Unmarshal(raw, target)
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// TestDecodeItemDetails tests decoding item settings and data per family and variant.
+func TestDecodeItemDetails(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		check func(t *testing.T, d *models.ItemDetails)
+	}{
+		{
+			name: "numbered list",
+			json: `{"type":"list","variant":"numbered"}`,
+			check: func(t *testing.T, d *models.ItemDetails) {
+				if d.List == nil || d.List.Style != models.ListStyleNumbered || !d.Known {
+					t.Errorf("Expected known numbered list, got %+v", d)
+				}
+			},
+		},
+		{
+			name: "checkbox list",
+			json: `{"type":"list","variant":"Checkboxes"}`,
+			check: func(t *testing.T, d *models.ItemDetails) {
+				if d.List == nil || d.List.Style != models.ListStyleCheckboxes {
+					t.Errorf("Expected checkbox list, got %+v", d.List)
+				}
+			},
+		},
+		{
+			name: "unknown list variant falls back to bullets",
+			json: `{"type":"list","variant":"fancy"}`,
+			check: func(t *testing.T, d *models.ItemDetails) {
+				if d.List == nil || d.List.Style != models.ListStyleBulleted || d.Known {
+					t.Errorf("Expected unknown bulleted list, got %+v", d)
+				}
+			},
+		},
+		{
+			name: "multiple response knowledge check with settings and data",
+			json: `{"type":"knowledgeCheck","variant":"Multiple Response",
+				"settings":{"shuffle":true,"allowRetry":true,"showAnswer":false,"paddingTop":3,"backgroundColor":"#fff"},
+				"data":{"points":10}}`,
+			check: func(t *testing.T, d *models.ItemDetails) {
+				kc := d.KnowledgeCheck
+				if kc == nil || kc.QuestionType != models.QuestionMultipleChoice || !d.Known {
+					t.Fatalf("Expected known multiple choice question, got %+v", d)
+				}
+				if !kc.Shuffle || !kc.AllowRetry || kc.ShowAnswer || kc.Points != 10 {
+					t.Errorf("Unexpected knowledge check details: %+v", kc)
+				}
+				if d.Layout.PaddingTop != 3 || d.Layout.BackgroundColor != "#fff" {
+					t.Errorf("Unexpected layout: %+v", d.Layout)
+				}
+			},
+		},
+		{
+			name: "rise multiple choice is a single choice question",
+			json: `{"type":"knowledgeCheck","variant":"multipleChoice"}`,
+			check: func(t *testing.T, d *models.ItemDetails) {
+				if d.KnowledgeCheck.QuestionType != models.QuestionSingleChoice {
+					t.Errorf("Expected single choice, got '%s'", d.KnowledgeCheck.QuestionType)
+				}
+			},
+		},
+		{
+			name: "fill in the blank",
+			json: `{"type":"knowledgeCheck","variant":"fill-in-the-blank"}`,
+			check: func(t *testing.T, d *models.ItemDetails) {
+				if d.KnowledgeCheck.QuestionType != models.QuestionFillIn {
+					t.Errorf("Expected fill in, got '%s'", d.KnowledgeCheck.QuestionType)
+				}
+			},
+		},
+		{
+			name: "question type inferred from matching answers",
+			json: `{"type":"knowledgeCheck","items":[{"answers":[{"title":"A","matchTitle":"1"}]}]}`,
+			check: func(t *testing.T, d *models.ItemDetails) {
+				if d.KnowledgeCheck.QuestionType != models.QuestionMatching || d.Known {
+					t.Errorf("Expected inferred matching question, got %+v", d)
+				}
+			},
+		},
+		{
+			name: "question type inferred from several correct answers",
+			json: `{"type":"knowledgeCheck","items":[{"answers":[{"title":"A","correct":true},{"title":"B","correct":true}]}]}`,
+			check: func(t *testing.T, d *models.ItemDetails) {
+				if d.KnowledgeCheck.QuestionType != models.QuestionMultipleChoice {
+					t.Errorf("Expected inferred multiple choice, got '%s'", d.KnowledgeCheck.QuestionType)
+				}
+			},
+		},
+		{
+			name: "multimedia settings",
+			json: `{"type":"multimedia","variant":"video","settings":{"autoplay":true,"loop":"yes"}}`,
+			check: func(t *testing.T, d *models.ItemDetails) {
+				// The mistyped loop flag is ignored without losing autoplay
+				if d.Media == nil || d.Media.Layout != "video" || !d.Media.Autoplay || d.Media.Loop {
+					t.Errorf("Unexpected media details: %+v", d.Media)
+				}
+			},
+		},
+		{
+			name: "unknown family keeps raw JSON",
+			json: `{"type":"timeline","variant":"vertical","settings":{"orientation":"vertical"},"data":[1,2]}`,
+			check: func(t *testing.T, d *models.ItemDetails) {
+				if d.Known || d.List != nil || d.KnowledgeCheck != nil || d.Media != nil {
+					t.Errorf("Expected unknown item without typed details, got %+v", d)
+				}
+				if string(d.RawSettings) != `{"orientation":"vertical"}` || string(d.RawData) != `[1,2]` {
+					t.Errorf("Expected raw JSON to be kept, got %s and %s", d.RawSettings, d.RawData)
+				}
+			},
+		},
+		{
+			name: "known family without settings",
+			json: `{"type":"text","variant":"paragraph"}`,
+			check: func(t *testing.T, d *models.ItemDetails) {
+				if !d.Known || d.RawSettings != nil || d.RawData != nil {
+					t.Errorf("Expected known text item without raw JSON, got %+v", d)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var item models.Item
+			if err := json.Unmarshal([]byte(tt.json), &item); err != nil {
+				t.Fatalf("Failed to unmarshal item: %v", err)
+			}
+			tt.check(t, DecodeItemDetails(&item))
+		})
+	}
+}
+
+// TestArticulateParser_DecodesItemDetails tests that loaded courses carry decoded details.
+func TestArticulateParser_DecodesItemDetails(t *testing.T) {
+	parser := NewArticulateParser(nil, "", 0)
+	input := `{"course":{"lessons":[{"items":[{"type":"list","variant":"numbered"}]}]}}`
+
+	course, err := parser.LoadCourseFromReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	item := &course.Course.Lessons[0].Items[0]
+	if item.Details == nil || item.Details.List.Style != models.ListStyleNumbered {
+		t.Fatalf("Expected decoded numbered list, got %+v", item.Details)
+	}
+	if ItemDetails(item) != item.Details {
+		t.Error("Expected ItemDetails to reuse decoded details")
+	}
+
+	// Items built in code are decoded on demand
+	built := &models.Item{Type: "list", Variant: "checkboxes"}
+	if got := ItemDetails(built).List.Style; got != models.ListStyleCheckboxes {
+		t.Errorf("Expected on-demand decoding to give checkboxes, got '%s'", got)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"strings"
+	"unicode"
 
 	"github.com/kjanat/articulate-parser/internal/interfaces"
 )
@@ -15,23 +17,24 @@ type SlogLogger struct {
 
 // NewSlogLogger creates a new structured logger using slog.
 // The level parameter controls the minimum log level (debug, info, warn, error).
+// Logs are written to stderr so they never mix with exports written to stdout.
 func NewSlogLogger(level slog.Level) interfaces.Logger {
 	opts := &slog.HandlerOptions{
 		Level: level,
 	}
-	handler := slog.NewJSONHandler(os.Stdout, opts)
+	handler := slog.NewJSONHandler(os.Stderr, opts)
 	return &SlogLogger{
 		logger: slog.New(handler),
 	}
 }
 
 // NewTextLogger creates a new structured logger with human-readable text output.
-// Useful for development and debugging.
+// Useful for development and debugging. Like NewSlogLogger, it writes to stderr.
 func NewTextLogger(level slog.Level) interfaces.Logger {
 	opts := &slog.HandlerOptions{
 		Level: level,
 	}
-	handler := slog.NewTextHandler(os.Stdout, opts)
+	handler := slog.NewTextHandler(os.Stderr, opts)
 	return &SlogLogger{
 		logger: slog.New(handler),
 	}
@@ -72,6 +75,120 @@ func (l *SlogLogger) WithContext(ctx context.Context) interfaces.Logger {
 	return l
 }
 
+// redactedValue replaces secrets in log output.
+const redactedValue = "[REDACTED]"
+
+// minSecretLength is the shortest secret value that is redacted from log
+// messages. Shorter values would blank out unrelated text.
+const minSecretLength = 4
+
+// sensitiveKeys are the words that mark a log key as naming a credential
+// when they end it, as in "Authorization", "session_cookie" or "X-Auth-Token".
+var sensitiveKeys = map[string]bool{
+	"authorization": true, "cookie": true, "token": true, "password": true, "secret": true,
+}
+
+// RedactingLogger wraps a Logger and removes credentials from everything it logs.
+// Values of keys that look sensitive (e.g. "authorization" or "cookie") are
+// replaced entirely, and known secret values are masked wherever they occur in
+// the message or in string and error values.
+type RedactingLogger struct {
+	next     interfaces.Logger
+	replacer *strings.Replacer
+}
+
+// NewRedactingLogger creates a logger that redacts the given secrets before
+// passing entries to next. Secrets shorter than four characters are ignored.
+func NewRedactingLogger(next interfaces.Logger, secrets ...string) interfaces.Logger {
+	var pairs []string
+	for _, secret := range secrets {
+		if len(secret) >= minSecretLength {
+			pairs = append(pairs, secret, redactedValue)
+		}
+	}
+	return &RedactingLogger{
+		next:     next,
+		replacer: strings.NewReplacer(pairs...),
+	}
+}
+
+// Debug logs a debug-level message with secrets redacted.
+func (l *RedactingLogger) Debug(msg string, keysAndValues ...any) {
+	l.next.Debug(l.replacer.Replace(msg), l.redact(keysAndValues)...)
+}
+
+// Info logs an info-level message with secrets redacted.
+func (l *RedactingLogger) Info(msg string, keysAndValues ...any) {
+	l.next.Info(l.replacer.Replace(msg), l.redact(keysAndValues)...)
+}
+
+// Warn logs a warning-level message with secrets redacted.
+func (l *RedactingLogger) Warn(msg string, keysAndValues ...any) {
+	l.next.Warn(l.replacer.Replace(msg), l.redact(keysAndValues)...)
+}
+
+// Error logs an error-level message with secrets redacted.
+func (l *RedactingLogger) Error(msg string, keysAndValues ...any) {
+	l.next.Error(l.replacer.Replace(msg), l.redact(keysAndValues)...)
+}
+
+// With returns a new redacting logger with the given key-value pairs added as context.
+func (l *RedactingLogger) With(keysAndValues ...any) interfaces.Logger {
+	return &RedactingLogger{
+		next:     l.next.With(l.redact(keysAndValues)...),
+		replacer: l.replacer,
+	}
+}
+
+// WithContext returns a new redacting logger with context information.
+func (l *RedactingLogger) WithContext(ctx context.Context) interfaces.Logger {
+	return &RedactingLogger{
+		next:     l.next.WithContext(ctx),
+		replacer: l.replacer,
+	}
+}
+
+// redact returns a copy of keysAndValues with sensitive keys and secret values masked.
+func (l *RedactingLogger) redact(keysAndValues []any) []any {
+	redacted := make([]any, len(keysAndValues))
+	for i, v := range keysAndValues {
+		if i%2 == 1 {
+			if key, ok := keysAndValues[i-1].(string); ok && isSensitiveKey(key) {
+				redacted[i] = redactedValue
+				continue
+			}
+		}
+
+		switch value := v.(type) {
+		case string:
+			redacted[i] = l.replacer.Replace(value)
+		case error:
+			redacted[i] = l.replacer.Replace(value.Error())
+		default:
+			redacted[i] = v
+		}
+	}
+	return redacted
+}
+
+// isSensitiveKey reports whether a log key names a credential, i.e. its last
+// word is one of the sensitiveKeys. Words are separated by punctuation or by
+// an upper case letter after a lower case one, so "apiToken" is sensitive
+// while "token_count" and "tokens" are not.
+func isSensitiveKey(key string) bool {
+	runes := []rune(key)
+	start := 0
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			start = i + 1
+		case i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1]):
+			start = i
+		}
+	}
+	return sensitiveKeys[strings.ToLower(string(runes[start:]))]
+}
+
 // NoOpLogger is a logger that discards all log messages.
 // Useful for testing or when logging should be disabled.
 type NoOpLogger struct{}
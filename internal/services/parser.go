@@ -1,16 +1,23 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/url"
 	"os"
 	"regexp"
+	"strings"
 	"time"
 
+	"golang.org/x/time/rate"
+
+	"github.com/kjanat/articulate-parser/internal/config"
 	"github.com/kjanat/articulate-parser/internal/interfaces"
 	"github.com/kjanat/articulate-parser/internal/models"
 )
@@ -21,8 +28,23 @@ const (
 	defaultBaseURL = "https://" + riseHost
 )
 
-// shareIDRegex is compiled once at package init for extracting share IDs from URIs.
-var shareIDRegex = regexp.MustCompile(`/share/([a-zA-Z0-9_-]+)`)
+// Patterns compiled once at package init for parsing share URLs.
+var (
+	// shareIDRegex extracts the share ID from the path of a share URL.
+	shareIDRegex = regexp.MustCompile(`/share/([a-zA-Z0-9_-]+)`)
+	// lessonLinkRegex extracts the lesson ID from a deep link fragment like #/lessons/<id>.
+	lessonLinkRegex = regexp.MustCompile(`^/?lessons/([a-zA-Z0-9_-]+)`)
+)
+
+// shareLink holds the parts of a Rise share URL needed to fetch its course.
+type shareLink struct {
+	// shareID identifies the shared course
+	shareID string
+	// lessonID is the lesson a deep link points to, if any
+	lessonID string
+	// baseURL is the API base URL serving the share host
+	baseURL string
+}
 
 // ArticulateParser implements the CourseParser interface specifically for Articulate Rise courses.
 // It can fetch courses from the Articulate Rise API or load them from local JSON files.
@@ -33,12 +55,69 @@ type ArticulateParser struct {
 	Client *http.Client
 	// Logger for structured logging
 	Logger interfaces.Logger
+	// Retry controls retries of transient failures; the zero value disables them
+	Retry RetryPolicy
+	// Limiter throttles requests across all fetches made by this parser; nil disables it
+	Limiter *rate.Limiter
+	// Cache stores fetched courses on disk for revalidation and offline use; nil disables it
+	Cache *CourseCache
+	// Offline serves courses strictly from Cache without making any requests
+	Offline bool
+	// Hosts maps additional accepted share hosts (host or host:port, lower case)
+	// to the API base URL serving them. An empty base URL uses BaseURL.
+	// rise.articulate.com is always accepted.
+	Hosts map[string]string
+	// Auth holds the credentials sent with every request; nil sends anonymous requests
+	Auth *Credentials
+	// Strict rejects course JSON with unknown fields or items with a *SchemaDriftError
+	Strict bool
+	// MaxPayloadSize is the maximum size of course data in bytes; larger
+	// payloads fail with a *PayloadTooLargeError. Zero disables the limit.
+	MaxPayloadSize int64
 }
 
 // NewArticulateParser creates a new ArticulateParser instance.
 // If baseURL is empty, uses the default Articulate Rise API URL.
 // If timeout is zero, uses a 30-second timeout.
+// Course data is limited to config.DefaultMaxPayloadSize.
 func NewArticulateParser(logger interfaces.Logger, baseURL string, timeout time.Duration) interfaces.CourseParser {
+	return newArticulateParser(logger, baseURL, timeout)
+}
+
+// NewArticulateParserFromConfig creates a new ArticulateParser instance from
+// the application configuration, including its retry, rate limiting, cache and
+// share host settings. Requests are authenticated with creds, which may be nil;
+// see LoadCredentials. The logger should redact creds.Secrets(), e.g. by
+// wrapping it with NewRedactingLogger.
+func NewArticulateParserFromConfig(logger interfaces.Logger, cfg *config.Config, creds *Credentials) interfaces.CourseParser {
+	p := newArticulateParser(logger, cfg.BaseURL, cfg.RequestTimeout)
+	p.Auth = creds
+	p.Client.Jar = creds.cookieJar()
+	p.Retry = RetryPolicy{
+		MaxRetries: cfg.MaxRetries,
+		BaseDelay:  cfg.RetryBaseDelay,
+		MaxDelay:   cfg.RetryMaxDelay,
+	}
+	if cfg.RateLimit > 0 {
+		p.Limiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), max(cfg.RateBurst, 1))
+	}
+	if cfg.CacheDir != "" {
+		p.Cache = NewCourseCache(cfg.CacheDir)
+	}
+	p.Offline = cfg.Offline
+	p.Strict = cfg.Strict
+	p.MaxPayloadSize = cfg.MaxPayloadSize
+	if len(cfg.ShareHosts) > 0 {
+		p.Hosts = make(map[string]string, len(cfg.ShareHosts))
+		for host, baseURL := range cfg.ShareHosts {
+			p.Hosts[strings.ToLower(host)] = strings.TrimSuffix(baseURL, "/")
+		}
+	}
+	return p
+}
+
+// newArticulateParser applies the constructor defaults shared by the exported constructors.
+func newArticulateParser(logger interfaces.Logger, baseURL string, timeout time.Duration) *ArticulateParser {
 	if logger == nil {
 		logger = NewNoOpLogger()
 	}
@@ -53,31 +132,185 @@ func NewArticulateParser(logger interfaces.Logger, baseURL string, timeout time.
 		Client: &http.Client{
 			Timeout: timeout,
 		},
-		Logger: logger,
+		Logger:         logger,
+		MaxPayloadSize: config.DefaultMaxPayloadSize,
 	}
 }
 
 // FetchCourse fetches a course from the given URI and returns the parsed course data.
-// The URI should be an Articulate Rise share URL (e.g., https://rise.articulate.com/share/SHARE_ID).
+// The URI should be an Articulate Rise share URL (e.g., https://rise.articulate.com/share/SHARE_ID)
+// on rise.articulate.com or one of the configured Hosts. For deep links to a lesson
+// (e.g., /share/SHARE_ID#/lessons/LESSON_ID) the lesson is recorded in LinkedLessonID.
 // The context can be used for cancellation and timeout control.
+// When a cache is configured, previously fetched courses are revalidated with
+// conditional requests and, in offline mode, served without any request.
 func (p *ArticulateParser) FetchCourse(ctx context.Context, uri string) (*models.Course, error) {
-	shareID, err := p.extractShareID(uri)
+	link, err := p.parseShareURL(uri)
 	if err != nil {
 		return nil, err
 	}
 
-	apiURL := p.buildAPIURL(shareID)
+	course, err := p.fetchCourseData(ctx, link.baseURL, link.shareID)
+	if err != nil {
+		return nil, err
+	}
+	course.LinkedLessonID = link.lessonID
+
+	return course, nil
+}
+
+// fetchCourseData fetches the course for a share ID from the boot API at baseURL.
+// Without a cache the response is decoded as it streams in. With a cache it
+// sends the stored validators as conditional headers, reuses the cached body
+// on 304 Not Modified and stores fresh responses for later runs.
+func (p *ArticulateParser) fetchCourseData(ctx context.Context, baseURL, shareID string) (*models.Course, error) {
+	apiURL := bootAPIURL(baseURL, shareID)
+	if p.Cache == nil {
+		if p.Offline {
+			return nil, fmt.Errorf("offline mode requires a cache directory")
+		}
+		var course *models.Course
+		_, err := p.fetchWithRetry(ctx, apiURL, nil, func(pr *payloadReader) error {
+			var err error
+			course, _, err = p.decodeCourseStream(pr, false)
+			return err
+		})
+		return course, err
+	}
+
+	entry, cached, err := p.Cache.Load(baseURL, shareID)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		// A damaged entry is treated as a miss and replaced by the next fetch
+		p.Logger.Warn("ignoring unreadable cache entry", "error", err, "share_id", shareID)
+	}
+	if err != nil {
+		entry, cached = nil, nil
+	}
+
+	if p.Offline {
+		if entry == nil {
+			return nil, fmt.Errorf("course %s is not in the cache (offline mode)", shareID)
+		}
+		p.Logger.Debug("serving course from cache", "share_id", shareID, "fetched_at", entry.FetchedAt)
+		return p.decodeCourse(cached)
+	}
+
+	header := make(http.Header)
+	if entry != nil {
+		if entry.ETag != "" {
+			header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	var course *models.Course
+	var body []byte
+	resp, err := p.fetchWithRetry(ctx, apiURL, header, func(pr *payloadReader) error {
+		var err error
+		course, body, err = p.decodeCourseStream(pr, true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		p.Logger.Debug("cached course is up to date", "share_id", shareID)
+		if course, err = p.decodeCourse(cached); err != nil {
+			return nil, err
+		}
+		entry.FetchedAt = time.Now()
+		body = cached
+	} else {
+		entry = &CacheEntry{
+			ShareID:      shareID,
+			BaseURL:      baseURL,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			FetchedAt:    time.Now(),
+		}
+	}
+
+	// Failing to update the cache does not affect the fetched course
+	if err := p.Cache.Store(entry, body); err != nil {
+		p.Logger.Warn("failed to update course cache", "error", err, "share_id", shareID)
+	}
+	return course, nil
+}
+
+// fetchWithRetry requests apiURL until it succeeds, the failure is not
+// transient, the retry policy is exhausted or the context is cancelled.
+// The given header is added to each request. The body of a 200 OK response
+// is streamed to decode; read errors while decoding are retried like failed
+// requests, while decoding errors are not. It returns the successful
+// response, whose body has been closed; a 304 Not Modified counts as success
+// only when the request was conditional.
+func (p *ArticulateParser) fetchWithRetry(ctx context.Context, apiURL string, header http.Header, decode func(*payloadReader) error) (*http.Response, error) {
+	conditional := header.Get("If-None-Match") != "" || header.Get("If-Modified-Since") != ""
+
+	for attempt := 0; ; attempt++ {
+		resp, body, err := p.fetchOnce(ctx, apiURL, header, decode)
+
+		var delay time.Duration
+		switch {
+		case err != nil && resp != nil:
+			// The response arrived but its payload was rejected
+			return nil, err
+		case err != nil:
+			if ctx.Err() != nil || attempt >= p.Retry.MaxRetries {
+				return nil, err
+			}
+			delay = p.Retry.backoff(attempt)
+		case resp.StatusCode == http.StatusOK,
+			resp.StatusCode == http.StatusNotModified && conditional:
+			return resp, nil
+		default:
+			err = statusError(resp.StatusCode, body)
+			if !isRetryableStatus(resp.StatusCode) || attempt >= p.Retry.MaxRetries {
+				return nil, err
+			}
+			var ok bool
+			if delay, ok = p.Retry.retryDelay(attempt, resp.Header); !ok {
+				return nil, err
+			}
+		}
+
+		p.Logger.Warn("retrying course fetch", "attempt", attempt+1, "delay", delay, "error", err, "url", apiURL)
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, fmt.Errorf("failed to fetch course data: %w", err)
+		}
+	}
+}
+
+// fetchOnce performs a single authenticated GET request for apiURL with the
+// given extra header, waiting for the rate limiter first. The body of a
+// 200 OK response is passed to decode; for other statuses up to
+// maxErrorBodySize bytes of the body are returned for the error message.
+// An error returned together with the response means that the payload was
+// rejected, e.g. because it is too large or not valid JSON.
+func (p *ArticulateParser) fetchOnce(ctx context.Context, apiURL string, header http.Header, decode func(*payloadReader) error) (*http.Response, []byte, error) {
+	if p.Limiter != nil {
+		if err := p.Limiter.Wait(ctx); err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch course data: rate limiter: %w", err)
+		}
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, http.NoBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
 	}
+	p.Auth.apply(req)
 
 	resp, err := p.Client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch course data: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch course data: %w", err)
 	}
-	// Ensure response body is closed even if ReadAll fails. Close errors are logged
+	// Ensure response body is closed even if decoding fails. Close errors are logged
 	// but not fatal since the body content has already been read and parsed. In the
 	// context of HTTP responses, the body must be closed to release the underlying
 	// connection, but a close error doesn't invalidate the data already consumed.
@@ -87,37 +320,125 @@ func (p *ArticulateParser) FetchCourse(ctx context.Context, uri string) (*models
 		}
 	}()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		return resp, body, nil
 	}
 
-	var course models.Course
-	if err := json.Unmarshal(body, &course); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	pr := p.newPayloadReader(resp.Body)
+	if err := pr.checkSize(resp.ContentLength); err != nil {
+		return resp, nil, err
+	}
+	if err := decode(pr); err != nil {
+		if pr.readErr != nil {
+			return nil, nil, fmt.Errorf("failed to read response body: %w", pr.readErr)
+		}
+		return resp, nil, err
 	}
 
-	return &course, nil
+	return resp, nil, nil
 }
 
-// LoadCourseFromFile loads an Articulate Rise course from a local JSON file.
+// LoadCourseFromFile loads an Articulate Rise course from a local JSON file,
+// from a Rise SCORM package (.zip) produced by "Export for LMS", or from a
+// share page saved from a browser (.html).
 func (p *ArticulateParser) LoadCourseFromFile(filePath string) (*models.Course, error) {
+	switch {
+	case isPackageFile(filePath):
+		return p.loadCourseFromPackage(filePath)
+	case isSharePageFile(filePath):
+		return p.loadCourseFromSharePage(filePath)
+	}
+
 	// #nosec G304 - File path is provided by user via CLI argument, which is expected behavior
-	data, err := os.ReadFile(filePath)
+	f, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
+	// The file is only read from, so a close error cannot affect the result.
+	defer func() {
+		if err := f.Close(); err != nil {
+			p.Logger.Warn("failed to close course file", "error", err, "path", filePath)
+		}
+	}()
+
+	pr := p.newPayloadReader(f)
+	if info, err := f.Stat(); err == nil {
+		if err := pr.checkSize(info.Size()); err != nil {
+			return nil, err
+		}
+	}
+	course, _, err := p.decodeCourseStream(pr, false)
+	return course, err
+}
+
+// LoadCourseFromReader loads an Articulate Rise course from JSON read from r.
+// This allows course data to be piped in, e.g. from standard input.
+// The JSON is decoded as it is read, up to MaxPayloadSize bytes.
+func (p *ArticulateParser) LoadCourseFromReader(r io.Reader) (*models.Course, error) {
+	course, _, err := p.decodeCourseStream(p.newPayloadReader(r), false)
+	return course, err
+}
 
+// decodeCourse decodes course JSON that is already in memory, such as a
+// cached response.
+func (p *ArticulateParser) decodeCourse(data []byte) (*models.Course, error) {
+	course, _, err := p.decodeCourseStream(p.newPayloadReader(bytes.NewReader(data)), false)
+	return course, err
+}
+
+// decodeCourseStream decodes course JSON as it is read from pr, decodes the
+// item details and, in strict mode, checks the JSON for schema drift. The
+// JSON itself is only kept in memory, and returned, if keepRaw is set or
+// strict mode needs it.
+func (p *ArticulateParser) decodeCourseStream(pr *payloadReader, keepRaw bool) (*models.Course, []byte, error) {
+	var r io.Reader = pr
+	var raw *bytes.Buffer
+	if keepRaw || p.Strict {
+		raw = new(bytes.Buffer)
+		r = io.TeeReader(pr, raw)
+	}
+
+	dec := json.NewDecoder(r)
 	var course models.Course
-	if err := json.Unmarshal(data, &course); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	if err := streamCourse(dec, &course); err != nil {
+		return nil, nil, pr.wrapError(err)
+	}
+	// Like json.Unmarshal, reject anything but whitespace after the course
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		if err == nil {
+			err = fmt.Errorf("unexpected data after the course object")
+		}
+		return nil, nil, pr.wrapError(err)
 	}
+	DecodeCourseDetails(&course)
 
-	return &course, nil
+	if raw == nil {
+		return &course, nil, nil
+	}
+	if err := p.checkSchema(raw.Bytes()); err != nil {
+		return nil, nil, err
+	}
+	return &course, raw.Bytes(), nil
+}
+
+// checkSchema returns a *SchemaDriftError in strict mode if the course JSON
+// contains fields or items that are not recognized. It does nothing otherwise.
+func (p *ArticulateParser) checkSchema(data []byte) error {
+	if !p.Strict {
+		return nil
+	}
+	issues, err := CheckSchema(data)
+	if err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return &SchemaDriftError{Issues: issues}
+	}
+	return nil
 }
 
 // extractShareID extracts the share ID from a Rise URI.
@@ -131,22 +452,62 @@ func (p *ArticulateParser) LoadCourseFromFile(filePath string) (*models.Course,
 //   - The share ID string if found
 //   - An error if the share ID can't be extracted from the URI
 func (p *ArticulateParser) extractShareID(uri string) (string, error) {
+	link, err := p.parseShareURL(uri)
+	if err != nil {
+		return "", err
+	}
+	return link.shareID, nil
+}
+
+// parseShareURL validates a share URL against the accepted hosts and extracts
+// the share ID, the deep-linked lesson ID and the API base URL for its host.
+//
+// Parameters:
+//   - uri: The Articulate Rise share URL
+//
+// Returns:
+//   - The parsed share link
+//   - An error if the host is not accepted or the share ID can't be extracted
+func (p *ArticulateParser) parseShareURL(uri string) (shareLink, error) {
 	// Parse the URL to validate the domain
 	parsedURL, err := url.Parse(uri)
 	if err != nil {
-		return "", fmt.Errorf("invalid URI: %s", uri)
+		return shareLink{}, fmt.Errorf("invalid URI: %s", uri)
 	}
 
-	// Validate that it's an Articulate Rise domain
-	if parsedURL.Host != riseHost {
-		return "", fmt.Errorf("invalid domain for Articulate Rise URI: %s", parsedURL.Host)
+	// Validate that it's an Articulate Rise domain or a configured share host
+	baseURL, ok := p.apiBaseURL(parsedURL.Host)
+	if !ok {
+		return shareLink{}, fmt.Errorf("invalid domain for Articulate Rise URI: %s", parsedURL.Host)
 	}
 
-	matches := shareIDRegex.FindStringSubmatch(uri)
+	matches := shareIDRegex.FindStringSubmatch(parsedURL.Path)
 	if len(matches) < 2 {
-		return "", fmt.Errorf("could not extract share ID from URI: %s", uri)
+		return shareLink{}, fmt.Errorf("could not extract share ID from URI: %s", uri)
 	}
-	return matches[1], nil
+
+	link := shareLink{shareID: matches[1], baseURL: baseURL}
+	if lesson := lessonLinkRegex.FindStringSubmatch(parsedURL.Fragment); len(lesson) == 2 {
+		link.lessonID = lesson[1]
+	}
+	return link, nil
+}
+
+// apiBaseURL returns the API base URL serving a share host and whether the
+// host is accepted at all. Hosts are compared case-insensitively, including
+// any port.
+func (p *ArticulateParser) apiBaseURL(host string) (string, bool) {
+	host = strings.ToLower(host)
+	if baseURL, ok := p.Hosts[host]; ok {
+		if baseURL == "" {
+			return p.BaseURL, true
+		}
+		return baseURL, true
+	}
+	if host == riseHost {
+		return p.BaseURL, true
+	}
+	return "", false
 }
 
 // buildAPIURL constructs the API URL for fetching course data.
@@ -158,5 +519,10 @@ func (p *ArticulateParser) extractShareID(uri string) (string, error) {
 // Returns:
 //   - The complete API URL string for fetching the course data
 func (p *ArticulateParser) buildAPIURL(shareID string) string {
-	return fmt.Sprintf("%s/api/rise-runtime/boot/share/%s", p.BaseURL, shareID)
+	return bootAPIURL(p.BaseURL, shareID)
+}
+
+// bootAPIURL constructs the boot API URL for a share ID on the given base URL.
+func bootAPIURL(baseURL, shareID string) string {
+	return fmt.Sprintf("%s/api/rise-runtime/boot/share/%s", baseURL, shareID)
 }
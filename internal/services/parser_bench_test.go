@@ -1,8 +1,11 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -217,3 +220,94 @@ func BenchmarkArticulateParser_BuildAPIURL(b *testing.B) {
 		_ = parser.buildAPIURL(shareID)
 	}
 }
+
+// largeCourseJSON returns the JSON of a course with the given number of
+// lessons, each holding text, list and knowledge check items.
+func largeCourseJSON(b *testing.B, lessonCount int) []byte {
+	lessons := make([]models.Lesson, lessonCount)
+	for i := range lessons {
+		lessons[i] = models.Lesson{
+			ID:          fmt.Sprintf("lesson-%d", i),
+			Title:       fmt.Sprintf("Lesson %d", i),
+			Type:        "lesson",
+			Description: "<p>A lesson description that is long enough to be realistic.</p>",
+			Items: []models.Item{
+				{Type: "text", Items: []models.SubItem{{Heading: "<h2>Heading</h2>", Paragraph: "<p>Paragraph text with <strong>markup</strong>.</p>"}}},
+				{Type: "list", Variant: "numbered", Items: []models.SubItem{{Paragraph: "<p>First</p>"}, {Paragraph: "<p>Second</p>"}}},
+				{Type: "knowledgeCheck", Items: []models.SubItem{{
+					Title:    "<p>Question?</p>",
+					Answers:  []models.Answer{{Title: "A", Correct: true}, {Title: "B"}, {Title: "C"}},
+					Feedback: "<p>Feedback</p>",
+				}}},
+			},
+		}
+	}
+
+	data, err := json.Marshal(&models.Course{ShareID: "large", Course: models.CourseInfo{Title: "Large", Lessons: lessons}})
+	if err != nil {
+		b.Fatalf("Failed to marshal: %v", err)
+	}
+	return data
+}
+
+// streamReader hides the io.WriterTo and Len methods of bytes.Reader, so
+// that reading behaves like an HTTP response body of unknown length.
+type streamReader struct {
+	r io.Reader
+}
+
+func (s streamReader) Read(p []byte) (int, error) { return s.r.Read(p) }
+
+// BenchmarkDecodeCourse_ReadAll benchmarks the former approach of reading the
+// whole payload with io.ReadAll before unmarshaling it, for comparison with
+// BenchmarkDecodeCourse_Stream.
+func BenchmarkDecodeCourse_ReadAll(b *testing.B) {
+	data := largeCourseJSON(b, 2000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for b.Loop() {
+		body, err := io.ReadAll(streamReader{bytes.NewReader(data)})
+		if err != nil {
+			b.Fatalf("ReadAll failed: %v", err)
+		}
+		var course models.Course
+		if err := json.Unmarshal(body, &course); err != nil {
+			b.Fatalf("Unmarshal failed: %v", err)
+		}
+		DecodeCourseDetails(&course)
+	}
+}
+
+// BenchmarkDecodeCourse_Stream benchmarks decoding the same payload as it is
+// read, as FetchCourse and LoadCourseFromReader do.
+func BenchmarkDecodeCourse_Stream(b *testing.B) {
+	data := largeCourseJSON(b, 2000)
+	parser := NewArticulateParser(nil, "", 0)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := parser.LoadCourseFromReader(streamReader{bytes.NewReader(data)}); err != nil {
+			b.Fatalf("LoadCourseFromReader failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkDecodeCourse_TooLarge benchmarks rejecting an oversized payload,
+// which stops reading at the limit instead of buffering the whole payload.
+func BenchmarkDecodeCourse_TooLarge(b *testing.B) {
+	data := largeCourseJSON(b, 2000)
+	parser := NewArticulateParser(nil, "", 0).(*ArticulateParser)
+	parser.MaxPayloadSize = int64(len(data) / 10)
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for b.Loop() {
+		if _, err := parser.LoadCourseFromReader(streamReader{bytes.NewReader(data)}); err == nil {
+			b.Fatal("Expected payload too large error")
+		}
+	}
+}
@@ -283,6 +283,27 @@ func TestArticulateParser_LoadCourseFromFile_InvalidJSON(t *testing.T) {
 	}
 }
 
+// TestArticulateParser_LoadCourseFromReader tests loading course JSON from a reader.
+func TestArticulateParser_LoadCourseFromReader(t *testing.T) {
+	parser := NewArticulateParser(nil, "", 0)
+
+	course, err := parser.LoadCourseFromReader(strings.NewReader(`{"shareId":"stdin-share-id","course":{"title":"Piped Course"}}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if course.ShareID != "stdin-share-id" {
+		t.Errorf("Expected ShareID 'stdin-share-id', got '%s'", course.ShareID)
+	}
+	if course.Course.Title != "Piped Course" {
+		t.Errorf("Expected title 'Piped Course', got '%s'", course.Course.Title)
+	}
+
+	_, err = parser.LoadCourseFromReader(strings.NewReader("invalid json"))
+	if err == nil || !strings.Contains(err.Error(), "failed to unmarshal JSON") {
+		t.Errorf("Expected error to contain 'failed to unmarshal JSON', got %v", err)
+	}
+}
+
 // TestExtractShareID tests the extractShareID method.
 func TestExtractShareID(t *testing.T) {
 	parser := &ArticulateParser{}
@@ -365,6 +386,113 @@ func TestExtractShareID(t *testing.T) {
 	}
 }
 
+// TestParseShareURL tests custom share hosts and lesson deep links.
+func TestParseShareURL(t *testing.T) {
+	parser := &ArticulateParser{
+		BaseURL: "https://rise.articulate.com",
+		Hosts: map[string]string{
+			"rise.example.com": "",
+			"localhost:8080":   "http://localhost:9000",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		uri           string
+		expected      shareLink
+		expectedError string
+	}{
+		{
+			name:     "default host",
+			uri:      "https://rise.articulate.com/share/abc123#/",
+			expected: shareLink{shareID: "abc123", baseURL: "https://rise.articulate.com"},
+		},
+		{
+			name:     "lesson deep link",
+			uri:      "https://rise.articulate.com/share/abc123#/lessons/Lz9_x-1",
+			expected: shareLink{shareID: "abc123", lessonID: "Lz9_x-1", baseURL: "https://rise.articulate.com"},
+		},
+		{
+			name:     "deep link with trailing path",
+			uri:      "https://rise.articulate.com/share/abc123/#/lessons/lesson42/blocks",
+			expected: shareLink{shareID: "abc123", lessonID: "lesson42", baseURL: "https://rise.articulate.com"},
+		},
+		{
+			name:     "allowed host using the default base URL",
+			uri:      "https://RISE.example.com/share/alt-id",
+			expected: shareLink{shareID: "alt-id", baseURL: "https://rise.articulate.com"},
+		},
+		{
+			name:     "allowed host with port mapped to its own API",
+			uri:      "http://localhost:8080/share/mirror-id#/lessons/l1",
+			expected: shareLink{shareID: "mirror-id", lessonID: "l1", baseURL: "http://localhost:9000"},
+		},
+		{
+			name:          "host not in allowlist",
+			uri:           "https://evil.example.com/share/abc123",
+			expectedError: "invalid domain for Articulate Rise URI: evil.example.com",
+		},
+		{
+			name:          "allowed host on a different port",
+			uri:           "http://localhost:8081/share/abc123",
+			expectedError: "invalid domain",
+		},
+		{
+			name:          "share path only in the query",
+			uri:           "https://rise.articulate.com/redirect?to=/share/abc123",
+			expectedError: "could not extract share ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, err := parser.parseShareURL(tt.uri)
+
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("Expected error containing '%s', got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if link != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, link)
+			}
+		})
+	}
+}
+
+// TestArticulateParser_FetchCourse_CustomHost tests fetching from a mapped share host.
+func TestArticulateParser_FetchCourse_CustomHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/rise-runtime/boot/share/mirror-id" {
+			t.Errorf("Unexpected request path '%s'", r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(&models.Course{ShareID: "mirror-id"})
+	}))
+	defer server.Close()
+
+	parser := &ArticulateParser{
+		BaseURL: "https://unused.invalid",
+		Client:  &http.Client{Timeout: 5 * time.Second},
+		Logger:  NewNoOpLogger(),
+		Hosts:   map[string]string{"rise.mirror.test": server.URL},
+	}
+
+	course, err := parser.FetchCourse(context.Background(), "https://rise.mirror.test/share/mirror-id#/lessons/intro")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if course.ShareID != "mirror-id" {
+		t.Errorf("Expected ShareID 'mirror-id', got '%s'", course.ShareID)
+	}
+	if course.LinkedLessonID != "intro" {
+		t.Errorf("Expected LinkedLessonID 'intro', got '%s'", course.LinkedLessonID)
+	}
+}
+
 // TestBuildAPIURL tests the buildAPIURL method.
 func TestBuildAPIURL(t *testing.T) {
 	parser := &ArticulateParser{
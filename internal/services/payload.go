@@ -0,0 +1,174 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// maxErrorBodySize bounds how much of an error response is read for the error message.
+const maxErrorBodySize = 64 << 10
+
+// PayloadTooLargeError is returned when course data exceeds the parser's
+// MaxPayloadSize.
+type PayloadTooLargeError struct {
+	// Limit is the maximum payload size in bytes
+	Limit int64
+}
+
+// Error implements the error interface.
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("payload too large: course data exceeds the maximum size of %d bytes", e.Limit)
+}
+
+// payloadReader streams course data of at most limit bytes. It records
+// errors of the underlying reader so that they can be told apart from
+// decoding errors, e.g. to retry a connection dropped mid-response.
+type payloadReader struct {
+	r     io.Reader
+	limit int64
+	read  int64
+	// readErr is the first error of the underlying reader other than io.EOF
+	readErr error
+}
+
+// newPayloadReader limits r to the parser's MaxPayloadSize.
+func (p *ArticulateParser) newPayloadReader(r io.Reader) *payloadReader {
+	pr := &payloadReader{r: r, limit: p.MaxPayloadSize}
+	if pr.limit > 0 {
+		// One byte beyond the limit tells a payload of exactly limit bytes from a larger one
+		pr.r = io.LimitReader(r, pr.limit+1)
+	}
+	return pr
+}
+
+// Read implements io.Reader and fails with a *PayloadTooLargeError once
+// more than limit bytes have been read.
+func (pr *payloadReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	pr.read += int64(n)
+	if pr.limit > 0 && pr.read > pr.limit {
+		return n, &PayloadTooLargeError{Limit: pr.limit}
+	}
+	if err != nil && !errors.Is(err, io.EOF) && pr.readErr == nil {
+		pr.readErr = err
+	}
+	return n, err
+}
+
+// checkSize fails early if the announced size of the payload, such as a
+// Content-Length header or a file size, exceeds the limit.
+func (pr *payloadReader) checkSize(size int64) error {
+	if pr.limit > 0 && size > pr.limit {
+		return &PayloadTooLargeError{Limit: pr.limit}
+	}
+	return nil
+}
+
+// wrapError classifies an error returned while decoding from the reader.
+func (pr *payloadReader) wrapError(err error) error {
+	var tooLarge *PayloadTooLargeError
+	switch {
+	case errors.As(err, &tooLarge):
+		return err
+	case pr.readErr != nil:
+		return fmt.Errorf("failed to read course data: %w", pr.readErr)
+	default:
+		return fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+}
+
+// streamCourse decodes a course from dec one lesson at a time, so that the
+// decoder only buffers the JSON of a single lesson rather than the whole
+// payload. The result is the same as that of json.Unmarshal.
+func streamCourse(dec *json.Decoder, course *models.Course) error {
+	return streamObject(dec, course, "course", func() error {
+		return streamObject(dec, &course.Course, "lessons", func() error {
+			return streamLessons(dec, &course.Course.Lessons)
+		})
+	})
+}
+
+// streamObject decodes a JSON object from dec into target. The value of the
+// key matching field, compared case-insensitively like json.Unmarshal does,
+// is left to decodeField; all other keys are unmarshaled into target at the
+// end. A JSON null leaves target unchanged.
+func streamObject(dec *json.Decoder, target any, field string, decodeField func() error) error {
+	tok, err := dec.Token()
+	if err != nil || tok == nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected an object for %T, got %v", target, tok)
+	}
+
+	var rest bytes.Buffer
+	rest.WriteByte('{')
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		if strings.EqualFold(key, field) {
+			if err := decodeField(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		if rest.Len() > 1 {
+			rest.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return err
+		}
+		rest.Write(name)
+		rest.WriteByte(':')
+		rest.Write(value)
+	}
+	// Consume the closing brace
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	rest.WriteByte('}')
+
+	return json.Unmarshal(rest.Bytes(), target)
+}
+
+// streamLessons decodes a JSON array of lessons from dec, one lesson at a time.
+func streamLessons(dec *json.Decoder, lessons *[]models.Lesson) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		*lessons = nil
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected an array of lessons, got %v", tok)
+	}
+
+	*lessons = []models.Lesson{}
+	for dec.More() {
+		var lesson models.Lesson
+		if err := dec.Decode(&lesson); err != nil {
+			return err
+		}
+		*lessons = append(*lessons, lesson)
+	}
+	// Consume the closing bracket
+	_, err = dec.Token()
+	return err
+}
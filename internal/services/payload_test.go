@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// TestStreamCourse tests that streaming decoding matches json.Unmarshal.
+func TestStreamCourse(t *testing.T) {
+	inputs := []string{
+		`{"shareId":"a","course":{"title":"T","lessons":[{"id":"l1","items":[{"type":"text"}]},{"id":"l2"}],"color":"#fff"},"author":"x"}`,
+		`{"Course":{"Lessons":[],"ID":"upper-case keys"},"ShareID":"b"}`,
+		`{"course":{"lessons":null,"exportSettings":{"title":"e"}}}`,
+		`{"course":null,"labelSet":{"labels":{"k":"v"}}}`,
+		`{}`,
+		`null`,
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			var expected, got models.Course
+			if err := json.Unmarshal([]byte(input), &expected); err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+			if err := streamCourse(json.NewDecoder(strings.NewReader(input)), &got); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !reflect.DeepEqual(expected, got) {
+				t.Errorf("Expected %+v, got %+v", expected, got)
+			}
+		})
+	}
+
+	for _, input := range []string{`{"course":[]}`, `{"course":{"lessons":{}}}`, `{"course":{"lessons":[1]}}`, `{"course":`} {
+		if err := streamCourse(json.NewDecoder(strings.NewReader(input)), &models.Course{}); err == nil {
+			t.Errorf("Expected error for %s", input)
+		}
+	}
+}
+
+// TestArticulateParser_MaxPayloadSize tests rejecting payloads above the limit.
+func TestArticulateParser_MaxPayloadSize(t *testing.T) {
+	input := `{"shareId":"limited","course":{"title":"Limited"}}`
+	parser := NewArticulateParser(nil, "", 0).(*ArticulateParser)
+
+	parser.MaxPayloadSize = int64(len(input))
+	if _, err := parser.LoadCourseFromReader(strings.NewReader(input)); err != nil {
+		t.Fatalf("Expected payload of exactly the limit to load, got: %v", err)
+	}
+
+	parser.MaxPayloadSize = int64(len(input) - 1)
+	_, err := parser.LoadCourseFromReader(strings.NewReader(input))
+	var tooLarge *PayloadTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != parser.MaxPayloadSize {
+		t.Fatalf("Expected PayloadTooLargeError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "payload too large") {
+		t.Errorf("Expected 'payload too large' in error, got '%s'", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "course.json")
+	if err := os.WriteFile(path, []byte(input), 0o600); err != nil {
+		t.Fatalf("Failed to write course: %v", err)
+	}
+	if _, err := parser.LoadCourseFromFile(path); !errors.As(err, &tooLarge) {
+		t.Errorf("Expected PayloadTooLargeError for file, got %v", err)
+	}
+
+	parser.MaxPayloadSize = 0
+	if _, err := parser.LoadCourseFromFile(path); err != nil {
+		t.Errorf("Expected no limit with a zero MaxPayloadSize, got: %v", err)
+	}
+
+	if _, err := parser.LoadCourseFromReader(strings.NewReader(input + `{"second":1}`)); err == nil || !strings.Contains(err.Error(), "failed to unmarshal JSON") {
+		t.Errorf("Expected trailing data to be rejected, got %v", err)
+	}
+}
+
+// TestArticulateParser_FetchCourse_MaxPayloadSize tests that oversized
+// responses fail without retrying, with and without a Content-Length.
+func TestArticulateParser_FetchCourse_MaxPayloadSize(t *testing.T) {
+	body := `{"shareId":"big","course":{"description":"` + strings.Repeat("x", 4096) + `"}}`
+
+	tests := []struct {
+		name    string
+		chunked bool
+	}{
+		{"content length", false},
+		{"chunked", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				if tt.chunked {
+					// Flushing before writing the body forces chunked encoding
+					w.(http.Flusher).Flush()
+				}
+				_, _ = w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			parser := newRetryTestParser(server.URL, 3)
+			parser.MaxPayloadSize = 1024
+
+			_, err := parser.FetchCourse(context.Background(), "https://rise.articulate.com/share/big")
+			var tooLarge *PayloadTooLargeError
+			if !errors.As(err, &tooLarge) {
+				t.Fatalf("Expected PayloadTooLargeError, got %v", err)
+			}
+			if requests.Load() != 1 {
+				t.Errorf("Expected 1 request, got %d", requests.Load())
+			}
+		})
+	}
+}
+
+// TestArticulateParser_FetchCourse_RetryTruncatedBody tests retrying a
+// response whose connection drops before the body is complete.
+func TestArticulateParser_FetchCourse_RetryTruncatedBody(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := `{"shareId":"truncated","course":{"title":"Complete"}}`
+		if requests.Add(1) == 1 {
+			// Announce more data than is sent so that the client sees an unexpected EOF
+			w.Header().Set("Content-Length", "1000")
+			_, _ = w.Write([]byte(body[:20]))
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	parser := newRetryTestParser(server.URL, 2)
+	course, err := parser.FetchCourse(context.Background(), "https://rise.articulate.com/share/truncated")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if course.Course.Title != "Complete" || requests.Load() != 2 {
+		t.Errorf("Expected complete course after 2 requests, got '%s' after %d", course.Course.Title, requests.Load())
+	}
+}
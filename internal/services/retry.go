@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how ArticulateParser retries transient fetch failures
+// such as 5xx responses, 429 throttling and network errors. The zero value
+// disables retries.
+type RetryPolicy struct {
+	// MaxRetries is the number of additional attempts after the first request
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles on each attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff between attempts. A Retry-After header asking
+	// for a longer wait ends the retries instead.
+	MaxDelay time.Duration
+}
+
+// isRetryableStatus reports whether an HTTP status code indicates a transient
+// failure that is worth retrying.
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before the given retry attempt (starting at 0).
+// It grows exponentially from BaseDelay, is capped at MaxDelay and applies
+// jitter so that concurrent clients do not retry in lockstep.
+func (r RetryPolicy) backoff(attempt int) time.Duration {
+	delay := r.BaseDelay
+	for i := 0; i < attempt && (r.MaxDelay <= 0 || delay < r.MaxDelay); i++ {
+		delay *= 2
+	}
+	if r.MaxDelay > 0 && delay > r.MaxDelay {
+		delay = r.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// Equal jitter: wait at least half the delay, plus a random share of the rest
+	half := delay / 2
+	return half + rand.N(delay-half+1) // #nosec G404 - jitter does not need a secure source
+}
+
+// retryDelay returns how long to wait before retrying a response. It honours
+// the Retry-After header when present and falls back to exponential backoff.
+// It returns false if the server asks to wait longer than MaxDelay.
+func (r RetryPolicy) retryDelay(attempt int, header http.Header) (time.Duration, bool) {
+	if wait, ok := parseRetryAfter(header.Get("Retry-After"), time.Now()); ok {
+		if r.MaxDelay > 0 && wait > r.MaxDelay {
+			return 0, false
+		}
+		return wait, true
+	}
+	return r.backoff(attempt), true
+}
+
+// parseRetryAfter parses a Retry-After header value, which is either a number
+// of seconds or an HTTP date, into a wait duration relative to now.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleepContext waits for the given duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/kjanat/articulate-parser/internal/config"
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// newRetryTestParser creates a parser for the test server with a fast retry policy.
+func newRetryTestParser(serverURL string, maxRetries int) *ArticulateParser {
+	return &ArticulateParser{
+		BaseURL: serverURL,
+		Client:  &http.Client{Timeout: 5 * time.Second},
+		Logger:  NewNoOpLogger(),
+		Retry: RetryPolicy{
+			MaxRetries: maxRetries,
+			BaseDelay:  time.Millisecond,
+			MaxDelay:   50 * time.Millisecond,
+		},
+	}
+}
+
+// TestArticulateParser_FetchCourse_Retry tests retrying transient failures.
+func TestArticulateParser_FetchCourse_Retry(t *testing.T) {
+	testCourse := &models.Course{ShareID: "retry-share-id"}
+
+	tests := []struct {
+		name          string
+		failures      []int
+		maxRetries    int
+		expectedCalls int32
+		expectedError string
+	}{
+		{
+			name:          "recovers from server errors",
+			failures:      []int{http.StatusServiceUnavailable, http.StatusBadGateway},
+			maxRetries:    3,
+			expectedCalls: 3,
+		},
+		{
+			name:          "recovers from throttling",
+			failures:      []int{http.StatusTooManyRequests},
+			maxRetries:    1,
+			expectedCalls: 2,
+		},
+		{
+			name:          "gives up after max retries",
+			failures:      []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			maxRetries:    2,
+			expectedCalls: 3,
+			expectedError: "API returned status 500",
+		},
+		{
+			name:          "does not retry client errors",
+			failures:      []int{http.StatusNotFound},
+			maxRetries:    3,
+			expectedCalls: 1,
+			expectedError: "API returned status 404",
+		},
+		{
+			name:          "no retries by default",
+			failures:      []int{http.StatusServiceUnavailable},
+			maxRetries:    0,
+			expectedCalls: 1,
+			expectedError: "API returned status 503",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(calls.Add(1))
+				if n <= len(tt.failures) {
+					http.Error(w, "transient failure", tt.failures[n-1])
+					return
+				}
+				_ = json.NewEncoder(w).Encode(testCourse)
+			}))
+			defer server.Close()
+
+			parser := newRetryTestParser(server.URL, tt.maxRetries)
+			course, err := parser.FetchCourse(context.Background(), "https://rise.articulate.com/share/retry-share-id")
+
+			if got := calls.Load(); got != tt.expectedCalls {
+				t.Errorf("Expected %d requests, got %d", tt.expectedCalls, got)
+			}
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("Expected error containing '%s', got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if course.ShareID != testCourse.ShareID {
+				t.Errorf("Expected ShareID '%s', got '%s'", testCourse.ShareID, course.ShareID)
+			}
+		})
+	}
+}
+
+// TestArticulateParser_FetchCourse_RetryAfter tests honouring the Retry-After header.
+func TestArticulateParser_FetchCourse_RetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&models.Course{ShareID: "after"})
+	}))
+	defer server.Close()
+
+	parser := newRetryTestParser(server.URL, 1)
+	parser.Retry.MaxDelay = 2 * time.Second
+
+	start := time.Now()
+	if _, err := parser.FetchCourse(context.Background(), "https://rise.articulate.com/share/after"); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected to wait for Retry-After (1s), waited %v", elapsed)
+	}
+
+	// A Retry-After beyond MaxDelay ends the retries instead of blocking
+	calls.Store(0)
+	parser.Retry.MaxDelay = 100 * time.Millisecond
+	_, err := parser.FetchCourse(context.Background(), "https://rise.articulate.com/share/after")
+	if err == nil || !strings.Contains(err.Error(), "API returned status 429") {
+		t.Errorf("Expected 429 error, got %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+}
+
+// TestArticulateParser_FetchCourse_RetryContextCancel tests cancellation during backoff.
+func TestArticulateParser_FetchCourse_RetryContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	parser := newRetryTestParser(server.URL, 10)
+	parser.Retry.BaseDelay = time.Second
+	parser.Retry.MaxDelay = time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := parser.FetchCourse(ctx, "https://rise.articulate.com/share/cancel")
+	if err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Errorf("Expected context deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected cancellation to interrupt backoff, took %v", elapsed)
+	}
+}
+
+// TestArticulateParser_FetchCourse_RateLimit tests the shared client-side rate limiter.
+func TestArticulateParser_FetchCourse_RateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(&models.Course{ShareID: "limited"})
+	}))
+	defer server.Close()
+
+	parser := newRetryTestParser(server.URL, 0)
+	parser.Limiter = rate.NewLimiter(rate.Limit(20), 1)
+
+	start := time.Now()
+	for range 3 {
+		if _, err := parser.FetchCourse(context.Background(), "https://rise.articulate.com/share/limited"); err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+	}
+
+	// At 20 requests per second with a burst of 1, three requests need at least 100ms
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected rate limiter to space out requests, took %v", elapsed)
+	}
+}
+
+// TestRetryPolicy_Backoff tests the exponential backoff with jitter.
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{2, 400 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, time.Second},
+		{50, time.Second},
+	}
+
+	for _, tt := range tests {
+		for range 20 {
+			delay := policy.backoff(tt.attempt)
+			if delay < tt.max/2 || delay > tt.max {
+				t.Errorf("backoff(%d) = %v, want between %v and %v", tt.attempt, delay, tt.max/2, tt.max)
+			}
+		}
+	}
+
+	if delay := (RetryPolicy{}).backoff(3); delay != 0 {
+		t.Errorf("Expected zero backoff for zero policy, got %v", delay)
+	}
+}
+
+// TestParseRetryAfter tests parsing of Retry-After header values.
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"seconds", "5", 5 * time.Second, true},
+		{"http date", "Wed, 01 Jan 2025 12:00:30 GMT", 30 * time.Second, true},
+		{"past http date", "Wed, 01 Jan 2025 11:00:00 GMT", 0, true},
+		{"empty", "", 0, false},
+		{"negative", "-1", 0, false},
+		{"invalid", "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := parseRetryAfter(tt.value, now)
+			if ok != tt.ok || result != tt.expected {
+				t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, result, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+// TestNewArticulateParserFromConfig tests wiring retry, rate limit, cache and share host configuration.
+func TestNewArticulateParserFromConfig(t *testing.T) {
+	cfg := &config.Config{
+		BaseURL:        "https://custom.example.com",
+		RequestTimeout: 10 * time.Second,
+		MaxRetries:     4,
+		RetryBaseDelay: 200 * time.Millisecond,
+		RetryMaxDelay:  5 * time.Second,
+		RateLimit:      2,
+		RateBurst:      0,
+		MaxPayloadSize: 4096,
+	}
+
+	parser, ok := NewArticulateParserFromConfig(nil, cfg, nil).(*ArticulateParser)
+	if !ok {
+		t.Fatal("NewArticulateParserFromConfig() returned wrong type")
+	}
+
+	if parser.BaseURL != cfg.BaseURL {
+		t.Errorf("Expected BaseURL '%s', got '%s'", cfg.BaseURL, parser.BaseURL)
+	}
+	if parser.Client.Timeout != cfg.RequestTimeout {
+		t.Errorf("Expected timeout %v, got %v", cfg.RequestTimeout, parser.Client.Timeout)
+	}
+	if parser.MaxPayloadSize != cfg.MaxPayloadSize {
+		t.Errorf("Expected max payload size %d, got %d", cfg.MaxPayloadSize, parser.MaxPayloadSize)
+	}
+	expectedPolicy := RetryPolicy{MaxRetries: 4, BaseDelay: 200 * time.Millisecond, MaxDelay: 5 * time.Second}
+	if parser.Retry != expectedPolicy {
+		t.Errorf("Expected retry policy %+v, got %+v", expectedPolicy, parser.Retry)
+	}
+	if parser.Limiter == nil {
+		t.Fatal("Expected rate limiter to be configured")
+	}
+	if parser.Limiter.Burst() != 1 {
+		t.Errorf("Expected burst to be raised to 1, got %d", parser.Limiter.Burst())
+	}
+
+	if parser.Cache != nil || parser.Offline {
+		t.Error("Expected no cache when cache directory is empty")
+	}
+
+	if parser.Hosts != nil {
+		t.Errorf("Expected no additional hosts, got %v", parser.Hosts)
+	}
+
+	cfg.RateLimit = 0
+	cfg.ShareHosts = map[string]string{"Rise.Example.com": "https://api.example.com/"}
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	parser = NewArticulateParserFromConfig(nil, cfg, nil).(*ArticulateParser)
+	if parser.Limiter != nil {
+		t.Error("Expected no rate limiter when rate limit is zero")
+	}
+	if parser.Cache == nil || parser.Cache.Dir != cfg.CacheDir {
+		t.Errorf("Expected cache in '%s', got %+v", cfg.CacheDir, parser.Cache)
+	}
+	if !parser.Offline {
+		t.Error("Expected offline mode to be enabled")
+	}
+	if got := parser.Hosts["rise.example.com"]; got != "https://api.example.com" {
+		t.Errorf("Expected normalized host mapping, got %v", parser.Hosts)
+	}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// Model types that carry location information for schema issues.
+var (
+	schemaCourseType = reflect.TypeFor[models.Course]()
+	schemaLessonType = reflect.TypeFor[models.Lesson]()
+	schemaItemType   = reflect.TypeFor[models.Item]()
+)
+
+// SchemaDriftError is returned in strict mode when course JSON contains
+// keys or items that the models and exporters do not recognize.
+type SchemaDriftError struct {
+	// Issues lists every difference found, in document order
+	Issues []models.SchemaIssue
+}
+
+// Error implements the error interface.
+func (e *SchemaDriftError) Error() string {
+	return fmt.Sprintf("course JSON does not match the known schema: %d issue(s), first: %s",
+		len(e.Issues), FormatSchemaIssue(e.Issues[0]))
+}
+
+// CheckSchema compares course JSON with the fields of the models and reports
+// keys that json.Unmarshal would silently ignore, as well as items whose type
+// or variant the exporters do not recognize. Values of fields typed any, such
+// as Item.Settings and Item.Data, are not checked.
+// Returns an error if data is not valid JSON.
+func CheckSchema(data []byte) ([]models.SchemaIssue, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	c := &schemaChecker{fields: make(map[reflect.Type]map[string]reflect.Type)}
+	c.walk(value, schemaCourseType, schemaLocation{})
+	return c.issues, nil
+}
+
+// FormatSchemaIssue renders an issue as a single line, e.g.
+// `course.lessons[0].items[1] (lesson abc, item def): unknown field "transcript"`.
+func FormatSchemaIssue(issue models.SchemaIssue) string {
+	var ids []string
+	if issue.LessonID != "" {
+		ids = append(ids, "lesson "+issue.LessonID)
+	}
+	if issue.ItemID != "" {
+		ids = append(ids, "item "+issue.ItemID)
+	}
+	location := issue.Path
+	if location == "" {
+		location = "(root)"
+	}
+	if len(ids) > 0 {
+		location += " (" + strings.Join(ids, ", ") + ")"
+	}
+	return location + ": " + issue.Message
+}
+
+// WriteSchemaReport writes one line per issue followed by a summary line.
+func WriteSchemaReport(w io.Writer, issues []models.SchemaIssue) error {
+	for _, issue := range issues {
+		if _, err := fmt.Fprintln(w, FormatSchemaIssue(issue)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d schema issue(s) found\n", len(issues))
+	return err
+}
+
+// schemaLocation tracks where in the course JSON the checker is.
+type schemaLocation struct {
+	path     string
+	lessonID string
+	itemID   string
+}
+
+// field returns the location of an object key.
+func (l schemaLocation) field(key string) schemaLocation {
+	if l.path != "" {
+		key = l.path + "." + key
+	}
+	l.path = key
+	return l
+}
+
+// index returns the location of an array element.
+func (l schemaLocation) index(i int) schemaLocation {
+	l.path += "[" + strconv.Itoa(i) + "]"
+	return l
+}
+
+// schemaChecker walks decoded JSON alongside the model types.
+type schemaChecker struct {
+	issues []models.SchemaIssue
+	// fields caches the lower-cased JSON field names of each struct type
+	fields map[reflect.Type]map[string]reflect.Type
+}
+
+// walk checks value against the model type t.
+func (c *schemaChecker) walk(value any, t reflect.Type, loc schemaLocation) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+		switch t {
+		case schemaLessonType:
+			loc.lessonID = stringValue(obj["id"])
+			loc.itemID = ""
+		case schemaItemType:
+			loc.itemID = stringValue(obj["id"])
+			c.checkItem(obj, loc)
+		}
+
+		// Keys are sorted so that reports are stable between runs
+		fields := c.structFields(t)
+		for _, key := range slices.Sorted(maps.Keys(obj)) {
+			// encoding/json matches keys case-insensitively
+			fieldType, ok := fields[strings.ToLower(key)]
+			if !ok {
+				c.add(models.SchemaIssueUnknownField, loc.field(key), fmt.Sprintf("unknown field %q", key))
+				continue
+			}
+			c.walk(obj[key], fieldType, loc.field(key))
+		}
+	case reflect.Slice, reflect.Array:
+		list, ok := value.([]any)
+		if !ok {
+			return
+		}
+		for i, elem := range list {
+			c.walk(elem, t.Elem(), loc.index(i))
+		}
+	case reflect.Map:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+		for _, key := range slices.Sorted(maps.Keys(obj)) {
+			c.walk(obj[key], t.Elem(), loc.field(key))
+		}
+	}
+}
+
+// checkItem reports items whose type and variant are not recognized.
+func (c *schemaChecker) checkItem(obj map[string]any, loc schemaLocation) {
+	item := models.Item{Type: stringValue(obj["type"]), Variant: stringValue(obj["variant"])}
+	if DecodeItemDetails(&item).Known {
+		return
+	}
+	c.add(models.SchemaIssueUnknownItem, loc,
+		fmt.Sprintf("unknown item type %q with variant %q", item.Type, item.Variant))
+}
+
+// add records an issue at the given location.
+func (c *schemaChecker) add(kind string, loc schemaLocation, message string) {
+	c.issues = append(c.issues, models.SchemaIssue{
+		Kind:     kind,
+		Path:     loc.path,
+		LessonID: loc.lessonID,
+		ItemID:   loc.itemID,
+		Message:  message,
+	})
+}
+
+// structFields returns the JSON field names of a struct type, lower-cased,
+// mapped to their types. Fields tagged "-" are excluded since they never decode.
+func (c *schemaChecker) structFields(t reflect.Type) map[string]reflect.Type {
+	if fields, ok := c.fields[t]; ok {
+		return fields
+	}
+
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+	c.fields[t] = fields
+	return fields
+}
+
+// stringValue returns v if it is a JSON string and "" otherwise.
+func stringValue(v any) string {
+	s, _ := v.(string)
+	return s
+}
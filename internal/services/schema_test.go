@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// driftedCourseJSON contains an unknown course field, an unknown sub-item
+// field and an item type the exporters do not recognize.
+const driftedCourseJSON = `{
+	"shareId": "drift",
+	"Author": "case-insensitive match",
+	"course": {
+		"id": "course-1",
+		"title": "Drift",
+		"newFeature": true,
+		"lessons": [{
+			"id": "lesson-1",
+			"items": [
+				{"id": "item-1", "type": "text", "settings": {"anything": 1}, "items": [{"id": "s1", "transcript": "t"}]},
+				{"id": "item-2", "type": "timeline", "variant": "vertical"}
+			]
+		}]
+	}
+}`
+
+// TestCheckSchema tests reporting unknown fields and items with their locations.
+func TestCheckSchema(t *testing.T) {
+	issues, err := CheckSchema([]byte(driftedCourseJSON))
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []models.SchemaIssue{
+		{
+			Kind:     models.SchemaIssueUnknownField,
+			Path:     "course.lessons[0].items[0].items[0].transcript",
+			LessonID: "lesson-1",
+			ItemID:   "item-1",
+			Message:  `unknown field "transcript"`,
+		},
+		{
+			Kind:     models.SchemaIssueUnknownItem,
+			Path:     "course.lessons[0].items[1]",
+			LessonID: "lesson-1",
+			ItemID:   "item-2",
+			Message:  `unknown item type "timeline" with variant "vertical"`,
+		},
+		{
+			Kind:    models.SchemaIssueUnknownField,
+			Path:    "course.newFeature",
+			Message: `unknown field "newFeature"`,
+		},
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues, got %d: %+v", len(expected), len(issues), issues)
+	}
+	for i := range expected {
+		if issues[i] != expected[i] {
+			t.Errorf("Issue %d: expected %+v, got %+v", i, expected[i], issues[i])
+		}
+	}
+
+	if _, err := CheckSchema([]byte("{not json")); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
+
+// TestWriteSchemaReport tests the textual schema report.
+func TestWriteSchemaReport(t *testing.T) {
+	issues := []models.SchemaIssue{
+		{Path: "course.lessons[0].items[1]", LessonID: "l1", ItemID: "i2", Message: "unknown item"},
+		{Path: "", Message: `unknown field "x"`},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSchemaReport(&buf, issues); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := "course.lessons[0].items[1] (lesson l1, item i2): unknown item\n" +
+		"(root): unknown field \"x\"\n" +
+		"2 schema issue(s) found\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, buf.String())
+	}
+}
+
+// TestArticulateParser_Strict tests that strict mode rejects drifted course JSON.
+func TestArticulateParser_Strict(t *testing.T) {
+	parser := NewArticulateParser(nil, "", 0).(*ArticulateParser)
+
+	if _, err := parser.LoadCourseFromReader(strings.NewReader(driftedCourseJSON)); err != nil {
+		t.Fatalf("Expected drift to be ignored outside strict mode, got: %v", err)
+	}
+
+	parser.Strict = true
+	_, err := parser.LoadCourseFromReader(strings.NewReader(driftedCourseJSON))
+	var drift *SchemaDriftError
+	if !errors.As(err, &drift) {
+		t.Fatalf("Expected SchemaDriftError, got %v", err)
+	}
+	if len(drift.Issues) != 3 || !strings.Contains(err.Error(), "3 issue(s)") {
+		t.Errorf("Expected 3 issues, got %d: %v", len(drift.Issues), err)
+	}
+
+	// Embedded course data is checked as well
+	page := filepath.Join(t.TempDir(), "share.html")
+	html := `<html><script type="application/json">` + driftedCourseJSON + `</script></html>`
+	if err := os.WriteFile(page, []byte(html), 0o600); err != nil {
+		t.Fatalf("Failed to write share page: %v", err)
+	}
+	if _, err := parser.LoadCourseFromFile(page); !errors.As(err, &drift) {
+		t.Errorf("Expected SchemaDriftError for share page, got %v", err)
+	}
+
+	clean := `{"shareId":"ok","course":{"id":"c","lessons":[{"id":"l","items":[{"type":"list","variant":"numbered"}]}]}}`
+	if _, err := parser.LoadCourseFromReader(strings.NewReader(clean)); err != nil {
+		t.Errorf("Expected known schema to pass strict mode, got: %v", err)
+	}
+}
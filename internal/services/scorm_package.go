@@ -0,0 +1,235 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// packageAssetsDir is the directory Rise places bundled media files in.
+const packageAssetsDir = "assets/"
+
+// embeddedCourseRegexes match the places where Rise serializes the course data
+// into the runtime files of an "Export for LMS" package. The first capture group
+// holds the base64-encoded course JSON.
+var embeddedCourseRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`deserialize\(\s*["']([A-Za-z0-9+/=]+)["']\s*\)`),
+	regexp.MustCompile(`courseData\s*[=:]\s*["']([A-Za-z0-9+/=]+)["']`),
+}
+
+// isPackageFile reports whether the file path refers to a zip package.
+func isPackageFile(filePath string) bool {
+	return strings.EqualFold(filepath.Ext(filePath), ".zip")
+}
+
+// loadCourseFromPackage loads a course from a Rise SCORM 1.2/2004 zip package.
+// It searches the package's runtime files for the embedded course data,
+// decodes it and records the bundled media files on the returned course.
+func (p *ArticulateParser) loadCourseFromPackage(filePath string) (*models.Course, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open package: %w", err)
+	}
+	// The archive is only read from, so a close error cannot affect the result.
+	defer func() {
+		if err := zr.Close(); err != nil {
+			p.Logger.Warn("failed to close package", "error", err, "path", filePath)
+		}
+	}()
+
+	for _, f := range runtimeFiles(zr.File) {
+		data, err := p.readZipFile(f)
+		var tooLarge *PayloadTooLargeError
+		if errors.As(err, &tooLarge) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from package: %w", f.Name, err)
+		}
+
+		course, raw, ok := decodeEmbeddedCourse(data)
+		if !ok {
+			continue
+		}
+		if err := p.checkSchema(raw); err != nil {
+			return nil, err
+		}
+
+		p.Logger.Debug("found embedded course data", "path", filePath, "file", f.Name)
+		course.Package = &models.SourcePackage{
+			Path:  filePath,
+			Media: indexPackageMedia(zr.File),
+		}
+		return course, nil
+	}
+
+	return nil, fmt.Errorf("no course data found in package: %s", filePath)
+}
+
+// runtimeFiles returns the HTML and JavaScript files of a package that may hold
+// the embedded course data. Entry pages named index.html are returned first
+// since that is where Rise usually places the data.
+func runtimeFiles(files []*zip.File) []*zip.File {
+	var result []*zip.File
+	for _, f := range files {
+		switch strings.ToLower(path.Ext(f.Name)) {
+		case ".html", ".htm", ".js":
+			result = append(result, f)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return isIndexPage(result[i].Name) && !isIndexPage(result[j].Name)
+	})
+	return result
+}
+
+// isIndexPage reports whether a package entry is an index.html page.
+func isIndexPage(name string) bool {
+	return strings.EqualFold(path.Base(name), "index.html")
+}
+
+// readZipFile reads the contents of a package entry. Entries larger than
+// MaxPayloadSize fail with a *PayloadTooLargeError, since they may hold the
+// course data.
+func (p *ArticulateParser) readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rc.Close() // Read-only; a close error cannot invalidate the data already read.
+	}()
+
+	pr := p.newPayloadReader(rc)
+	if err := pr.checkSize(int64(min(f.UncompressedSize64, math.MaxInt64))); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(pr)
+}
+
+// decodeEmbeddedCourse looks for a serialized course in the given file content
+// and returns it together with its JSON.
+// It returns false if no candidate decodes to a course with content.
+func decodeEmbeddedCourse(data []byte) (*models.Course, []byte, bool) {
+	for _, re := range embeddedCourseRegexes {
+		for _, match := range re.FindAllSubmatch(data, -1) {
+			raw, err := base64.StdEncoding.DecodeString(string(match[1]))
+			if err != nil {
+				continue
+			}
+
+			var course models.Course
+			if err := json.Unmarshal(raw, &course); err != nil {
+				continue
+			}
+			if !hasCourseContent(&course) {
+				continue
+			}
+			DecodeCourseDetails(&course)
+			return &course, raw, true
+		}
+	}
+	return nil, nil, false
+}
+
+// indexPackageMedia maps the media files bundled in a package to their paths
+// inside the archive. Each file is indexed by its path relative to the assets
+// directory and by its base name, since course data may reference either.
+func indexPackageMedia(files []*zip.File) map[string]string {
+	media := make(map[string]string)
+	for _, f := range files {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		idx := assetsDirIndex(f.Name)
+		if idx < 0 {
+			continue
+		}
+
+		media[f.Name[idx:]] = f.Name
+		media[f.Name[idx+len(packageAssetsDir):]] = f.Name
+		if _, exists := media[path.Base(f.Name)]; !exists {
+			media[path.Base(f.Name)] = f.Name
+		}
+	}
+	return media
+}
+
+// assetsDirIndex returns the index in name of the assets directory, which
+// must be a whole path segment such as "assets/" or "scormcontent/assets/",
+// or -1 if name is not inside one.
+func assetsDirIndex(name string) int {
+	if strings.HasPrefix(name, packageAssetsDir) {
+		return 0
+	}
+	if idx := strings.Index(name, "/"+packageAssetsDir); idx >= 0 {
+		return idx + 1
+	}
+	return -1
+}
+
+// ResolvePackageMedia returns the path inside the source package of the media
+// file identified by key, which is typically an ImageMedia or VideoMedia key
+// or URL. It returns false if the course was not loaded from a package or the
+// media is not bundled in it.
+func ResolvePackageMedia(pkg *models.SourcePackage, key string) (string, bool) {
+	if pkg == nil || key == "" {
+		return "", false
+	}
+
+	if name, ok := pkg.Media[key]; ok {
+		return name, true
+	}
+	name, ok := pkg.Media[path.Base(key)]
+	return name, ok
+}
+
+// OpenPackageMedia opens the media file identified by key from the course's
+// source package. The caller must close the returned reader.
+func OpenPackageMedia(pkg *models.SourcePackage, key string) (io.ReadCloser, error) {
+	name, ok := ResolvePackageMedia(pkg, key)
+	if !ok {
+		return nil, fmt.Errorf("media not found in package: %s", key)
+	}
+
+	zr, err := zip.OpenReader(pkg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open package: %w", err)
+	}
+
+	rc, err := zr.Open(name)
+	if err != nil {
+		_ = zr.Close()
+		return nil, fmt.Errorf("failed to open %s from package: %w", name, err)
+	}
+
+	return &packageFile{ReadCloser: rc, archive: zr}, nil
+}
+
+// packageFile is a file inside a package that closes the archive along with itself.
+type packageFile struct {
+	io.ReadCloser
+	archive *zip.ReadCloser
+}
+
+// Close closes both the file and the archive it was opened from.
+func (f *packageFile) Close() error {
+	fileErr := f.ReadCloser.Close()
+	if err := f.archive.Close(); err != nil {
+		return err
+	}
+	return fileErr
+}
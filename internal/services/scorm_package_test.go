@@ -0,0 +1,239 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// writeTestPackage creates a zip package in dir containing the given files.
+func writeTestPackage(t *testing.T, dir string, files map[string]string) string {
+	t.Helper()
+
+	pkgPath := filepath.Join(dir, "course-scorm12.zip")
+	f, err := os.Create(pkgPath)
+	if err != nil {
+		t.Fatalf("Failed to create package: %v", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to create %s in package: %v", name, err)
+		}
+		if _, err := io.WriteString(w, content); err != nil {
+			t.Fatalf("Failed to write %s in package: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to finalize package: %v", err)
+	}
+
+	return pkgPath
+}
+
+// encodeTestCourse returns the base64-encoded JSON of a course, as Rise embeds it.
+func encodeTestCourse(t *testing.T, course *models.Course) string {
+	t.Helper()
+
+	data, err := json.Marshal(course)
+	if err != nil {
+		t.Fatalf("Failed to marshal test course: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// TestArticulateParser_LoadCourseFromFile_Package tests loading a Rise SCORM package.
+func TestArticulateParser_LoadCourseFromFile_Package(t *testing.T) {
+	testCourse := &models.Course{
+		Course: models.CourseInfo{
+			ID:    "package-course-id",
+			Title: "Package Course",
+			Lessons: []models.Lesson{
+				{ID: "lesson-1", Title: "First Lesson", Type: "blocks"},
+			},
+		},
+	}
+
+	indexHTML := `<!DOCTYPE html><html><head>
+<script>window.courseData = deserialize("` + encodeTestCourse(t, testCourse) + `");</script>
+</head><body></body></html>`
+
+	pkgPath := writeTestPackage(t, t.TempDir(), map[string]string{
+		"imsmanifest.xml":                        "<manifest/>",
+		"scormdriver/indexAPI.html":              "<html></html>",
+		"scormcontent/lib/main.bundle.js":        `var x = deserialize("bm90IGpzb24=");`,
+		"scormcontent/index.html":                indexHTML,
+		"scormcontent/assets/cover.jpg":          "jpeg",
+		"scormcontent/assets/videos/intro.mp4":   "mp4",
+		"scormcontent/assets/nested/diagram.png": "png",
+		"scormcontent/myassets/logo.png":         "not media",
+		"scormcontent/lib/oldassets/banner.png":  "not media",
+	})
+
+	parser := NewArticulateParser(nil, "", 0)
+	course, err := parser.LoadCourseFromFile(pkgPath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if course.Course.ID != testCourse.Course.ID {
+		t.Errorf("Expected course ID '%s', got '%s'", testCourse.Course.ID, course.Course.ID)
+	}
+	if len(course.Course.Lessons) != 1 {
+		t.Fatalf("Expected 1 lesson, got %d", len(course.Course.Lessons))
+	}
+	if course.Package == nil {
+		t.Fatal("Expected package information, got nil")
+	}
+	if course.Package.Path != pkgPath {
+		t.Errorf("Expected package path '%s', got '%s'", pkgPath, course.Package.Path)
+	}
+
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"cover.jpg", "scormcontent/assets/cover.jpg"},
+		{"assets/cover.jpg", "scormcontent/assets/cover.jpg"},
+		{"videos/intro.mp4", "scormcontent/assets/videos/intro.mp4"},
+		{"https://cdn.example.com/rise/courses/abc/diagram.png", "scormcontent/assets/nested/diagram.png"},
+	}
+	for _, tt := range tests {
+		name, ok := ResolvePackageMedia(course.Package, tt.key)
+		if !ok {
+			t.Errorf("Expected media '%s' to be found in package", tt.key)
+			continue
+		}
+		if name != tt.expected {
+			t.Errorf("Expected media '%s' at '%s', got '%s'", tt.key, tt.expected, name)
+		}
+	}
+
+	if _, ok := ResolvePackageMedia(course.Package, "missing.png"); ok {
+		t.Error("Expected missing media not to be found")
+	}
+	// Directories whose names only end in "assets" hold no media
+	for _, key := range []string{"logo.png", "banner.png", "myassets/logo.png"} {
+		if name, ok := ResolvePackageMedia(course.Package, key); ok {
+			t.Errorf("Expected '%s' not to be indexed as media, got '%s'", key, name)
+		}
+	}
+}
+
+// TestArticulateParser_LoadCourseFromFile_PackageErrors tests package error handling.
+func TestArticulateParser_LoadCourseFromFile_PackageErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	parser := NewArticulateParser(nil, "", 0)
+
+	tests := []struct {
+		name          string
+		setup         func() string
+		expectedError string
+	}{
+		{
+			name:          "nonexistent package",
+			setup:         func() string { return filepath.Join(tempDir, "missing.zip") },
+			expectedError: "failed to open package",
+		},
+		{
+			name: "not a zip file",
+			setup: func() string {
+				path := filepath.Join(tempDir, "broken.zip")
+				if err := os.WriteFile(path, []byte("not a zip"), 0o644); err != nil {
+					t.Fatalf("Failed to write test file: %v", err)
+				}
+				return path
+			},
+			expectedError: "failed to open package",
+		},
+		{
+			name: "package without course data",
+			setup: func() string {
+				return writeTestPackage(t, t.TempDir(), map[string]string{
+					"scormcontent/index.html": "<html><body>No data here</body></html>",
+				})
+			},
+			expectedError: "no course data found in package",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parser.LoadCourseFromFile(tt.setup())
+			if err == nil {
+				t.Fatalf("Expected error containing '%s', got nil", tt.expectedError)
+			}
+			if !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("Expected error containing '%s', got '%s'", tt.expectedError, err.Error())
+			}
+		})
+	}
+}
+
+// TestArticulateParser_LoadCourseFromFile_PackageTooLarge tests that runtime
+// files larger than MaxPayloadSize are rejected rather than truncated.
+func TestArticulateParser_LoadCourseFromFile_PackageTooLarge(t *testing.T) {
+	course := &models.Course{Course: models.CourseInfo{
+		Title:   "Large Course",
+		Lessons: []models.Lesson{{ID: "lesson-1", Title: "First Lesson", Type: "blocks"}},
+	}}
+	indexHTML := `<script>window.courseData = deserialize("` + encodeTestCourse(t, course) + `");</script>`
+	pkgPath := writeTestPackage(t, t.TempDir(), map[string]string{"scormcontent/index.html": indexHTML})
+	parser := NewArticulateParser(nil, "", 0).(*ArticulateParser)
+
+	parser.MaxPayloadSize = int64(len(indexHTML))
+	if _, err := parser.LoadCourseFromFile(pkgPath); err != nil {
+		t.Fatalf("Expected runtime file of exactly the limit to load, got: %v", err)
+	}
+
+	parser.MaxPayloadSize = int64(len(indexHTML) - 1)
+	_, err := parser.LoadCourseFromFile(pkgPath)
+	var tooLarge *PayloadTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != parser.MaxPayloadSize {
+		t.Errorf("Expected PayloadTooLargeError, got %v", err)
+	}
+}
+
+// TestOpenPackageMedia tests reading bundled media from a package.
+func TestOpenPackageMedia(t *testing.T) {
+	pkgPath := writeTestPackage(t, t.TempDir(), map[string]string{
+		"scormcontent/assets/cover.jpg": "jpeg-bytes",
+	})
+	pkg := &models.SourcePackage{
+		Path:  pkgPath,
+		Media: map[string]string{"cover.jpg": "scormcontent/assets/cover.jpg"},
+	}
+
+	rc, err := OpenPackageMedia(pkg, "cover.jpg")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("Failed to read media: %v", err)
+	}
+	if err := rc.Close(); err != nil {
+		t.Errorf("Failed to close media: %v", err)
+	}
+	if string(data) != "jpeg-bytes" {
+		t.Errorf("Expected media content 'jpeg-bytes', got '%s'", string(data))
+	}
+
+	if _, err := OpenPackageMedia(pkg, "missing.jpg"); err == nil {
+		t.Error("Expected error for missing media, got nil")
+	}
+	if _, err := OpenPackageMedia(nil, "cover.jpg"); err == nil {
+		t.Error("Expected error for nil package, got nil")
+	}
+}
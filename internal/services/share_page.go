@@ -0,0 +1,162 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// Patterns locating course data embedded in a saved share page script.
+var (
+	// jsonParseRegex matches a JSON.parse call with a string literal argument.
+	jsonParseRegex = regexp.MustCompile(`JSON\.parse\(\s*("(?:[^"\\]|\\.)*")\s*\)`)
+	// objectLiteralRegex matches the start of an object assigned or passed as an argument.
+	objectLiteralRegex = regexp.MustCompile(`[=(:]\s*\{`)
+)
+
+// isSharePageFile reports whether the file path refers to a saved HTML page.
+func isSharePageFile(filePath string) bool {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".html", ".htm":
+		return true
+	default:
+		return false
+	}
+}
+
+// loadCourseFromSharePage loads a course from a Rise share page saved from a
+// browser. Saved pages carry the course payload in an inline script instead of
+// fetching it from the boot API, so this works even after the share link has
+// been revoked.
+func (p *ArticulateParser) loadCourseFromSharePage(filePath string) (*models.Course, error) {
+	// #nosec G304 - File path is provided by user via CLI argument, which is expected behavior
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			p.Logger.Warn("failed to close share page", "error", err, "path", filePath)
+		}
+	}()
+
+	course, raw, err := parseSharePage(p.newPayloadReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse share page %s: %w", filePath, err)
+	}
+	if err := p.checkSchema(raw); err != nil {
+		return nil, err
+	}
+	return course, nil
+}
+
+// ParseSharePage extracts the course embedded in a saved Rise share page.
+// It inspects every inline script of the page and accepts the course data
+// as a JSON script block, an object literal assigned in JavaScript, a
+// JSON.parse string literal or a base64 blob as used by Rise packages.
+func ParseSharePage(r io.Reader) (*models.Course, error) {
+	course, _, err := parseSharePage(r)
+	return course, err
+}
+
+// parseSharePage implements ParseSharePage and also returns the course JSON.
+func parseSharePage(r io.Reader) (*models.Course, []byte, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	var scripts []string
+	collectScripts(doc, &scripts)
+
+	for _, script := range scripts {
+		if course, raw, ok := decodeScriptCourse(script); ok {
+			return course, raw, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("no embedded course data found in %d scripts", len(scripts))
+}
+
+// collectScripts recursively gathers the text content of all inline script elements.
+func collectScripts(n *html.Node, scripts *[]string) {
+	if n.Type == html.ElementNode && n.Data == "script" {
+		var sb strings.Builder
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				sb.WriteString(c.Data)
+			}
+		}
+		if text := strings.TrimSpace(sb.String()); text != "" {
+			*scripts = append(*scripts, text)
+		}
+		return
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		collectScripts(c, scripts)
+	}
+}
+
+// decodeScriptCourse tries each supported embedding of the course data on a
+// script and returns the course together with its JSON.
+func decodeScriptCourse(script string) (*models.Course, []byte, bool) {
+	// Plain JSON script blocks, e.g. <script type="application/json">.
+	if strings.HasPrefix(script, "{") {
+		if course, raw, ok := decodeCourseJSON(strings.NewReader(script)); ok {
+			return course, raw, true
+		}
+	}
+
+	for _, match := range jsonParseRegex.FindAllStringSubmatch(script, -1) {
+		var payload string
+		if err := json.Unmarshal([]byte(match[1]), &payload); err != nil {
+			continue
+		}
+		if course, raw, ok := decodeCourseJSON(strings.NewReader(payload)); ok {
+			return course, raw, true
+		}
+	}
+
+	for _, loc := range objectLiteralRegex.FindAllStringIndex(script, -1) {
+		if course, raw, ok := decodeCourseJSON(strings.NewReader(script[loc[1]-1:])); ok {
+			return course, raw, true
+		}
+	}
+
+	return decodeEmbeddedCourse([]byte(script))
+}
+
+// decodeCourseJSON decodes the first JSON value of r as a course and returns
+// it together with the JSON of that value. Trailing content such as the rest
+// of a script is ignored. It returns false if the value is not valid JSON or
+// does not describe a course with content.
+func decodeCourseJSON(r io.Reader) (*models.Course, []byte, bool) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, nil, false
+	}
+	var course models.Course
+	if err := json.Unmarshal(raw, &course); err != nil {
+		return nil, nil, false
+	}
+	if !hasCourseContent(&course) {
+		return nil, nil, false
+	}
+	DecodeCourseDetails(&course)
+	return &course, raw, true
+}
+
+// hasCourseContent reports whether a decoded course carries any course data,
+// which distinguishes it from unrelated JSON that happens to decode cleanly.
+func hasCourseContent(course *models.Course) bool {
+	return course.Course.ID != "" || course.Course.Title != "" || len(course.Course.Lessons) > 0
+}
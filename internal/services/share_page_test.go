@@ -0,0 +1,111 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/models"
+)
+
+// TestParseSharePage tests extracting course data from saved share pages.
+func TestParseSharePage(t *testing.T) {
+	testCourse := &models.Course{
+		ShareID: "saved-share-id",
+		Author:  "Page Author",
+		Course: models.CourseInfo{
+			ID:    "saved-course-id",
+			Title: "Saved Course",
+			Lessons: []models.Lesson{
+				{ID: "lesson-1", Title: "Offline Lesson", Type: "blocks"},
+			},
+		},
+	}
+	data, err := json.Marshal(testCourse)
+	if err != nil {
+		t.Fatalf("Failed to marshal test course: %v", err)
+	}
+	payload := string(data)
+
+	tests := []struct {
+		name string
+		page string
+	}{
+		{
+			name: "JSON script block",
+			page: `<html><head><script type="application/json" id="course-data">` + payload + `</script></head></html>`,
+		},
+		{
+			name: "object literal assignment",
+			page: `<html><head><script>var config = {"a": 1};
+window.__bootData = ` + payload + `; window.start();</script></head></html>`,
+		},
+		{
+			name: "JSON.parse string literal",
+			page: `<html><body><script>window.courseData = JSON.parse(` + strconv.Quote(payload) + `);</script></body></html>`,
+		},
+		{
+			name: "base64 deserialize call",
+			page: `<html><body><script src="bundle.js"></script><script>deserialize("` + encodeTestCourse(t, testCourse) + `")</script></body></html>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			course, err := ParseSharePage(strings.NewReader(tt.page))
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if course.Course.ID != testCourse.Course.ID {
+				t.Errorf("Expected course ID '%s', got '%s'", testCourse.Course.ID, course.Course.ID)
+			}
+			if course.ShareID != testCourse.ShareID {
+				t.Errorf("Expected ShareID '%s', got '%s'", testCourse.ShareID, course.ShareID)
+			}
+			if len(course.Course.Lessons) != 1 || course.Course.Lessons[0].Title != "Offline Lesson" {
+				t.Errorf("Expected lesson 'Offline Lesson', got %+v", course.Course.Lessons)
+			}
+		})
+	}
+}
+
+// TestParseSharePage_NoCourseData tests pages without embedded course data.
+func TestParseSharePage_NoCourseData(t *testing.T) {
+	page := `<html><head><script>var settings = {"theme": "dark"};</script></head><body>Loading...</body></html>`
+
+	_, err := ParseSharePage(strings.NewReader(page))
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "no embedded course data found") {
+		t.Errorf("Expected error to contain 'no embedded course data found', got '%s'", err.Error())
+	}
+}
+
+// TestArticulateParser_LoadCourseFromFile_SharePage tests loading a saved share page from disk.
+func TestArticulateParser_LoadCourseFromFile_SharePage(t *testing.T) {
+	tempDir := t.TempDir()
+	pagePath := filepath.Join(tempDir, "share.html")
+	page := `<html><script>window.__bootData = {"shareId":"abc","course":{"id":"page-course","title":"Page Course"}};</script></html>`
+	if err := os.WriteFile(pagePath, []byte(page), 0o644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+
+	parser := NewArticulateParser(nil, "", 0)
+
+	course, err := parser.LoadCourseFromFile(pagePath)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if course.Course.Title != "Page Course" {
+		t.Errorf("Expected title 'Page Course', got '%s'", course.Course.Title)
+	}
+
+	_, err = parser.LoadCourseFromFile(filepath.Join(tempDir, "missing.htm"))
+	if err == nil || !strings.Contains(err.Error(), "failed to read file") {
+		t.Errorf("Expected 'failed to read file' error, got %v", err)
+	}
+}
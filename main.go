@@ -1,13 +1,18 @@
 // Package main provides the entry point for the articulate-parser application.
 // This application fetches Articulate Rise courses from URLs or local files and
-// exports them to different formats such as Markdown or DOCX.
+// exports them to different formats such as Markdown, DOCX or PDF.
 package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/kjanat/articulate-parser/internal/config"
 	"github.com/kjanat/articulate-parser/internal/exporters"
@@ -38,9 +43,7 @@ func run(args []string) int {
 	}
 
 	htmlCleaner := services.NewHTMLCleaner()
-	parser := services.NewArticulateParser(logger, cfg.BaseURL, cfg.RequestTimeout)
 	exporterFactory := exporters.NewFactory(htmlCleaner)
-	app := services.NewApp(parser, exporterFactory)
 
 	// Check for version flag
 	if len(args) > 1 && (args[1] == "--version" || args[1] == "-v") {
@@ -52,23 +55,72 @@ func run(args []string) int {
 
 	// Check for help flag
 	if len(args) > 1 && (args[1] == "--help" || args[1] == "-h" || args[1] == "help") {
-		printUsage(args[0], app.SupportedFormats())
+		printUsage(args[0], exporterFactory.SupportedFormats())
 		return 0
 	}
 
+	// Apply option flags, which override the environment configuration
+	positional, err := parseFlags(cfg, args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		printUsage(args[0], exporterFactory.SupportedFormats())
+		return 1
+	}
+
+	// Check for cache management commands
+	if len(positional) > 0 && positional[0] == "cache" {
+		return runCacheCommand(cfg, positional[1:])
+	}
+
+	// Load credentials for private share links and keep them out of the logs
+	creds, err := services.LoadCredentials(cfg)
+	if err != nil {
+		logger.Error("failed to load credentials", "error", err)
+		return 1
+	}
+	logger = services.NewRedactingLogger(logger, creds.Secrets()...)
+
+	// The validate-schema command loads a course in strict mode without exporting it
+	validate := len(positional) > 0 && positional[0] == "validate-schema"
+	if validate {
+		cfg.Strict = true
+	}
+
+	// The exporter options only take effect once the flags are applied
+	exporterFactory = exporters.NewFactoryWithOptions(htmlCleaner, exporters.Options{
+		Markdown: exporters.MarkdownOptions{
+			Flavor: cfg.MarkdownFlavor,
+		},
+		HTML: exporters.HTMLOptions{
+			TemplateDir: cfg.TemplateDir,
+			Stylesheet:  cfg.Stylesheet,
+			Theme:       cfg.Theme,
+			TrustHTML:   cfg.TrustHTML,
+		},
+		Docx: exporters.DocxOptions{
+			ReferenceDoc: cfg.ReferenceDoc,
+			TOC:          cfg.TOC,
+			MediaDir:     cfg.MediaDir,
+		},
+	})
+	parser := services.NewArticulateParserFromConfig(logger, cfg, creds)
+	app := services.NewApp(parser, exporterFactory)
+
+	if validate {
+		return runValidateSchema(app, positional[1:])
+	}
+
 	// Check for required command-line arguments
-	if len(args) < 4 {
+	if len(positional) < 3 {
 		printUsage(args[0], app.SupportedFormats())
 		return 1
 	}
 
-	source := args[1]
-	format := args[2]
-	output := args[3]
-
-	var err error
+	source := positional[0]
+	format := positional[1]
+	output := positional[2]
 
-	// Determine if source is a URI or file path
+	// Determine if source is a URI or file path ("-" reads from stdin)
 	if isURI(source) {
 		err = app.ProcessCourseFromURI(context.Background(), source, format, output)
 	} else {
@@ -76,6 +128,11 @@ func run(args []string) int {
 	}
 
 	if err != nil {
+		var drift *services.SchemaDriftError
+		if errors.As(err, &drift) {
+			// Report errors are ignored: the failure is logged below either way
+			_ = services.WriteSchemaReport(os.Stderr, drift.Issues)
+		}
 		logger.Error("failed to process course", "error", err, "source", source)
 		return 1
 	}
@@ -84,6 +141,141 @@ func run(args []string) int {
 	return 0
 }
 
+// parseFlags applies the option flags in args to cfg and returns the remaining
+// positional arguments. Flags may appear before, between or after them.
+//
+// Parameters:
+//   - cfg: The configuration to update with flag values
+//   - args: The command-line arguments without the program name
+//
+// Returns:
+//   - The positional arguments in their original order
+//   - An error if a flag is unknown or has an invalid value
+func parseFlags(cfg *config.Config, args []string) ([]string, error) {
+	flags := flag.NewFlagSet("articulate-parser", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.BoolVar(&cfg.Offline, "offline", cfg.Offline, "serve courses from the cache only")
+	flags.BoolVar(&cfg.Strict, "strict", cfg.Strict, "fail on unknown fields and items in the course JSON")
+	flags.StringVar(&cfg.CacheDir, "cache-dir", cfg.CacheDir, "directory for cached course data")
+	flags.StringVar(&cfg.MarkdownFlavor, "markdown-flavor", cfg.MarkdownFlavor, "Markdown flavor: gfm, commonmark or plain")
+	flags.StringVar(&cfg.TemplateDir, "template-dir", cfg.TemplateDir, "directory of *.gohtml files overriding HTML templates")
+	flags.StringVar(&cfg.Stylesheet, "stylesheet", cfg.Stylesheet, "CSS file replacing the built-in HTML stylesheet")
+	flags.StringVar(&cfg.Theme, "theme", cfg.Theme, "HTML theme: default or course")
+	flags.BoolVar(&cfg.TrustHTML, "trust-html", cfg.TrustHTML, "embed course HTML in HTML exports without sanitizing it")
+	flags.StringVar(&cfg.ReferenceDoc, "reference-doc", cfg.ReferenceDoc, ".docx or .dotx providing the DOCX styles and page setup")
+	flags.BoolVar(&cfg.TOC, "toc", cfg.TOC, "add a table of contents to DOCX exports")
+	flags.StringVar(&cfg.MediaDir, "media-dir", cfg.MediaDir, "directory of images to embed in DOCX exports instead of downloading them")
+	flags.StringVar(&cfg.CookieFile, "cookie-file", cfg.CookieFile, "Netscape cookie file for private share links")
+	flags.Func("header", "extra request header as \"Name: value\" (repeatable)", func(value string) error {
+		cfg.Headers = append(cfg.Headers, value)
+		return nil
+	})
+
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// runCacheCommand handles the "cache list" and "cache purge [share-id]"
+// commands and returns an exit code.
+//
+// Parameters:
+//   - cfg: The configuration providing the cache directory
+//   - args: The command arguments following "cache"
+//
+// Returns:
+//   - 0 on success, 1 on failure
+func runCacheCommand(cfg *config.Config, args []string) int {
+	if cfg.CacheDir == "" {
+		fmt.Fprintln(os.Stderr, "Error: no cache directory configured (set ARTICULATE_CACHE_DIR or --cache-dir)")
+		return 1
+	}
+	cache := services.NewCourseCache(cfg.CacheDir)
+
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		entries, err := cache.List()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if len(entries) == 0 {
+			fmt.Printf("No cached courses in %s\n", cfg.CacheDir)
+			return 0
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "SHARE ID\tAPI\tSIZE\tFETCHED\tETAG")
+		for _, e := range entries {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", e.ShareID, e.BaseURL, e.Size, e.FetchedAt.Format(time.RFC3339), e.ETag)
+		}
+		// Flush errors are ignored: there is nothing useful to do if stdout is gone
+		_ = tw.Flush()
+		return 0
+	case len(args) >= 1 && len(args) <= 2 && args[0] == "purge":
+		shareID := ""
+		if len(args) == 2 {
+			shareID = args[1]
+		}
+		removed, err := cache.Purge(shareID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Removed %d cached course(s)\n", removed)
+		return 0
+	default:
+		fmt.Fprintln(os.Stderr, "Usage: cache list | cache purge [share-id]")
+		return 1
+	}
+}
+
+// runValidateSchema handles the "validate-schema <source>" command, which
+// reports fields and items of the course JSON that are not recognized.
+//
+// Parameters:
+//   - app: The application, whose parser must be in strict mode
+//   - args: The command arguments following "validate-schema"
+//
+// Returns:
+//   - 0 if the course matches the known schema, 1 otherwise
+func runValidateSchema(app *services.App, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: validate-schema <source>")
+		return 1
+	}
+	source := args[0]
+
+	var err error
+	if isURI(source) {
+		err = app.ValidateCourseFromURI(context.Background(), source)
+	} else {
+		err = app.ValidateCourseFromFile(source)
+	}
+
+	var drift *services.SchemaDriftError
+	switch {
+	case errors.As(err, &drift):
+		// Write errors are ignored: there is nothing useful to do if stdout is gone
+		_ = services.WriteSchemaReport(os.Stdout, drift.Issues)
+		return 1
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("No schema drift found in %s\n", source)
+	return 0
+}
+
 // isURI checks if a string is a URI by looking for http:// or https:// prefixes.
 //
 // Parameters:
@@ -101,11 +293,33 @@ func isURI(str string) bool {
 //   - programName: The name of the program (args[0])
 //   - supportedFormats: Slice of supported export formats
 func printUsage(programName string, supportedFormats []string) {
-	fmt.Printf("Usage: %s <source> <format> <output>\n", programName)
-	fmt.Printf("  source: URI or file path to the course\n")
+	fmt.Printf("Usage: %s [options] <source> <format> <output>\n", programName)
+	fmt.Printf("       %s [options] cache list | cache purge [share-id]\n", programName)
+	fmt.Printf("       %s [options] validate-schema <source>\n", programName)
+	fmt.Printf("  source: URI or file path to the course (JSON, Rise SCORM .zip or saved share page .html), or - for stdin\n")
 	fmt.Printf("  format: export format (%s)\n", strings.Join(supportedFormats, ", "))
-	fmt.Printf("  output: output file path\n")
+	fmt.Printf("  output: output file path, or - for stdout\n")
+	fmt.Println("\nOptions:")
+	fmt.Printf("  --cache-dir <dir>  cache fetched courses in <dir> and revalidate them on later runs\n")
+	fmt.Printf("  --offline          serve courses from the cache only, without network access\n")
+	fmt.Printf("  --cookie-file <f>  send cookies from a Netscape cookie file (e.g. exported from a browser)\n")
+	fmt.Printf("  --header <h>       send an extra request header \"Name: value\"; repeatable\n")
+	fmt.Printf("  --strict           fail when the course JSON has unknown fields or item types\n")
+	fmt.Printf("  --markdown-flavor  Markdown flavor of course text: gfm (default), commonmark, or plain without markup\n")
+	fmt.Printf("  --template-dir <d> override HTML templates such as textItem with the *.gohtml files in <d>\n")
+	fmt.Printf("  --stylesheet <f>   use the CSS file <f> instead of the built-in HTML stylesheet\n")
+	fmt.Printf("  --theme <name>     HTML theme: default, or course to use the course colour as accent\n")
+	fmt.Printf("  --trust-html       embed course HTML as is; by default scripts, event handlers and javascript: links are removed\n")
+	fmt.Printf("  --reference-doc    use the styles, headers, footers and page setup of the given .docx or .dotx\n")
+	fmt.Printf("  --toc              add a table of contents to DOCX exports\n")
+	fmt.Printf("  --media-dir <d>    embed DOCX images from <d>, matched by file name, instead of downloading them\n")
+	fmt.Printf("  Bearer tokens are read from ARTICULATE_AUTH_TOKEN so they stay out of the process list.\n")
 	fmt.Println("\nExample:")
 	fmt.Printf("  %s articulate-sample.json markdown output.md\n", programName)
 	fmt.Printf("  %s https://rise.articulate.com/share/xyz docx output.docx\n", programName)
+	fmt.Printf("  %s course-scorm12.zip html output.html\n", programName)
+	fmt.Printf("  curl -s <boot-json-url> | %s - markdown - | pandoc -o course.pdf\n", programName)
+	fmt.Printf("  %s --cache-dir ~/.cache/articulate --offline https://rise.articulate.com/share/xyz markdown output.md\n", programName)
+	fmt.Printf("  %s --theme course --template-dir ./templates articulate-sample.json html output.html\n", programName)
+	fmt.Printf("  %s --toc --reference-doc brand.dotx articulate-sample.json docx handout.docx\n", programName)
 }
@@ -5,8 +5,12 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/kjanat/articulate-parser/internal/config"
+	"github.com/kjanat/articulate-parser/internal/services"
 )
 
 // TestIsURI tests the isURI function with various input scenarios.
@@ -295,8 +299,8 @@ func TestRunWithInvalidFile(t *testing.T) {
 		t.Errorf("Expected exit code 1 for non-existent file, got %d", exitCode)
 	}
 
-	// Should have error output in structured log format
-	output := stdoutBuf.String()
+	// Should have error output in structured log format on stderr
+	output := stderrBuf.String()
 	if !strings.Contains(output, "level=ERROR") && !strings.Contains(output, "failed to process course") {
 		t.Errorf("Expected error message about processing course, got: %s", output)
 	}
@@ -348,8 +352,8 @@ func TestRunWithInvalidURI(t *testing.T) {
 		t.Errorf("Expected failure (exit code 1) for invalid URI, got %d", exitCode)
 	}
 
-	// Should have error output in structured log format
-	output := stdoutBuf.String()
+	// Should have error output in structured log format on stderr
+	output := stderrBuf.String()
 	if !strings.Contains(output, "level=ERROR") && !strings.Contains(output, "failed to process course") {
 		t.Errorf("Expected error message about processing course, got: %s", output)
 	}
@@ -405,22 +409,22 @@ func TestRunWithValidJSONFile(t *testing.T) {
 		_ = os.Remove(outputFile)
 	}()
 
-	// Save original stdout
-	originalStdout := os.Stdout
-	defer func() { os.Stdout = originalStdout }()
+	// Save original stderr, where log messages are written
+	originalStderr := os.Stderr
+	defer func() { os.Stderr = originalStderr }()
 
-	// Capture stdout
+	// Capture stderr
 	r, w, _ := os.Pipe()
-	os.Stdout = w
+	os.Stderr = w
 
 	args := []string{"articulate-parser", tmpFile.Name(), "markdown", outputFile}
 	exitCode := run(args)
 
-	// Close write end and restore stdout. Close errors are ignored: we've already
+	// Close write end and restore stderr. Close errors are ignored: we've already
 	// written the success message before closing, and any close error doesn't affect
 	// the validity of the captured output or the test assertions.
 	_ = w.Close()
-	os.Stdout = originalStdout
+	os.Stderr = originalStderr
 
 	// Read captured output. Copy errors are ignored: the success message was
 	// successfully written to the pipe, and we can verify it regardless of any
@@ -445,6 +449,301 @@ func TestRunWithValidJSONFile(t *testing.T) {
 	}
 }
 
+// TestRunWithStdio tests reading the course from stdin and writing the export to stdout.
+func TestRunWithStdio(t *testing.T) {
+	oldStdin, oldStdout, oldStderr := os.Stdin, os.Stdout, os.Stderr
+	defer func() { os.Stdin, os.Stdout, os.Stderr = oldStdin, oldStdout, oldStderr }()
+
+	stdinR, stdinW, _ := os.Pipe()
+	stdoutR, stdoutW, _ := os.Pipe()
+	stderrR, stderrW, _ := os.Pipe()
+	os.Stdin, os.Stdout, os.Stderr = stdinR, stdoutW, stderrW
+
+	// Write errors are ignored: the pipe buffer easily holds this small payload.
+	_, _ = stdinW.WriteString(`{"shareId":"piped","course":{"id":"c1","title":"Piped Course"}}`)
+	_ = stdinW.Close()
+
+	exitCode := run([]string{"articulate-parser", "-", "markdown", "-"})
+
+	_ = stdoutW.Close()
+	_ = stderrW.Close()
+	os.Stdin, os.Stdout, os.Stderr = oldStdin, oldStdout, oldStderr
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	_, _ = io.Copy(&stdoutBuf, stdoutR)
+	_, _ = io.Copy(&stderrBuf, stderrR)
+	_ = stdinR.Close()
+	_ = stdoutR.Close()
+	_ = stderrR.Close()
+
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d (stderr: %s)", exitCode, stderrBuf.String())
+	}
+
+	// The export must be the only content on stdout so it can be piped onwards
+	if !strings.HasPrefix(stdoutBuf.String(), "# Piped Course\n") {
+		t.Errorf("Expected Markdown export on stdout, got: %s", stdoutBuf.String())
+	}
+	if strings.Contains(stdoutBuf.String(), "level=") {
+		t.Errorf("Expected no log output on stdout, got: %s", stdoutBuf.String())
+	}
+	if !strings.Contains(stderrBuf.String(), "successfully exported course") {
+		t.Errorf("Expected success message on stderr, got: %s", stderrBuf.String())
+	}
+}
+
+// TestParseFlags tests parsing option flags mixed with positional arguments.
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expected      []string
+		cacheDir      string
+		offline       bool
+		headers       []string
+		cookieFile    string
+		strict        bool
+		flavor        string
+		templateDir   string
+		stylesheet    string
+		theme         string
+		trustHTML     bool
+		referenceDoc  string
+		toc           bool
+		mediaDir      string
+		expectedError string
+	}{
+		{
+			name:     "no flags",
+			args:     []string{"in.json", "markdown", "out.md"},
+			expected: []string{"in.json", "markdown", "out.md"},
+		},
+		{
+			name:     "leading flags",
+			args:     []string{"--offline", "--cache-dir", "/tmp/c", "in.json", "markdown", "out.md"},
+			expected: []string{"in.json", "markdown", "out.md"},
+			cacheDir: "/tmp/c",
+			offline:  true,
+		},
+		{
+			name:     "trailing and interleaved flags",
+			args:     []string{"-", "markdown", "-cache-dir=/tmp/d", "-", "--offline"},
+			expected: []string{"-", "markdown", "-"},
+			cacheDir: "/tmp/d",
+			offline:  true,
+		},
+		{
+			name:       "auth flags",
+			args:       []string{"--header", "X-A: 1", "in.json", "--header=X-B: 2", "--cookie-file", "c.txt", "md", "out.md"},
+			expected:   []string{"in.json", "md", "out.md"},
+			headers:    []string{"X-A: 1", "X-B: 2"},
+			cookieFile: "c.txt",
+		},
+		{
+			name:     "strict flag",
+			args:     []string{"validate-schema", "--strict", "in.json"},
+			expected: []string{"validate-schema", "in.json"},
+			strict:   true,
+		},
+		{
+			name:     "markdown flags",
+			args:     []string{"in.json", "md", "--markdown-flavor", "plain", "out.md"},
+			expected: []string{"in.json", "md", "out.md"},
+			flavor:   "plain",
+		},
+		{
+			name:        "html flags",
+			args:        []string{"in.json", "html", "--template-dir", "tpl", "--stylesheet=brand.css", "--theme", "course", "--trust-html", "out.html"},
+			expected:    []string{"in.json", "html", "out.html"},
+			templateDir: "tpl",
+			stylesheet:  "brand.css",
+			theme:       "course",
+			trustHTML:   true,
+		},
+		{
+			name:         "docx flags",
+			args:         []string{"--toc", "in.json", "docx", "--reference-doc", "brand.dotx", "--media-dir=media", "out.docx"},
+			expected:     []string{"in.json", "docx", "out.docx"},
+			referenceDoc: "brand.dotx",
+			toc:          true,
+			mediaDir:     "media",
+		},
+		{
+			name:          "unknown flag",
+			args:          []string{"--bogus", "in.json"},
+			expectedError: "flag provided but not defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			positional, err := parseFlags(cfg, tt.args)
+
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("Expected error containing '%s', got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if strings.Join(positional, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("Expected positional args %v, got %v", tt.expected, positional)
+			}
+			if cfg.CacheDir != tt.cacheDir {
+				t.Errorf("Expected cache dir '%s', got '%s'", tt.cacheDir, cfg.CacheDir)
+			}
+			if cfg.Offline != tt.offline {
+				t.Errorf("Expected offline %v, got %v", tt.offline, cfg.Offline)
+			}
+			if strings.Join(cfg.Headers, "|") != strings.Join(tt.headers, "|") {
+				t.Errorf("Expected headers %q, got %q", tt.headers, cfg.Headers)
+			}
+			if cfg.CookieFile != tt.cookieFile {
+				t.Errorf("Expected cookie file '%s', got '%s'", tt.cookieFile, cfg.CookieFile)
+			}
+			if cfg.Strict != tt.strict {
+				t.Errorf("Expected strict %v, got %v", tt.strict, cfg.Strict)
+			}
+			if cfg.MarkdownFlavor != tt.flavor {
+				t.Errorf("Expected Markdown flavor '%s', got '%s'", tt.flavor, cfg.MarkdownFlavor)
+			}
+			if cfg.TemplateDir != tt.templateDir || cfg.Stylesheet != tt.stylesheet || cfg.Theme != tt.theme {
+				t.Errorf("Expected template dir '%s', stylesheet '%s' and theme '%s', got '%s', '%s' and '%s'",
+					tt.templateDir, tt.stylesheet, tt.theme, cfg.TemplateDir, cfg.Stylesheet, cfg.Theme)
+			}
+			if cfg.TrustHTML != tt.trustHTML {
+				t.Errorf("Expected trust HTML %v, got %v", tt.trustHTML, cfg.TrustHTML)
+			}
+			if cfg.ReferenceDoc != tt.referenceDoc {
+				t.Errorf("Expected reference document '%s', got '%s'", tt.referenceDoc, cfg.ReferenceDoc)
+			}
+			if cfg.TOC != tt.toc {
+				t.Errorf("Expected TOC %v, got %v", tt.toc, cfg.TOC)
+			}
+			if cfg.MediaDir != tt.mediaDir {
+				t.Errorf("Expected media directory '%s', got '%s'", tt.mediaDir, cfg.MediaDir)
+			}
+		})
+	}
+}
+
+// TestRunValidateSchema tests the validate-schema command and strict exports.
+func TestRunValidateSchema(t *testing.T) {
+	dir := t.TempDir()
+	writeCourse := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write course: %v", err)
+		}
+		return path
+	}
+	clean := writeCourse("clean.json", `{"course":{"id":"c","lessons":[{"id":"l","items":[{"type":"text"}]}]}}`)
+	drifted := writeCourse("drifted.json", `{"course":{"id":"c","lessons":[{"id":"l","items":[{"id":"i","type":"timeline"}]}]}}`)
+
+	// runCaptured runs the program and returns its exit code and stdout.
+	runCaptured := func(args ...string) (int, string) {
+		oldStdout, oldStderr := os.Stdout, os.Stderr
+		r, w, _ := os.Pipe()
+		os.Stdout, os.Stderr = w, w
+
+		exitCode := run(append([]string{"articulate-parser"}, args...))
+
+		// Close and copy errors are ignored: the output has been fully written.
+		_ = w.Close()
+		os.Stdout, os.Stderr = oldStdout, oldStderr
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		_ = r.Close()
+		return exitCode, buf.String()
+	}
+
+	if exitCode, output := runCaptured("validate-schema", clean); exitCode != 0 || !strings.Contains(output, "No schema drift") {
+		t.Errorf("Expected clean course to pass, got exit code %d and output: %s", exitCode, output)
+	}
+
+	exitCode, output := runCaptured("validate-schema", drifted)
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1 for drifted course, got %d", exitCode)
+	}
+	if !strings.Contains(output, `course.lessons[0].items[0] (lesson l, item i): unknown item type "timeline"`) {
+		t.Errorf("Expected report with item location, got: %s", output)
+	}
+
+	outputFile := filepath.Join(dir, "out.md")
+	if exitCode, _ := runCaptured(drifted, "markdown", outputFile); exitCode != 0 {
+		t.Errorf("Expected drift to be ignored without --strict, got exit code %d", exitCode)
+	}
+	if exitCode, output := runCaptured("--strict", drifted, "markdown", outputFile); exitCode != 1 || !strings.Contains(output, "1 schema issue(s) found") {
+		t.Errorf("Expected strict export to fail with a report, got exit code %d and output: %s", exitCode, output)
+	}
+
+	if exitCode, _ := runCaptured("validate-schema"); exitCode != 1 {
+		t.Errorf("Expected exit code 1 without a source, got %d", exitCode)
+	}
+}
+
+// TestRunCacheCommand tests the cache list and purge commands.
+func TestRunCacheCommand(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("ARTICULATE_CACHE_DIR", cacheDir)
+
+	cache := services.NewCourseCache(cacheDir)
+	for _, id := range []string{"first-id", "second-id"} {
+		if err := cache.Store(&services.CacheEntry{ShareID: id, BaseURL: "https://rise.articulate.com", ETag: `"etag-` + id + `"`}, []byte("{}")); err != nil {
+			t.Fatalf("Failed to populate cache: %v", err)
+		}
+	}
+
+	// runCaptured runs the program and returns its exit code and stdout.
+	runCaptured := func(args ...string) (int, string) {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		exitCode := run(append([]string{"articulate-parser"}, args...))
+
+		// Close and copy errors are ignored: the output has been fully written.
+		_ = w.Close()
+		os.Stdout = oldStdout
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		_ = r.Close()
+		return exitCode, buf.String()
+	}
+
+	exitCode, output := runCaptured("cache", "list")
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	for _, expected := range []string{"SHARE ID", "first-id", "second-id", "https://rise.articulate.com", `"etag-first-id"`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected cache list to contain %q, got: %s", expected, output)
+		}
+	}
+
+	if exitCode, output = runCaptured("cache", "purge", "first-id"); exitCode != 0 || !strings.Contains(output, "Removed 1") {
+		t.Errorf("Expected one entry purged, got exit code %d and output: %s", exitCode, output)
+	}
+	if exitCode, output = runCaptured("--cache-dir", cacheDir, "cache", "purge"); exitCode != 0 || !strings.Contains(output, "Removed 1") {
+		t.Errorf("Expected remaining entry purged, got exit code %d and output: %s", exitCode, output)
+	}
+	if _, output = runCaptured("cache", "list"); !strings.Contains(output, "No cached courses") {
+		t.Errorf("Expected empty cache listing, got: %s", output)
+	}
+
+	if exitCode, _ = runCaptured("cache", "unknown"); exitCode != 1 {
+		t.Errorf("Expected exit code 1 for unknown cache command, got %d", exitCode)
+	}
+
+	t.Setenv("ARTICULATE_CACHE_DIR", "")
+	if exitCode, _ = runCaptured("cache", "list"); exitCode != 1 {
+		t.Errorf("Expected exit code 1 without cache directory, got %d", exitCode)
+	}
+}
+
 // TestRunIntegration tests the run function with different output formats using sample file.
 func TestRunIntegration(t *testing.T) {
 	// Skip if sample file doesn't exist